@@ -14,6 +14,7 @@
 package model
 
 import (
+	"strings"
 	"github.com/perses/shared/cue/common"
 	commonProxy "github.com/perses/shared/cue/common/proxy"
 )
@@ -23,6 +24,28 @@ import (
 kind: #kind
 spec: {
 	commonProxy.#baseHTTPDatasourceSpec
+	database?: strings.MinRunes(1)
+	// the ClickHouse interface the URL points to, the Perses panels only query the http one
+	protocol?: "http" | "native"
+	// requires TLS, so the URL must use https
+	secure?: bool
+	if secure != _|_ && secure {
+		directUrl?: =~"^https://"
+		proxy?: spec: url: =~"^https://"
+	}
+	// allows ClickHouse to compress its responses, with the codec negotiated by the browser
+	compression?: bool
+	settings?:    #settings
+}
+
+// max_execution_time is a whole number of seconds, so there are no milliseconds
+#durationRegex: "^(\\d+y)?(\\d+w)?(\\d+d)?(\\d+h)?(\\d+m)?(\\d+s)?$"
+
+// ClickHouse settings sent along with every query.
+#settings: {
+	maxExecutionTime?: =~#durationRegex & !=""
+	maxResultRows?:    int & >=0
+	readonly?:         0 | 1 | 2
 }
 
 #selector: common.#datasourceSelector & {_kind: #kind}
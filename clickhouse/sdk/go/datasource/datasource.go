@@ -16,18 +16,70 @@ package datasource
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/perses/perses/go-sdk/datasource"
+	"github.com/perses/perses/pkg/model/api/v1/common"
 	"github.com/perses/perses/pkg/model/api/v1/datasource/http"
+	"github.com/perses/plugins/sdk/go/option"
 )
 
 const (
 	PluginKind = "ClickHouseDatasource"
 )
 
+// Protocol is the ClickHouse interface the URL points to.
+type Protocol string
+
+const (
+	// HTTPProtocol is the HTTP interface (port 8123, or 8443 with TLS), the one queried by the Perses panels.
+	HTTPProtocol Protocol = "http"
+	// NativeProtocol is the native TCP interface (port 9000, or 9440 with TLS). Browsers cannot speak it, so it is
+	// only meant for the tools reading the datasource: the Perses panels report an error for it.
+	NativeProtocol Protocol = "native"
+)
+
+// ReadonlyMode maps the ClickHouse `readonly` setting.
+type ReadonlyMode uint
+
+const (
+	// ReadWriteMode allows every query.
+	ReadWriteMode ReadonlyMode = 0
+	// ReadonlyStrictMode only allows read queries and forbids changing settings.
+	ReadonlyStrictMode ReadonlyMode = 1
+	// ReadonlySettingsMode only allows read queries but settings can be changed.
+	ReadonlySettingsMode ReadonlyMode = 2
+)
+
+// QuerySettings are ClickHouse settings sent along with every query.
+type QuerySettings struct {
+	// MaxExecutionTime is sent as `max_execution_time`, a whole number of seconds.
+	MaxExecutionTime common.Duration `json:"maxExecutionTime,omitempty" yaml:"maxExecutionTime,omitempty"`
+	// MaxResultRows is sent as `max_result_rows`.
+	MaxResultRows uint64 `json:"maxResultRows,omitempty" yaml:"maxResultRows,omitempty"`
+	// Readonly is sent as `readonly` when set. It is a pointer so that ReadWriteMode (0) is kept.
+	Readonly *ReadonlyMode `json:"readonly,omitempty" yaml:"readonly,omitempty"`
+}
+
+func (s *QuerySettings) validate() error {
+	if s.MaxExecutionTime < 0 || time.Duration(s.MaxExecutionTime)%time.Second != 0 {
+		return fmt.Errorf("maxExecutionTime must be a whole number of seconds, got %s", s.MaxExecutionTime)
+	}
+	if s.Readonly != nil && *s.Readonly > ReadonlySettingsMode {
+		return fmt.Errorf("readonly must be 0, 1 or 2, got %d", *s.Readonly)
+	}
+	return nil
+}
+
 type PluginSpec struct {
-	DirectURL string      `json:"directUrl,omitempty" yaml:"directUrl,omitempty"`
-	Proxy     *http.Proxy `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+	DirectURL   string         `json:"directUrl,omitempty" yaml:"directUrl,omitempty"`
+	Proxy       *http.Proxy    `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+	Database    string         `json:"database,omitempty" yaml:"database,omitempty"`
+	Protocol    Protocol       `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+	Secure      bool           `json:"secure,omitempty" yaml:"secure,omitempty"`
+	Compression bool           `json:"compression,omitempty" yaml:"compression,omitempty"`
+	Settings    *QuerySettings `json:"settings,omitempty" yaml:"settings,omitempty"`
 }
 
 func (s *PluginSpec) UnmarshalJSON(data []byte) error {
@@ -63,6 +115,29 @@ func (s *PluginSpec) validate() error {
 	if len(s.DirectURL) > 0 && s.Proxy != nil {
 		return fmt.Errorf("at most directUrl or proxy must be configured")
 	}
+	return s.validateSettings()
+}
+
+// validateSettings checks the settings and that the URL uses TLS when it is required.
+func (s *PluginSpec) validateSettings() error {
+	switch s.Protocol {
+	case "", HTTPProtocol, NativeProtocol:
+	default:
+		return fmt.Errorf("unknown protocol %q", s.Protocol)
+	}
+	if s.Secure {
+		if len(s.DirectURL) > 0 && !strings.HasPrefix(s.DirectURL, "https://") {
+			return fmt.Errorf("secure is enabled but directUrl %q is not using https", s.DirectURL)
+		}
+		if s.Proxy != nil && s.Proxy.Spec.URL != nil && s.Proxy.Spec.URL.URL != nil && s.Proxy.Spec.URL.Scheme != "https" {
+			return fmt.Errorf("secure is enabled but the proxy url %q is not using https", s.Proxy.Spec.URL.String())
+		}
+	}
+	if s.Settings != nil {
+		if err := s.Settings.validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -75,10 +150,8 @@ func create(options ...Option) (Builder, error) {
 
 	var defaults []Option
 
-	for _, opt := range append(defaults, options...) {
-		if err := opt(builder); err != nil {
-			return *builder, err
-		}
+	if err := option.ApplyAndValidate(PluginKind, builder, append(defaults, options...), builder.validateSettings); err != nil {
+		return *builder, err
 	}
 
 	return *builder, nil
@@ -88,6 +161,13 @@ type Builder struct {
 	PluginSpec `json:",inline" yaml:",inline"`
 }
 
+func (b *Builder) settings() *QuerySettings {
+	if b.Settings == nil {
+		b.Settings = &QuerySettings{}
+	}
+	return b.Settings
+}
+
 func ClickHouse(options ...Option) datasource.Option {
 	return func(builder *datasource.Builder) error {
 		plugin, err := create(options...)
@@ -14,7 +14,10 @@
 package datasource
 
 import (
+	"time"
+
 	"github.com/perses/perses/go-sdk/http"
+	"github.com/perses/perses/pkg/model/api/v1/common"
 )
 
 func DirectURL(url string) Option {
@@ -34,3 +37,75 @@ func HTTPProxy(url string, options ...http.Option) Option {
 		return nil
 	}
 }
+
+func Database(database string) Option {
+	return func(builder *Builder) error {
+		builder.Database = database
+		return nil
+	}
+}
+
+func WithProtocol(protocol Protocol) Option {
+	return func(builder *Builder) error {
+		builder.Protocol = protocol
+		return nil
+	}
+}
+
+func HTTP() Option {
+	return WithProtocol(HTTPProtocol)
+}
+
+func Native() Option {
+	return WithProtocol(NativeProtocol)
+}
+
+// Secure requires the connection to ClickHouse to use TLS: the URL must use https.
+func Secure(secure bool) Option {
+	return func(builder *Builder) error {
+		builder.Secure = secure
+		return nil
+	}
+}
+
+// WithCompression allows ClickHouse to compress its responses (`enable_http_compression`). The codec is the one
+// negotiated by the browser, which sets the Accept-Encoding header itself.
+func WithCompression(enabled bool) Option {
+	return func(builder *Builder) error {
+		builder.Compression = enabled
+		return nil
+	}
+}
+
+func WithSettings(settings QuerySettings) Option {
+	return func(builder *Builder) error {
+		if err := settings.validate(); err != nil {
+			return err
+		}
+		builder.Settings = &settings
+		return nil
+	}
+}
+
+func MaxExecutionTime(duration time.Duration) Option {
+	return func(builder *Builder) error {
+		settings := builder.settings()
+		settings.MaxExecutionTime = common.Duration(duration)
+		return settings.validate()
+	}
+}
+
+func MaxResultRows(rows uint64) Option {
+	return func(builder *Builder) error {
+		builder.settings().MaxResultRows = rows
+		return nil
+	}
+}
+
+func Readonly(mode ReadonlyMode) Option {
+	return func(builder *Builder) error {
+		settings := builder.settings()
+		settings.Readonly = &mode
+		return settings.validate()
+	}
+}
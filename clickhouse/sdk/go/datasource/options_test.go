@@ -0,0 +1,172 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasource
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClickHouseOptions(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		options  []Option
+		expected string
+	}{
+		{
+			title: "database, compression and settings",
+			options: []Option{
+				DirectURL("https://clickhouse.example.com:8443"),
+				HTTP(),
+				Secure(true),
+				Database("logs"),
+				WithCompression(true),
+				MaxExecutionTime(30 * time.Second),
+				MaxResultRows(100000),
+				Readonly(ReadonlyStrictMode),
+			},
+			expected: `{"directUrl":"https://clickhouse.example.com:8443","database":"logs","protocol":"http","secure":true,"compression":true,"settings":{"maxExecutionTime":"30s","maxResultRows":100000,"readonly":1}}`,
+		},
+		{
+			title: "native protocol over TLS",
+			options: []Option{
+				HTTPProxy("https://clickhouse.example.com:9440"),
+				Native(),
+				Secure(true),
+			},
+			expected: `{"proxy":{"kind":"HTTPProxy","spec":{"url":"https://clickhouse.example.com:9440"}},"protocol":"native","secure":true}`,
+		},
+		{
+			title:    "read-write mode is kept",
+			options:  []Option{DirectURL("http://localhost:8123"), Readonly(ReadWriteMode)},
+			expected: `{"directUrl":"http://localhost:8123","settings":{"readonly":0}}`,
+		},
+		{
+			title: "settings at once",
+			options: []Option{
+				HTTPProxy("http://localhost:8123"),
+				WithSettings(QuerySettings{MaxResultRows: 10}),
+			},
+			expected: `{"proxy":{"kind":"HTTPProxy","spec":{"url":"http://localhost:8123"}},"settings":{"maxResultRows":10}}`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			builder, err := create(test.options...)
+			if err != nil {
+				t.Fatalf("create failed: %v", err)
+			}
+			jsonBytes, err := json.Marshal(builder.PluginSpec)
+			if err != nil {
+				t.Fatalf("Failed to marshal spec: %v", err)
+			}
+			if string(jsonBytes) != test.expected {
+				t.Errorf("Expected %s, got %s", test.expected, jsonBytes)
+			}
+			// the built spec must be accepted when decoded
+			var spec PluginSpec
+			if err := json.Unmarshal(jsonBytes, &spec); err != nil {
+				t.Errorf("Failed to unmarshal the built spec: %v", err)
+			}
+		})
+	}
+}
+
+func TestClickHouseOptions_Invalid(t *testing.T) {
+	testSuites := []struct {
+		title   string
+		options []Option
+		err     string
+	}{
+		{
+			title:   "sub-second max execution time",
+			options: []Option{MaxExecutionTime(500 * time.Millisecond)},
+			err:     "maxExecutionTime must be a whole number of seconds",
+		},
+		{
+			title:   "max execution time with milliseconds",
+			options: []Option{MaxExecutionTime(1500 * time.Millisecond)},
+			err:     "maxExecutionTime must be a whole number of seconds",
+		},
+		{
+			title:   "unknown readonly mode",
+			options: []Option{Readonly(3)},
+			err:     "readonly must be 0, 1 or 2, got 3",
+		},
+		{
+			title:   "unknown protocol",
+			options: []Option{WithProtocol("grpc")},
+			err:     `unknown protocol "grpc"`,
+		},
+		{
+			title:   "secure with a http directUrl",
+			options: []Option{DirectURL("http://localhost:8123"), Secure(true)},
+			err:     `secure is enabled but directUrl "http://localhost:8123" is not using https`,
+		},
+		{
+			title:   "secure with a http proxy",
+			options: []Option{HTTPProxy("http://localhost:8123"), Secure(true)},
+			err:     `secure is enabled but the proxy url "http://localhost:8123" is not using https`,
+		},
+		{
+			title:   "invalid settings",
+			options: []Option{WithSettings(QuerySettings{MaxExecutionTime: -1})},
+			err:     "maxExecutionTime must be a whole number of seconds",
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			_, err := create(test.options...)
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if !strings.Contains(err.Error(), test.err) {
+				t.Errorf("Expected error containing %q, got %q", test.err, err.Error())
+			}
+		})
+	}
+}
+
+func TestUnmarshal_Invalid(t *testing.T) {
+	testSuites := []struct {
+		title string
+		json  string
+		err   string
+	}{
+		{
+			title: "no url",
+			json:  `{"database":"logs"}`,
+			err:   "directUrl or proxy cannot be empty",
+		},
+		{
+			title: "sub-second max execution time",
+			json:  `{"directUrl":"http://localhost:8123","settings":{"maxExecutionTime":"500ms"}}`,
+			err:   "maxExecutionTime must be a whole number of seconds",
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			var spec PluginSpec
+			err := json.Unmarshal([]byte(test.json), &spec)
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if !strings.Contains(err.Error(), test.err) {
+				t.Errorf("Expected error containing %q, got %q", test.err, err.Error())
+			}
+		})
+	}
+}
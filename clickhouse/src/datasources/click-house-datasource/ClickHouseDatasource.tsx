@@ -20,9 +20,18 @@ const createClient: DatasourcePlugin<ClickHouseDatasourceSpec, ClickHouseDatasou
   spec,
   options
 ) => {
-  const { directUrl, proxy } = spec;
+  const { directUrl, proxy, database, protocol, secure, compression, settings } = spec;
   const { proxyUrl } = options;
 
+  if (protocol === 'native') {
+    throw new Error(
+      'ClickHouseDatasource uses the native protocol, which browsers cannot speak. Configure the URL of the HTTP interface with the "http" protocol to query it from the panels.'
+    );
+  }
+  if (secure && directUrl !== undefined && !directUrl.startsWith('https://')) {
+    throw new Error(`ClickHouseDatasource requires TLS but its directUrl ${directUrl} is not using https.`);
+  }
+
   const datasourceUrl = directUrl ?? proxyUrl;
   if (datasourceUrl === undefined) {
     throw new Error(
@@ -35,8 +44,12 @@ const createClient: DatasourcePlugin<ClickHouseDatasourceSpec, ClickHouseDatasou
   return {
     options: {
       datasourceUrl,
+      database,
+      compression,
+      settings,
     },
-    query: (params, headers) => query(params, { datasourceUrl, headers: headers ?? specHeaders }),
+    query: (params, headers) =>
+      query({ ...params, database }, { datasourceUrl, headers: headers ?? specHeaders, compression, settings }),
   };
 };
 
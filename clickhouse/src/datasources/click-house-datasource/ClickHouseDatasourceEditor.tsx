@@ -37,6 +37,11 @@ export function ClickHouseDatasourceEditor(props: ClickHouseDatasourceEditorProp
             endpointPattern: '/',
             method: 'GET',
           },
+          {
+            // queries are sent with POST when the datasource sets readonly
+            endpointPattern: '/',
+            method: 'POST',
+          },
         ],
         url: '',
       },
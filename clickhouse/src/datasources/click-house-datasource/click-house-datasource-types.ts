@@ -14,9 +14,25 @@
 import { HTTPProxy, RequestHeaders } from '@perses-dev/core';
 import { DatasourceClient } from '@perses-dev/plugin-system';
 
+// the ClickHouse interface the URL points to, the panels only query the http one
+export type ClickHouseProtocol = 'http' | 'native';
+
+export interface ClickHouseQuerySettings {
+  maxExecutionTime?: string;
+  maxResultRows?: number;
+  readonly?: 0 | 1 | 2;
+}
+
 export interface ClickHouseDatasourceSpec {
   directUrl?: string;
   proxy?: HTTPProxy;
+  database?: string;
+  protocol?: ClickHouseProtocol;
+  // requires TLS, so the URL must use https
+  secure?: boolean;
+  // allows ClickHouse to compress its responses, with the codec negotiated by the browser
+  compression?: boolean;
+  settings?: ClickHouseQuerySettings;
 }
 
 interface QueryRequestParameters extends Record<string, string> {
@@ -28,6 +44,9 @@ interface QueryRequestParameters extends Record<string, string> {
 interface ClickHouseDatasourceClientOptions {
   datasourceUrl: string;
   headers?: RequestHeaders;
+  database?: string;
+  compression?: boolean;
+  settings?: ClickHouseQuerySettings;
 }
 
 export interface ClickHouseDatasourceResponse {
@@ -11,7 +11,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-import { RequestHeaders } from '@perses-dev/core';
+import { RequestHeaders, parseDurationString } from '@perses-dev/core';
+import { ClickHouseQuerySettings } from '../datasources/click-house-datasource/click-house-datasource-types';
 
 export interface ClickHouseQueryParams {
   query: string;
@@ -21,6 +22,8 @@ export interface ClickHouseQueryParams {
 export interface ClickHouseQueryOptions {
   datasourceUrl: string;
   headers?: RequestHeaders;
+  compression?: boolean;
+  settings?: ClickHouseQuerySettings;
 }
 
 export interface ClickHouseQueryResponse {
@@ -36,7 +39,7 @@ export async function query(
   params: ClickHouseQueryParams,
   queryOptions: ClickHouseQueryOptions
 ): Promise<ClickHouseQueryResponse> {
-  const { datasourceUrl, headers } = queryOptions;
+  const { datasourceUrl, headers, compression, settings } = queryOptions;
 
   const url = urlBuilder(datasourceUrl);
   if (!params.query) {
@@ -48,14 +51,27 @@ export async function query(
     finalQuery += ' FORMAT JSON';
   }
 
-  url.searchParams.set('query', finalQuery);
   url.searchParams.set('database', params.database || 'default');
+  setQuerySettings(url, settings);
+  if (compression) {
+    // The browser negotiates the codec itself (Accept-Encoding can't be set by a script), ClickHouse only has to be
+    // allowed to compress the response.
+    url.searchParams.set('enable_http_compression', '1');
+  }
+
+  // ClickHouse forces readonly=2 on GET requests and refuses to change it, so the query is sent in the body of a POST
+  // request when the datasource sets readonly.
+  const usePost = settings?.readonly !== undefined;
+  if (!usePost) {
+    url.searchParams.set('query', finalQuery);
+  }
 
-  const init = {
-    method: 'GET',
+  const init: RequestInit = {
+    method: usePost ? 'POST' : 'GET',
     headers: {
       ...headers,
     },
+    body: usePost ? finalQuery : undefined,
   };
 
   try {
@@ -81,6 +97,25 @@ export async function query(
   }
 }
 
+function setQuerySettings(url: URL, settings?: ClickHouseQuerySettings): void {
+  if (settings === undefined) {
+    return;
+  }
+  if (settings.maxExecutionTime !== undefined) {
+    const { seconds = 0, minutes = 0, hours = 0, days = 0, weeks = 0, years = 0 } = parseDurationString(
+      settings.maxExecutionTime
+    );
+    const totalSeconds = seconds + 60 * (minutes + 60 * (hours + 24 * (days + 7 * weeks + 365 * years)));
+    url.searchParams.set('max_execution_time', Math.max(1, Math.round(totalSeconds)).toString());
+  }
+  if (settings.maxResultRows !== undefined) {
+    url.searchParams.set('max_result_rows', settings.maxResultRows.toString());
+  }
+  if (settings.readonly !== undefined) {
+    url.searchParams.set('readonly', settings.readonly.toString());
+  }
+}
+
 function urlBuilder(datasourceUrl: string): URL {
   if (datasourceUrl.startsWith('http://') || datasourceUrl.startsWith('https://')) {
     return new URL(datasourceUrl);
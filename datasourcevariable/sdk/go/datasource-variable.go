@@ -15,6 +15,8 @@ package datasourcevariable
 
 import (
 	listvariable "github.com/perses/perses/go-sdk/variable/list-variable"
+	"github.com/perses/plugins/sdk/go/option"
+	"github.com/perses/plugins/sdk/go/selector"
 )
 
 type PluginSpec struct {
@@ -36,10 +38,10 @@ func create(datasourcePluginKind string, options ...Option) (Builder, error) {
 		DatasourcePluginKind(datasourcePluginKind),
 	}
 
-	for _, opt := range append(defaults, options...) {
-		if err := opt(builder); err != nil {
-			return *builder, err
-		}
+	if err := option.ApplyAndValidate(PluginKind, builder, append(defaults, options...), func() error {
+		return selector.CheckDatasourcePluginKind(builder.DatasourcePluginKind)
+	}); err != nil {
+		return *builder, err
 	}
 
 	return *builder, nil
@@ -22,10 +22,25 @@ spec: close({
 	calculation: common.#calculation
 	format?:     common.#format
 	thresholds?: common.#thresholds
+	min?:        number // start value of the gauge, 0 by default
 	max?:        number // determines end value of last threshold color segment when unit is not a percent
-	legend?:     #legend
+	if min != _|_ && max != _|_ {
+		max: >min
+	}
+	legend?:  #legend
+	layout?:  #layout
+	display?: "arc" | "needle" // arc by default
 })
 
+// one gauge is drawn per series
+#layout: {
+	mode: "row" | "grid"
+	if mode == "grid" {
+		columns?: int & >0 // computed from the number of series by default
+	}
+	seriesNameFormat?: string // template like "{{instance}}" replaced by the labels of the series
+}
+
 #legend: {
 	show?: bool | *true
 }
@@ -52,6 +52,9 @@ spec: {
 		}
 	}
 
+	if #panel.fieldConfig.defaults.min != _|_ {
+		min: #panel.fieldConfig.defaults.min
+	}
 	if #panel.fieldConfig.defaults.max != _|_ {
 		max: #panel.fieldConfig.defaults.max
 	}
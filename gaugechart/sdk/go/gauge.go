@@ -14,24 +14,84 @@
 package gauge
 
 import (
+	"fmt"
+
 	"github.com/perses/perses/go-sdk/common"
 	"github.com/perses/perses/go-sdk/panel"
+	"github.com/perses/plugins/sdk/go/option"
 )
 
 const PluginKind = "GaugeChart"
 
+// DisplayMode is how the value is drawn on a gauge.
+type DisplayMode string
+
+const (
+	// ArcDisplay fills an arc up to the value.
+	ArcDisplay DisplayMode = "arc"
+	// NeedleDisplay points a needle at the value over the threshold colors.
+	NeedleDisplay DisplayMode = "needle"
+)
+
+// LayoutMode is how the gauges of several series are laid out.
+type LayoutMode string
+
+const (
+	RowLayout  LayoutMode = "row"
+	GridLayout LayoutMode = "grid"
+)
+
 type PluginSpec struct {
 	Calculation common.Calculation `json:"calculation" yaml:"calculation"`
 	Format      *common.Format     `json:"format,omitempty" yaml:"format,omitempty"`
 	Thresholds  *common.Thresholds `json:"thresholds,omitempty" yaml:"thresholds,omitempty"`
-	Max         float64            `json:"max,omitempty" yaml:"max,omitempty"`
+	Min         float64            `json:"min,omitempty" yaml:"min,omitempty"`
+	Max         *float64           `json:"max,omitempty" yaml:"max,omitempty"`
 	Legend      *LegendSpec        `json:"legend,omitempty" yaml:"legend,omitempty"`
+	Layout      *LayoutSpec        `json:"layout,omitempty" yaml:"layout,omitempty"`
+	Display     DisplayMode        `json:"display,omitempty" yaml:"display,omitempty"`
+}
+
+func (s *PluginSpec) validate() error {
+	if s.Max != nil && *s.Max <= s.Min {
+		return fmt.Errorf("max (%g) must be greater than min (%g)", *s.Max, s.Min)
+	}
+	switch s.Display {
+	case "", ArcDisplay, NeedleDisplay:
+	default:
+		return fmt.Errorf("unknown display %q", s.Display)
+	}
+	if s.Layout != nil {
+		return s.Layout.validate()
+	}
+	return nil
 }
 
 type LegendSpec struct {
 	Show bool `json:"show" yaml:"show"`
 }
 
+// LayoutSpec lays out one gauge per series, in a row or in a grid of Columns columns. The gauges are labeled with
+// SeriesNameFormat when it is set, a template like "{{instance}}" replaced by the labels of the series.
+type LayoutSpec struct {
+	Mode             LayoutMode `json:"mode" yaml:"mode"`
+	Columns          uint       `json:"columns,omitempty" yaml:"columns,omitempty"`
+	SeriesNameFormat string     `json:"seriesNameFormat,omitempty" yaml:"seriesNameFormat,omitempty"`
+}
+
+func (l *LayoutSpec) validate() error {
+	switch l.Mode {
+	case RowLayout:
+		if l.Columns > 0 {
+			return fmt.Errorf("the columns of the layout only apply to the %s mode", GridLayout)
+		}
+	case GridLayout:
+	default:
+		return fmt.Errorf("unknown layout mode %q", l.Mode)
+	}
+	return nil
+}
+
 type Option func(plugin *Builder) error
 
 type Builder struct {
@@ -47,10 +107,8 @@ func create(options ...Option) (Builder, error) {
 		Calculation(common.LastCalculation),
 	}
 
-	for _, opt := range append(defaults, options...) {
-		if err := opt(builder); err != nil {
-			return *builder, err
-		}
+	if err := option.ApplyAndValidate(PluginKind, builder, append(defaults, options...), builder.validate); err != nil {
+		return *builder, err
 	}
 
 	return *builder, nil
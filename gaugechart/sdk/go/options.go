@@ -38,9 +38,16 @@ func Thresholds(thresholds common.Thresholds) Option {
 	}
 }
 
+func Min(min float64) Option { // nolint: revive
+	return func(builder *Builder) error {
+		builder.Min = min
+		return nil
+	}
+}
+
 func Max(max float64) Option { // nolint: revive
 	return func(builder *Builder) error {
-		builder.Max = max
+		builder.Max = &max
 		return nil
 	}
 }
@@ -51,3 +58,28 @@ func Legend(legend LegendSpec) Option {
 		return nil
 	}
 }
+
+func Display(display DisplayMode) Option {
+	return func(builder *Builder) error {
+		builder.Display = display
+		return nil
+	}
+}
+
+func Layout(layout LayoutSpec) Option {
+	return func(builder *Builder) error {
+		builder.Layout = &layout
+		return nil
+	}
+}
+
+// WithRowLayout lays out the gauges in a row, labeled with the seriesNameFormat template when it is not empty.
+func WithRowLayout(seriesNameFormat string) Option {
+	return Layout(LayoutSpec{Mode: RowLayout, SeriesNameFormat: seriesNameFormat})
+}
+
+// WithGridLayout lays out the gauges in a grid of columns, labeled with the seriesNameFormat template when it is not
+// empty. The number of columns is computed from the number of series when columns is 0.
+func WithGridLayout(columns uint, seriesNameFormat string) Option {
+	return Layout(LayoutSpec{Mode: GridLayout, Columns: columns, SeriesNameFormat: seriesNameFormat})
+}
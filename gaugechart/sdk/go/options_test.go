@@ -0,0 +1,128 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gauge
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/perses/perses/go-sdk/common"
+)
+
+var percentUnit = string(common.PercentUnit)
+
+func TestMinLayoutAndDisplay(t *testing.T) {
+	builder, err := create(
+		Min(-20),
+		Max(80),
+		Display(NeedleDisplay),
+		WithGridLayout(3, "{{instance}}"),
+	)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	jsonBytes, err := json.Marshal(builder.PluginSpec)
+	if err != nil {
+		t.Fatalf("Failed to marshal spec: %v", err)
+	}
+	expected := `{"calculation":"last","min":-20,"max":80,"layout":{"mode":"grid","columns":3,"seriesNameFormat":"{{instance}}"},"display":"needle"}`
+	if string(jsonBytes) != expected {
+		t.Errorf("Expected %s, got %s", expected, jsonBytes)
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		options  []Option
+		expected string
+	}{
+		{
+			title:    "negative range up to 0",
+			options:  []Option{Min(-40), Max(0)},
+			expected: `{"calculation":"last","min":-40,"max":0}`,
+		},
+		{
+			title:    "min without max",
+			options:  []Option{Min(5)},
+			expected: `{"calculation":"last","min":5}`,
+		},
+		{
+			title:    "min without max with the percent unit",
+			options:  []Option{Format(common.Format{Unit: &percentUnit}), Min(150)},
+			expected: `{"calculation":"last","format":{"unit":"percent"},"min":150}`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			builder, err := create(test.options...)
+			if err != nil {
+				t.Fatalf("create failed: %v", err)
+			}
+			jsonBytes, err := json.Marshal(builder.PluginSpec)
+			if err != nil {
+				t.Fatalf("Failed to marshal spec: %v", err)
+			}
+			if string(jsonBytes) != test.expected {
+				t.Errorf("Expected %s, got %s", test.expected, jsonBytes)
+			}
+		})
+	}
+}
+
+func TestMinLayoutAndDisplay_Invalid(t *testing.T) {
+	testSuites := []struct {
+		title   string
+		options []Option
+		err     string
+	}{
+		{
+			title:   "max lower than min",
+			options: []Option{Min(50), Max(10)},
+			err:     "max (10) must be greater than min (50)",
+		},
+		{
+			title:   "max equal to min",
+			options: []Option{Min(10), Max(10)},
+			err:     "max (10) must be greater than min (10)",
+		},
+		{
+			title:   "unknown display",
+			options: []Option{Display("bar")},
+			err:     `unknown display "bar"`,
+		},
+		{
+			title:   "unknown layout mode",
+			options: []Option{Layout(LayoutSpec{Mode: "column"})},
+			err:     `unknown layout mode "column"`,
+		},
+		{
+			title:   "columns of a row layout",
+			options: []Option{Layout(LayoutSpec{Mode: RowLayout, Columns: 2})},
+			err:     "the columns of the layout only apply to the grid mode",
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			_, err := create(test.options...)
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if !strings.Contains(err.Error(), test.err) {
+				t.Errorf("Expected error containing %q, got %q", test.err, err.Error())
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ import { GaugeChart as EChartsGaugeChart, GaugeSeriesOption } from 'echarts/char
 import { GridComponent, TitleComponent, TooltipComponent } from 'echarts/components';
 import { CanvasRenderer } from 'echarts/renderers';
 import { ReactElement } from 'react';
+import { GaugeDisplay } from './gauge-chart-model';
 
 use([EChartsGaugeChart, GridComponent, TitleComponent, TooltipComponent, CanvasRenderer]);
 
@@ -37,14 +38,28 @@ export interface GaugeChartBaseProps {
   data: GaugeSeries;
   format: FormatOptions;
   axisLine: GaugeSeriesOption['axisLine'];
+  min?: number;
   max?: number;
+  display?: GaugeDisplay;
   valueFontSize: string;
   progressWidth: number;
   titleFontSize: number;
 }
 
 export function GaugeChartBase(props: GaugeChartBaseProps): ReactElement {
-  const { width, height, data, format, axisLine, max, valueFontSize, progressWidth, titleFontSize } = props;
+  const {
+    width,
+    height,
+    data,
+    format,
+    axisLine,
+    min = 0,
+    max,
+    display = 'arc',
+    valueFontSize,
+    progressWidth,
+    titleFontSize,
+  } = props;
   const chartsTheme = useChartsTheme();
 
   // useDeepMemo ensures value size util does not rerun everytime you hover on the chart
@@ -57,7 +72,7 @@ export function GaugeChartBase(props: GaugeChartBaseProps): ReactElement {
       center: ['50%', '65%'] as [string, string],
       startAngle: 200,
       endAngle: -20,
-      min: 0,
+      min: min,
       max: max,
       axisTick: {
         show: false,
@@ -75,6 +90,30 @@ export function GaugeChartBase(props: GaugeChartBaseProps): ReactElement {
       ],
     };
 
+    // The needle display draws the threshold colors as a thick arc under a needle, without the progress arc
+    const isNeedle = display === 'needle';
+    const pointer: GaugeSeriesOption['pointer'] = isNeedle
+      ? {
+          show: true,
+          length: '75%',
+          width: Math.max(4, Math.round(progressWidth * 0.25)),
+          offsetCenter: [0, 0],
+          itemStyle: {
+            color: 'auto',
+          },
+        }
+      : {
+          show: true,
+          // pointer hidden for small panels, path taken from ex: https://echarts.apache.org/examples/en/editor.html?c=gauge-grade
+          icon: width > GAUGE_SMALL_BREAKPOINT ? 'path://M12.8,0.7l12,40.1H0.7L12.8,0.7z' : 'none',
+          length: 10,
+          width: 5,
+          offsetCenter: [0, '-49%'],
+          itemStyle: {
+            color: 'auto',
+          },
+        };
+
     return {
       title: {
         show: false,
@@ -89,13 +128,14 @@ export function GaugeChartBase(props: GaugeChartBaseProps): ReactElement {
           radius: '90%',
           silent: true,
           progress: {
-            show: true,
+            show: !isNeedle,
             width: progressWidth,
             itemStyle: {
               color: 'auto',
             },
           },
           axisLine: {
+            show: !isNeedle,
             lineStyle: {
               color: [[1, 'rgba(127,127,127,0.35)']], // TODO (sjcobb): use future chart theme colors
               width: progressWidth,
@@ -118,24 +158,21 @@ export function GaugeChartBase(props: GaugeChartBaseProps): ReactElement {
         {
           ...baseGaugeConfig,
           radius: '100%',
-          pointer: {
-            show: true,
-            // pointer hidden for small panels, path taken from ex: https://echarts.apache.org/examples/en/editor.html?c=gauge-grade
-            icon: width > GAUGE_SMALL_BREAKPOINT ? 'path://M12.8,0.7l12,40.1H0.7L12.8,0.7z' : 'none',
-            length: 10,
-            width: 5,
-            offsetCenter: [0, '-49%'],
+          pointer: pointer,
+          anchor: {
+            show: isNeedle,
+            size: Math.round(progressWidth * 0.6),
             itemStyle: {
               color: 'auto',
             },
           },
-          axisLine: axisLine,
+          axisLine: isNeedle ? { ...axisLine, lineStyle: { ...axisLine?.lineStyle, width: progressWidth } } : axisLine,
           // `detail` is the text displayed in the middle
           detail: {
             show: true,
             width: '60%',
             borderRadius: 8,
-            offsetCenter: [0, '-9%'],
+            offsetCenter: [0, isNeedle ? '30%' : '-9%'],
             color: 'inherit', // allows value color to match active threshold color
             fontSize: valueFontSize,
             formatter:
@@ -168,7 +205,20 @@ export function GaugeChartBase(props: GaugeChartBaseProps): ReactElement {
         },
       ],
     };
-  }, [data, width, height, chartsTheme, format, axisLine, max, valueFontSize, progressWidth, titleFontSize]);
+  }, [
+    data,
+    width,
+    height,
+    chartsTheme,
+    format,
+    axisLine,
+    min,
+    max,
+    display,
+    valueFontSize,
+    progressWidth,
+    titleFontSize,
+  ]);
 
   return (
     <EChart
@@ -19,6 +19,7 @@ import {
   OptionsEditorControl,
   OptionsEditorGrid,
   OptionsEditorGroup,
+  SettingsAutocomplete,
   ThresholdsEditor,
 } from '@perses-dev/components';
 import { ThresholdOptions } from '@perses-dev/core';
@@ -30,8 +31,12 @@ import {
   DEFAULT_FORMAT,
   DEFAULT_MAX_PERCENT,
   DEFAULT_MAX_PERCENT_DECIMAL,
+  DISPLAY_CONFIG,
+  DISPLAY_OPTIONS,
   GaugeChartOptions,
   GaugeChartOptionsEditorProps,
+  LAYOUT_MODE_CONFIG,
+  LAYOUT_MODE_OPTIONS,
 } from './gauge-chart-model';
 
 export function GaugeChartOptionsEditorSettings(props: GaugeChartOptionsEditorProps): ReactElement {
@@ -76,12 +81,34 @@ export function GaugeChartOptionsEditorSettings(props: GaugeChartOptionsEditorPr
     );
   };
 
+  const display = value.display ?? 'arc';
+  const layoutMode = value.layout?.mode ?? 'row';
+
   return (
     <OptionsEditorGrid>
       <OptionsEditorColumn>
         <OptionsEditorGroup title="Misc">
           <FormatControls value={format} onChange={handleUnitChange} />
           <CalculationSelector value={value.calculation} onChange={handleCalculationChange} />
+          <OptionsEditorControl
+            label="Min"
+            control={
+              <TextField
+                type="number"
+                value={value.min ?? ''}
+                onChange={(e) => {
+                  // ensure empty value resets to undef to start the gauge at 0
+                  const newValue = e.target.value ? Number(e.target.value) : undefined;
+                  onChange(
+                    produce(value, (draft: GaugeChartOptions) => {
+                      draft.min = newValue;
+                    })
+                  );
+                }}
+                placeholder="0"
+              />
+            }
+          />
           <OptionsEditorControl
             label="Max"
             control={
@@ -98,6 +125,89 @@ export function GaugeChartOptionsEditorSettings(props: GaugeChartOptionsEditorPr
                   );
                 }}
                 placeholder={maxPlaceholder}
+                error={value.max !== undefined && value.max <= (value.min ?? 0)}
+                helperText={value.max !== undefined && value.max <= (value.min ?? 0) ? 'Must be greater than min' : ''}
+              />
+            }
+          />
+          <OptionsEditorControl
+            label="Display"
+            control={
+              <SettingsAutocomplete
+                value={{ ...DISPLAY_CONFIG[display], id: display }}
+                options={DISPLAY_OPTIONS}
+                onChange={(__, newValue) => {
+                  onChange(
+                    produce(value, (draft: GaugeChartOptions) => {
+                      // arc is the default, so the property is removed
+                      draft.display = newValue.id === 'arc' ? undefined : newValue.id;
+                    })
+                  );
+                }}
+                disableClearable
+              />
+            }
+          />
+        </OptionsEditorGroup>
+        <OptionsEditorGroup title="Layout">
+          <OptionsEditorControl
+            label="Mode"
+            control={
+              <SettingsAutocomplete
+                value={{ ...LAYOUT_MODE_CONFIG[layoutMode], id: layoutMode }}
+                options={LAYOUT_MODE_OPTIONS}
+                onChange={(__, newValue) => {
+                  onChange(
+                    produce(value, (draft: GaugeChartOptions) => {
+                      draft.layout = { ...draft.layout, mode: newValue.id };
+                      // the columns only apply to the grid mode
+                      if (newValue.id !== 'grid') {
+                        delete draft.layout.columns;
+                      }
+                    })
+                  );
+                }}
+                disableClearable
+              />
+            }
+          />
+          {layoutMode === 'grid' && (
+            <OptionsEditorControl
+              label="Columns"
+              control={
+                <TextField
+                  type="number"
+                  value={value.layout?.columns ?? ''}
+                  onChange={(e) => {
+                    const newValue = e.target.value ? Math.max(1, Math.round(Number(e.target.value))) : undefined;
+                    onChange(
+                      produce(value, (draft: GaugeChartOptions) => {
+                        draft.layout = { ...draft.layout, mode: 'grid', columns: newValue };
+                      })
+                    );
+                  }}
+                  placeholder="Auto"
+                />
+              }
+            />
+          )}
+          <OptionsEditorControl
+            label="Series name format"
+            control={
+              <TextField
+                value={value.layout?.seriesNameFormat ?? ''}
+                onChange={(e) => {
+                  onChange(
+                    produce(value, (draft: GaugeChartOptions) => {
+                      draft.layout = {
+                        ...draft.layout,
+                        mode: draft.layout?.mode ?? 'row',
+                        seriesNameFormat: e.target.value || undefined,
+                      };
+                    })
+                  );
+                }}
+                placeholder="{{instance}}"
               />
             }
           />
@@ -22,6 +22,7 @@ import {
   DEFAULT_FORMAT,
   DEFAULT_MAX_PERCENT,
   DEFAULT_MAX_PERCENT_DECIMAL,
+  DEFAULT_MIN,
   GaugeChartOptions,
 } from './gauge-chart-model';
 import { convertThresholds, defaultThresholdInput } from './thresholds';
@@ -80,11 +81,20 @@ function getResponsiveTitleFontSize(width: number, height: number): number {
   return Math.max(MIN_SIZE, Math.min(MAX_SIZE, size));
 }
 
+/**
+ * Replaces the {{label}} tokens of the series name format by the labels of the series
+ */
+function formatSeriesName(seriesNameFormat: string, labels: Record<string, string> = {}): string {
+  return seriesNameFormat.replace(/\{\{\s*(.+?)\s*\}\}/g, (_match, label) => labels[label] ?? '');
+}
+
 export type GaugeChartPanelProps = PanelProps<GaugeChartOptions, TimeSeriesData>;
 
 export function GaugeChartPanel(props: GaugeChartPanelProps): ReactElement | null {
   const { spec: pluginSpec, contentDimensions, queryResults } = props;
-  const { calculation, max, legend } = pluginSpec;
+  const { calculation, max, legend, layout, display } = pluginSpec;
+  const min = pluginSpec.min ?? DEFAULT_MIN;
+  const seriesNameFormat = layout?.seriesNameFormat;
 
   const { thresholds: thresholdsColors } = useChartsTheme();
 
@@ -110,14 +120,17 @@ export function GaugeChartPanel(props: GaugeChartPanelProps): ReactElement | nul
 
     for (const result of queryResults) {
       for (const timeSeries of result.data.series) {
+        const name = seriesNameFormat
+          ? formatSeriesName(seriesNameFormat, timeSeries.labels)
+          : (timeSeries.formattedName ?? '');
         seriesData.push({
           value: calculate(timeSeries.values),
-          label: showLegend ? (timeSeries.formattedName ?? '') : '',
+          label: showLegend ? name : '',
         });
       }
     }
     return seriesData;
-  }, [queryResults, calculation, showLegend]);
+  }, [queryResults, calculation, showLegend, seriesNameFormat]);
 
   if (!contentDimensions) return null;
 
@@ -125,20 +138,35 @@ export function GaugeChartPanel(props: GaugeChartPanelProps): ReactElement | nul
   let thresholdMax = max;
   if (thresholdMax === undefined) {
     thresholdMax = format.unit === 'percent' ? DEFAULT_MAX_PERCENT : DEFAULT_MAX_PERCENT_DECIMAL;
+    // the default max can't end a gauge starting above it: the gauge ends at the greatest value instead
+    if (thresholdMax <= min) {
+      const values = gaugeData
+        .map(({ value }) => value)
+        .filter((value): value is number => typeof value === 'number' && value > min);
+      thresholdMax = values.length > 0 ? Math.max(...values) : min + thresholdMax;
+    }
   }
-  const axisLineColors = convertThresholds(thresholds, format, thresholdMax, thresholdsColors);
+  const axisLineColors = convertThresholds(thresholds, format, thresholdMax, thresholdsColors, min);
 
-  // accounts for showing a separate chart for each time series
-  let chartWidth = contentDimensions.width / gaugeData.length - PANEL_PADDING_OFFSET;
+  // accounts for showing a separate chart for each time series, in a row or in a grid
+  const isGrid = layout?.mode === 'grid' && gaugeData.length > 1;
+  const columns = isGrid ? Math.min(layout?.columns ?? Math.ceil(Math.sqrt(gaugeData.length)), gaugeData.length) : 1;
+  const rows = isGrid ? Math.ceil(gaugeData.length / columns) : 1;
+  let chartWidth = contentDimensions.width / (isGrid ? columns : gaugeData.length) - PANEL_PADDING_OFFSET;
   if (chartWidth < GAUGE_MIN_WIDTH && gaugeData.length > 1) {
     // enables horizontal scroll when charts overflow outside of panel
     chartWidth = GAUGE_MIN_WIDTH;
   }
+  let chartHeight = isGrid ? contentDimensions.height / rows : contentDimensions.height;
+  if (chartHeight < GAUGE_MIN_WIDTH && isGrid) {
+    // enables vertical scroll when the rows overflow outside of panel
+    chartHeight = GAUGE_MIN_WIDTH;
+  }
 
   // Calculate responsive values based on chart dimensions
-  const progressWidth = getResponsiveProgressWidth(chartWidth, contentDimensions.height);
+  const progressWidth = getResponsiveProgressWidth(chartWidth, chartHeight);
   const axisLineWidth = Math.round(progressWidth * 0.2); // Axis line width is 20% of progress width
-  const titleFontSize = getResponsiveTitleFontSize(chartWidth, contentDimensions.height);
+  const titleFontSize = getResponsiveTitleFontSize(chartWidth, chartHeight);
 
   const axisLine: GaugeSeriesOption['axisLine'] = {
     show: true,
@@ -166,7 +194,9 @@ export function GaugeChartPanel(props: GaugeChartPanelProps): ReactElement | nul
         data={EMPTY_GAUGE_SERIES}
         format={format}
         axisLine={axisLine}
+        min={min}
         max={thresholdMax}
+        display={display}
         valueFontSize={emptyValueFontSize}
         progressWidth={emptyProgressWidth}
         titleFontSize={emptyTitleFontSize}
@@ -176,6 +206,46 @@ export function GaugeChartPanel(props: GaugeChartPanelProps): ReactElement | nul
 
   const hasMultipleCharts = gaugeData.length > 1;
 
+  const gauges = gaugeData.map((series, seriesIndex) => {
+    const fontSize = getResponsiveValueFontSize(series.value ?? null, format, chartWidth, chartHeight);
+
+    return (
+      <Box key={`gauge-series-${seriesIndex}`}>
+        <GaugeChartBase
+          width={chartWidth}
+          height={chartHeight}
+          data={series}
+          format={format}
+          axisLine={axisLine}
+          min={min}
+          max={thresholdMax}
+          display={display}
+          valueFontSize={fontSize}
+          progressWidth={progressWidth}
+          titleFontSize={titleFontSize}
+        />
+      </Box>
+    );
+  });
+
+  if (isGrid) {
+    return (
+      <Box
+        sx={{
+          display: 'grid',
+          gridTemplateColumns: `repeat(${columns}, ${chartWidth}px)`,
+          columnGap: `${PANEL_PADDING_OFFSET}px`,
+          justifyContent: 'center',
+          height: contentDimensions.height,
+          // so scrollbars only show when necessary
+          overflow: 'auto',
+        }}
+      >
+        {gauges}
+      </Box>
+    );
+  }
+
   return (
     <Stack
       direction="row"
@@ -187,25 +257,7 @@ export function GaugeChartPanel(props: GaugeChartPanelProps): ReactElement | nul
         overflowX: gaugeData.length > 1 ? 'scroll' : 'auto',
       }}
     >
-      {gaugeData.map((series, seriesIndex) => {
-        const fontSize = getResponsiveValueFontSize(series.value ?? null, format, chartWidth, contentDimensions.height);
-
-        return (
-          <Box key={`gauge-series-${seriesIndex}`}>
-            <GaugeChartBase
-              width={chartWidth}
-              height={contentDimensions.height}
-              data={series}
-              format={format}
-              axisLine={axisLine}
-              max={thresholdMax}
-              valueFontSize={fontSize}
-              progressWidth={progressWidth}
-              titleFontSize={titleFontSize}
-            />
-          </Box>
-        );
-      })}
+      {gauges}
     </Stack>
   );
 }
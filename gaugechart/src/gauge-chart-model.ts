@@ -17,6 +17,43 @@ import { OptionsEditorProps } from '@perses-dev/plugin-system';
 export const DEFAULT_FORMAT: FormatOptions = { unit: 'percent-decimal' };
 export const DEFAULT_MAX_PERCENT = 100;
 export const DEFAULT_MAX_PERCENT_DECIMAL = 1;
+export const DEFAULT_MIN = 0;
+
+// arc fills an arc up to the value, needle points a needle at the value over the threshold colors
+export type GaugeDisplay = 'arc' | 'needle';
+
+export const DISPLAY_CONFIG: Record<GaugeDisplay, { label: string }> = {
+  arc: { label: 'Arc' },
+  needle: { label: 'Needle' },
+};
+
+export const DISPLAY_OPTIONS = Object.entries(DISPLAY_CONFIG).map(([id, config]) => ({
+  id: id as GaugeDisplay,
+  ...config,
+}));
+
+export type GaugeLayoutMode = 'row' | 'grid';
+
+export const LAYOUT_MODE_CONFIG: Record<GaugeLayoutMode, { label: string }> = {
+  row: { label: 'Row' },
+  grid: { label: 'Grid' },
+};
+
+export const LAYOUT_MODE_OPTIONS = Object.entries(LAYOUT_MODE_CONFIG).map(([id, config]) => ({
+  id: id as GaugeLayoutMode,
+  ...config,
+}));
+
+/**
+ * Lays out one gauge per series, in a row or in a grid. The gauges are labeled with seriesNameFormat when it is set, a
+ * template like "{{instance}}" replaced by the labels of the series.
+ */
+export interface GaugeLayout {
+  mode: GaugeLayoutMode;
+  // grid only, computed from the number of series by default
+  columns?: number;
+  seriesNameFormat?: string;
+}
 
 /**
  * The schema for a GaugeChart panel.
@@ -32,8 +69,11 @@ export interface GaugeChartOptions {
   calculation: CalculationType;
   format?: FormatOptions;
   thresholds?: ThresholdOptions;
+  min?: number;
   max?: number;
   legend?: { show?: boolean };
+  layout?: GaugeLayout;
+  display?: GaugeDisplay;
 }
 
 export type GaugeChartOptionsEditorProps = OptionsEditorProps<GaugeChartOptions>;
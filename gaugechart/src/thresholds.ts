@@ -24,7 +24,8 @@ export function convertThresholds(
   thresholds: ThresholdOptions,
   unit: FormatOptions,
   max: number,
-  palette: ThresholdColorPalette
+  palette: ThresholdColorPalette,
+  min = 0
 ): EChartsAxisLineColors {
   const defaultThresholdColor = thresholds.defaultColor ?? palette.defaultColor;
   const defaultThresholdSteps: EChartsAxisLineColors = [[0, defaultThresholdColor]];
@@ -37,7 +38,8 @@ export function convertThresholds(
       if (thresholds.mode === 'percent') {
         return step.value / 100;
       }
-      return step.value / max;
+      // the segments start at min, and are clamped to the range of the gauge
+      return Math.min(Math.max((step.value - min) / (max - min), 0), segmentMax);
     });
     valuesArr.push(segmentMax);
 
@@ -28,5 +28,41 @@ spec: close({
 	if min != _|_ && max != _|_ {
 		max: >=min
 	}
-	logBase?: 2 | 10
+	logBase?:      2 | 10
+	colorScheme?:  #colorScheme
+	bucketLayout?: #bucketLayout
+	// auto reads native histograms when the results have some, classic buckets when the series have a "le" label,
+	// and raw series otherwise
+	dataFormat?: "auto" | "native-histogram" | "classic-buckets" | "raw-series"
+	if dataFormat != _|_ && bucketLayout != _|_ {
+		// the bucket layout only applies to raw series
+		dataFormat: "auto" | "raw-series"
+	}
+})
+
+#color: =~"^#(?:[0-9a-fA-F]{3}){1,2}$"
+
+#colorScheme: close({
+	mode:     "sequential"
+	palette?: "blues" | "greens" | "oranges" | "reds" | "purples" | "viridis" | "magma"
+}) | close({
+	mode:      "diverging"
+	palette?:  "blue-yellow-red" | "blue-white-red" | "red-yellow-green"
+	midpoint?: number // count the palette is centered on
+}) | close({
+	mode: "custom"
+	// a step colors the counts from its value up to the value of the next step
+	steps: [#colorStep, #colorStep, ...#colorStep]
+})
+
+#colorStep: {
+	value: number
+	color: #color
+}
+
+// the buckets the raw series are bucketed in, 10 buckets by default
+#bucketLayout: close({
+	count: int & >0 // number of buckets over the range of the values
+}) | close({
+	size: number & >0
 })
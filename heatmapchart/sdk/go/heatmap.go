@@ -14,12 +14,112 @@
 package heatmap
 
 import (
+	"fmt"
+	"regexp"
+	"slices"
+
 	"github.com/perses/perses/go-sdk/common"
 	"github.com/perses/perses/go-sdk/panel"
+	"github.com/perses/plugins/sdk/go/option"
 )
 
 const PluginKind = "HeatMapChart"
 
+// DataFormat is the format of the query results the buckets are read from.
+type DataFormat string
+
+const (
+	// AutoDataFormat reads native histograms when the results have some, classic buckets when the series have a "le"
+	// label, and raw series otherwise.
+	AutoDataFormat DataFormat = "auto"
+	// NativeHistogramDataFormat reads the buckets of Prometheus native histograms.
+	NativeHistogramDataFormat DataFormat = "native-histogram"
+	// ClassicBucketsDataFormat reads Prometheus classic histograms: one cumulative series per "le" upper bound.
+	ClassicBucketsDataFormat DataFormat = "classic-buckets"
+	// RawSeriesDataFormat buckets the values of the series client-side, as laid out by the BucketLayout.
+	RawSeriesDataFormat DataFormat = "raw-series"
+)
+
+type ColorSchemeMode string
+
+const (
+	SequentialColorScheme ColorSchemeMode = "sequential"
+	DivergingColorScheme  ColorSchemeMode = "diverging"
+	CustomColorScheme     ColorSchemeMode = "custom"
+)
+
+var (
+	sequentialPalettes = []string{"blues", "greens", "oranges", "reds", "purples", "viridis", "magma"}
+	divergingPalettes  = []string{"blue-yellow-red", "blue-white-red", "red-yellow-green"}
+	colorRegexp        = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}){1,2}$`)
+)
+
+// ColorStep colors the counts greater than or equal to Value, up to the value of the next step.
+type ColorStep struct {
+	Value float64 `json:"value" yaml:"value"`
+	Color string  `json:"color" yaml:"color"`
+}
+
+// ColorScheme colors the cells by count. A sequential or diverging scheme uses a named palette, a diverging one being
+// centered on Midpoint when it is set. A custom scheme uses its steps.
+type ColorScheme struct {
+	Mode     ColorSchemeMode `json:"mode" yaml:"mode"`
+	Palette  string          `json:"palette,omitempty" yaml:"palette,omitempty"`
+	Midpoint *float64        `json:"midpoint,omitempty" yaml:"midpoint,omitempty"`
+	Steps    []ColorStep     `json:"steps,omitempty" yaml:"steps,omitempty"`
+}
+
+func (c *ColorScheme) validate() error {
+	switch c.Mode {
+	case SequentialColorScheme:
+		if len(c.Palette) > 0 && !slices.Contains(sequentialPalettes, c.Palette) {
+			return fmt.Errorf("unknown sequential palette %q, it must be one of %q", c.Palette, sequentialPalettes)
+		}
+	case DivergingColorScheme:
+		if len(c.Palette) > 0 && !slices.Contains(divergingPalettes, c.Palette) {
+			return fmt.Errorf("unknown diverging palette %q, it must be one of %q", c.Palette, divergingPalettes)
+		}
+	case CustomColorScheme:
+		if len(c.Steps) < 2 {
+			return fmt.Errorf("a custom color scheme needs at least two steps")
+		}
+		for i, step := range c.Steps {
+			if !colorRegexp.MatchString(step.Color) {
+				return fmt.Errorf("color %q of the step %d is not a hexadecimal color code", step.Color, i)
+			}
+			if i > 0 && step.Value <= c.Steps[i-1].Value {
+				return fmt.Errorf("the values of the steps must be increasing, step %d (%g) is not greater than step %d (%g)", i, step.Value, i-1, c.Steps[i-1].Value)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown color scheme mode %q", c.Mode)
+	}
+	if c.Mode != CustomColorScheme && len(c.Steps) > 0 {
+		return fmt.Errorf("steps can only be set on a %s color scheme", CustomColorScheme)
+	}
+	if c.Mode != DivergingColorScheme && c.Midpoint != nil {
+		return fmt.Errorf("midpoint can only be set on a %s color scheme", DivergingColorScheme)
+	}
+	return nil
+}
+
+// BucketLayout lays out the buckets the raw series are bucketed in, either a number of buckets over the range of the
+// values or a size of bucket. There are 10 buckets by default.
+type BucketLayout struct {
+	Count uint    `json:"count,omitempty" yaml:"count,omitempty"`
+	Size  float64 `json:"size,omitempty" yaml:"size,omitempty"`
+}
+
+func (b *BucketLayout) validate() error {
+	if b.Count > 0 && b.Size > 0 {
+		return fmt.Errorf("the bucket layout cannot have both a count and a size")
+	}
+	if b.Count == 0 && b.Size <= 0 {
+		return fmt.Errorf("the bucket layout needs a count or a positive size")
+	}
+	return nil
+}
+
 type PluginSpec struct {
 	YAxisFormat   *common.Format `json:"yAxisFormat,omitempty" yaml:"yAxisFormat,omitempty"`
 	CountFormat   *common.Format `json:"countFormat,omitempty" yaml:"countFormat,omitempty"`
@@ -27,6 +127,31 @@ type PluginSpec struct {
 	Min           float64        `json:"min,omitempty" yaml:"min,omitempty"`
 	Max           float64        `json:"max,omitempty" yaml:"max,omitempty"`
 	LogBase       uint           `json:"logBase,omitempty" yaml:"logBase,omitempty"`
+	ColorScheme   *ColorScheme   `json:"colorScheme,omitempty" yaml:"colorScheme,omitempty"`
+	BucketLayout  *BucketLayout  `json:"bucketLayout,omitempty" yaml:"bucketLayout,omitempty"`
+	DataFormat    DataFormat     `json:"dataFormat,omitempty" yaml:"dataFormat,omitempty"`
+}
+
+func (s *PluginSpec) validate() error {
+	if s.ColorScheme != nil {
+		if err := s.ColorScheme.validate(); err != nil {
+			return err
+		}
+	}
+	switch s.DataFormat {
+	case "", AutoDataFormat, NativeHistogramDataFormat, ClassicBucketsDataFormat, RawSeriesDataFormat:
+	default:
+		return fmt.Errorf("unknown data format %q", s.DataFormat)
+	}
+	if s.BucketLayout != nil {
+		if s.DataFormat == NativeHistogramDataFormat || s.DataFormat == ClassicBucketsDataFormat {
+			return fmt.Errorf("the bucket layout only applies to raw series, not to the %s data format", s.DataFormat)
+		}
+		if err := s.BucketLayout.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 type Option func(plugin *Builder) error
@@ -46,10 +171,8 @@ func create(options ...Option) (Builder, error) {
 		ShowVisualMap(true),
 	}
 
-	for _, opt := range append(defaults, options...) {
-		if err := opt(builder); err != nil {
-			return *builder, err
-		}
+	if err := option.ApplyAndValidate(PluginKind, builder, append(defaults, options...), builder.validate); err != nil {
+		return *builder, err
 	}
 
 	return *builder, nil
@@ -58,3 +58,50 @@ func WithLogBase(logBase uint) Option {
 		return nil
 	}
 }
+
+func WithColorScheme(colorScheme ColorScheme) Option {
+	return func(builder *Builder) error {
+		builder.ColorScheme = &colorScheme
+		return nil
+	}
+}
+
+// WithSequentialColors colors the cells with a sequential palette: "blues", "greens", "oranges", "reds", "purples",
+// "viridis" or "magma".
+func WithSequentialColors(palette string) Option {
+	return WithColorScheme(ColorScheme{Mode: SequentialColorScheme, Palette: palette})
+}
+
+// WithDivergingColors colors the cells with a diverging palette, "blue-yellow-red", "blue-white-red" or
+// "red-yellow-green", centered on the midpoint count.
+func WithDivergingColors(palette string, midpoint float64) Option {
+	return WithColorScheme(ColorScheme{Mode: DivergingColorScheme, Palette: palette, Midpoint: &midpoint})
+}
+
+// WithCustomColors colors the cells by steps of count.
+func WithCustomColors(steps ...ColorStep) Option {
+	return WithColorScheme(ColorScheme{Mode: CustomColorScheme, Steps: steps})
+}
+
+func WithDataFormat(dataFormat DataFormat) Option {
+	return func(builder *Builder) error {
+		builder.DataFormat = dataFormat
+		return nil
+	}
+}
+
+// WithBucketCount buckets the raw series in count buckets over the range of their values.
+func WithBucketCount(count uint) Option {
+	return func(builder *Builder) error {
+		builder.BucketLayout = &BucketLayout{Count: count}
+		return nil
+	}
+}
+
+// WithBucketSize buckets the raw series in buckets of the size.
+func WithBucketSize(size float64) Option {
+	return func(builder *Builder) error {
+		builder.BucketLayout = &BucketLayout{Size: size}
+		return nil
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package heatmap
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestColorSchemeAndBuckets(t *testing.T) {
+	builder, err := create(
+		WithDivergingColors("blue-white-red", 50),
+		WithDataFormat(RawSeriesDataFormat),
+		WithBucketSize(0.5),
+	)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	jsonBytes, err := json.Marshal(builder.PluginSpec)
+	if err != nil {
+		t.Fatalf("Failed to marshal spec: %v", err)
+	}
+	var result map[string]any
+	if err := json.Unmarshal(jsonBytes, &result); err != nil {
+		t.Fatalf("Failed to unmarshal spec: %v", err)
+	}
+	colorScheme, _ := json.Marshal(result["colorScheme"])
+	if string(colorScheme) != `{"midpoint":50,"mode":"diverging","palette":"blue-white-red"}` {
+		t.Errorf("Unexpected color scheme %s", colorScheme)
+	}
+	bucketLayout, _ := json.Marshal(result["bucketLayout"])
+	if string(bucketLayout) != `{"size":0.5}` || result["dataFormat"] != "raw-series" {
+		t.Errorf("Unexpected bucket layout %s or data format %v", bucketLayout, result["dataFormat"])
+	}
+}
+
+func TestColorSchemeAndBuckets_Invalid(t *testing.T) {
+	testSuites := []struct {
+		title   string
+		options []Option
+	}{
+		{
+			title:   "unknown sequential palette",
+			options: []Option{WithSequentialColors("rainbow")},
+		},
+		{
+			title:   "single custom step",
+			options: []Option{WithCustomColors(ColorStep{Value: 0, Color: "#000000"})},
+		},
+		{
+			title:   "decreasing custom steps",
+			options: []Option{WithCustomColors(ColorStep{Value: 10, Color: "#000000"}, ColorStep{Value: 5, Color: "#ffffff"})},
+		},
+		{
+			title:   "invalid custom color",
+			options: []Option{WithCustomColors(ColorStep{Value: 0, Color: "black"}, ColorStep{Value: 5, Color: "#ffffff"})},
+		},
+		{
+			title:   "bucket layout of native histograms",
+			options: []Option{WithDataFormat(NativeHistogramDataFormat), WithBucketCount(20)},
+		},
+		{
+			title:   "unknown data format",
+			options: []Option{WithDataFormat("summary")},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			if _, err := create(test.options...); err == nil {
+				t.Error("Expected an error, got nil")
+			}
+		})
+	}
+}
@@ -19,27 +19,12 @@ import { CustomChart } from 'echarts/charts';
 import type { CustomSeriesRenderItemAPI, CustomSeriesRenderItemParams } from 'echarts';
 import { useTheme } from '@mui/material';
 import { CustomSeriesRenderItemReturn } from 'echarts/types/dist/echarts';
-import { LOG_BASE } from '../heat-map-chart-model';
-import { getFormattedHeatmapAxisLabel } from '../utils';
+import { HeatMapColorScheme, LOG_BASE } from '../heat-map-chart-model';
+import { getFormattedHeatmapAxisLabel, getVisualMapColors } from '../utils';
 import { generateTooltipHTML } from './HeatMapTooltip';
 
 use([CustomChart]);
 
-// The default coloring is a blue->yellow->red gradient
-const DEFAULT_VISUAL_MAP_COLORS = [
-  '#313695',
-  '#4575b4',
-  '#74add1',
-  '#abd9e9',
-  '#e0f3f8',
-  '#ffffbf',
-  '#fee090',
-  '#fdae61',
-  '#f46d43',
-  '#d73027',
-  '#a50026',
-];
-
 export type HeatMapData = [number, number, number, number | undefined]; // [xIndex, yLower, yUpper, count]
 
 export interface HeatMapDataItem {
@@ -66,6 +51,7 @@ export interface HeatMapChartProps {
   min?: number;
   max?: number;
   logBase?: LOG_BASE;
+  colorScheme?: HeatMapColorScheme;
 }
 
 export function HeatMapChart({
@@ -82,12 +68,14 @@ export function HeatMapChart({
   min,
   max,
   logBase,
+  colorScheme,
 }: HeatMapChartProps): ReactElement | null {
   const chartsTheme = useChartsTheme();
   const theme = useTheme();
   const { timeZone } = useTimeZone();
 
   const option: EChartsCoreOption = useMemo(() => {
+    const visualMapColors = getVisualMapColors(colorScheme, countMin ?? 0, countMax ?? 0);
     return {
       tooltip: {
         appendToBody: true,
@@ -131,18 +119,12 @@ export function HeatMapChart({
       },
       visualMap: {
         show: showVisualMap ?? false,
-        type: 'continuous',
-        min: countMin,
-        max: countMax,
-        realtime: false,
-        itemHeight: height - 30,
-        itemWidth: 10,
+        ...visualMapColors,
+        // the size of the continuous gradient, the pieces of a piecewise visual map keep their default size
+        ...(visualMapColors.type === 'continuous' ? { realtime: false, itemHeight: height - 30, itemWidth: 10 } : {}),
         orient: 'vertical',
         left: 'right',
         top: 'center',
-        inRange: {
-          color: DEFAULT_VISUAL_MAP_COLORS,
-        },
         textStyle: {
           color: theme.palette.text.primary,
           textBorderColor: theme.palette.background.default,
@@ -214,6 +196,7 @@ export function HeatMapChart({
     min,
     max,
     logBase,
+    colorScheme,
   ]);
 
   const chart = useMemo(
@@ -25,7 +25,11 @@ import { produce } from 'immer';
 import merge from 'lodash/merge';
 import { ReactElement } from 'react';
 import {
+  COLOR_SCHEME_OPTIONS,
+  DATA_FORMAT_CONFIG,
+  DATA_FORMAT_OPTIONS,
   DEFAULT_FORMAT,
+  getColorSchemeOptionId,
   HeatMapChartOptions,
   HeatMapChartOptionsEditorProps,
   LOG_BASE_CONFIG,
@@ -67,6 +71,10 @@ export function HeatMapChartOptionsEditorSettings(props: HeatMapChartOptionsEdit
   const logBaseKey = value.logBase ? String(value.logBase) : 'none';
   const logBase = LOG_BASE_CONFIG[logBaseKey] ?? LOG_BASE_CONFIG['none'];
 
+  const dataFormat = value.dataFormat ?? 'auto';
+  const colorSchemeId = getColorSchemeOptionId(value.colorScheme);
+  const colorScheme = COLOR_SCHEME_OPTIONS.find((option) => option.id === colorSchemeId) ?? COLOR_SCHEME_OPTIONS[0]!;
+
   return (
     <OptionsEditorGrid>
       <OptionsEditorColumn>
@@ -76,6 +84,76 @@ export function HeatMapChartOptionsEditorSettings(props: HeatMapChartOptionsEdit
             label="Show Visual Map"
             control={<Switch checked={!!value.showVisualMap} onChange={handleShowVisualMapChange} />}
           />
+          <OptionsEditorControl
+            label="Color Scheme"
+            control={
+              <SettingsAutocomplete
+                value={colorScheme}
+                options={COLOR_SCHEME_OPTIONS}
+                onChange={(__, newValue) => {
+                  onChange(
+                    produce(value, (draft: HeatMapChartOptions) => {
+                      if (newValue.colorScheme === undefined) {
+                        return;
+                      }
+                      // keep the midpoint when switching between diverging palettes
+                      const midpoint = draft.colorScheme?.mode === 'diverging' ? draft.colorScheme.midpoint : undefined;
+                      draft.colorScheme =
+                        newValue.colorScheme.mode === 'diverging' && midpoint !== undefined
+                          ? { ...newValue.colorScheme, midpoint }
+                          : newValue.colorScheme;
+                    })
+                  );
+                }}
+                disableClearable
+              />
+            }
+          />
+        </OptionsEditorGroup>
+        <OptionsEditorGroup title="Data">
+          <OptionsEditorControl
+            label="Data Format"
+            control={
+              <SettingsAutocomplete
+                value={{ ...DATA_FORMAT_CONFIG[dataFormat], id: dataFormat }}
+                options={DATA_FORMAT_OPTIONS}
+                onChange={(__, newValue) => {
+                  onChange(
+                    produce(value, (draft: HeatMapChartOptions) => {
+                      // auto is the default, so the property is removed
+                      draft.dataFormat = newValue.id === 'auto' ? undefined : newValue.id;
+                      // the bucket layout only applies to raw series
+                      if (newValue.id === 'native-histogram' || newValue.id === 'classic-buckets') {
+                        draft.bucketLayout = undefined;
+                      }
+                    })
+                  );
+                }}
+                disableClearable
+              />
+            }
+          />
+          {(dataFormat === 'auto' || dataFormat === 'raw-series') && (
+            <OptionsEditorControl
+              label="Bucket Count"
+              control={
+                <TextField
+                  type="number"
+                  value={value.bucketLayout && 'count' in value.bucketLayout ? value.bucketLayout.count : ''}
+                  onChange={(e) => {
+                    const count = e.target.value ? Math.max(1, Math.round(Number(e.target.value))) : undefined;
+                    onChange(
+                      produce(value, (draft: HeatMapChartOptions) => {
+                        draft.bucketLayout = count !== undefined ? { count } : undefined;
+                      })
+                    );
+                  }}
+                  placeholder="10"
+                  sx={{ width: '100%' }}
+                />
+              }
+            />
+          )}
         </OptionsEditorGroup>
       </OptionsEditorColumn>
       <OptionsEditorColumn>
@@ -16,8 +16,16 @@ import { TimeScale, TimeSeries, TimeSeriesData } from '@perses-dev/core';
 import { PanelProps } from '@perses-dev/plugin-system';
 import merge from 'lodash/merge';
 import { ReactElement, useMemo } from 'react';
-import { DEFAULT_FORMAT, HeatMapChartOptions, LOG_BASE } from '../heat-map-chart-model';
-import { generateCompleteTimestamps, getCommonTimeScaleForQueries } from '../utils';
+import { DEFAULT_FORMAT, HeatMapChartOptions, HeatMapDataFormat, LOG_BASE } from '../heat-map-chart-model';
+import {
+  generateCompleteTimestamps,
+  getClassicBuckets,
+  getCommonTimeScaleForQueries,
+  getNativeHistogramBuckets,
+  getRawSeriesBuckets,
+  HeatMapBucket,
+  resolveDataFormat,
+} from '../utils';
 import { HeatMapChart, HeatMapDataItem } from './HeatMapChart';
 
 /**
@@ -50,6 +58,7 @@ export function HeatMapChartPanel(props: HeatMapChartPanelProps): ReactElement |
     countMin,
     countMax,
     timeScale,
+    dataFormat,
   }: {
     data: HeatMapDataItem[];
     xAxisCategories: number[];
@@ -58,39 +67,44 @@ export function HeatMapChartPanel(props: HeatMapChartPanelProps): ReactElement |
     countMin: number;
     countMax: number;
     timeScale?: TimeScale;
+    dataFormat?: HeatMapDataFormat;
   } = useMemo(() => {
+    const noData = {
+      data: [],
+      xAxisCategories: [],
+      min: 0,
+      max: 0,
+      countMin: 0,
+      countMax: 0,
+      timeScale: undefined,
+    };
     if (!queryResults || queryResults.length === 0) {
-      return {
-        data: [],
-        xAxisCategories: [],
-        min: 0,
-        max: 0,
-        countMin: 0,
-        countMax: 0,
-        timeScale: undefined,
-      };
+      return noData;
     }
 
-    if (
-      queryResults.length !== 1 ||
-      queryResults[0]!.data.series.length !== 1 ||
-      queryResults[0]!.data.series[0]!.histograms === undefined
-    ) {
-      return {
-        data: [],
-        xAxisCategories: [],
-        min: 0,
-        max: 0,
-        countMin: 0,
-        countMax: 0,
-        timeScale: undefined,
-      };
+    const allSeries: TimeSeries[] = queryResults.flatMap((result) => result.data.series);
+    const dataFormat = resolveDataFormat(allSeries, pluginSpec.dataFormat);
+    let buckets: HeatMapBucket[];
+    switch (dataFormat) {
+      case 'native-histogram': {
+        const series = allSeries[0];
+        if (queryResults.length !== 1 || allSeries.length !== 1 || series?.histograms === undefined) {
+          return { ...noData, dataFormat };
+        }
+        buckets = getNativeHistogramBuckets(series);
+        break;
+      }
+      case 'classic-buckets':
+        buckets = getClassicBuckets(allSeries);
+        break;
+      case 'raw-series':
+        buckets = getRawSeriesBuckets(allSeries, pluginSpec.bucketLayout);
+        break;
     }
 
-    const series: TimeSeries = queryResults[0]!.data.series[0]!;
-
     const timeScale = getCommonTimeScaleForQueries(queryResults);
     const xAxisCategories: number[] = generateCompleteTimestamps(timeScale);
+    const xAxisIndexes = new Map(xAxisCategories.map((time, index) => [time, index]));
 
     const logBase = pluginSpec.logBase;
 
@@ -100,63 +114,37 @@ export function HeatMapChartPanel(props: HeatMapChartPanelProps): ReactElement |
     let countMin = Infinity;
     let countMax = -Infinity;
 
-    for (const [, histogram] of series?.histograms ?? []) {
-      for (const bucket of histogram?.buckets ?? []) {
-        const [, lowerBound, upperBound, count] = bucket;
-        let lowerBoundFloat = parseFloat(lowerBound);
-        const upperBoundFloat = parseFloat(upperBound);
-        const countFloat = parseFloat(count);
-
-        // For logarithmic scales, skip buckets that would be entirely non-positive
-        if (logBase !== undefined && upperBoundFloat <= 0) {
-          continue;
-        }
-
-        // For log scales, adjust non-positive lower bounds
-        if (logBase !== undefined) {
-          lowerBoundFloat = getEffectiveLowerBound(lowerBoundFloat, upperBoundFloat, logBase);
-        }
-
-        if (lowerBoundFloat < lowestBound) {
-          lowestBound = lowerBoundFloat;
-        }
-        if (upperBoundFloat > highestBound) {
-          highestBound = upperBoundFloat;
-        }
-        if (countFloat < countMin) {
-          countMin = countFloat;
-        }
-        if (countFloat > countMax) {
-          countMax = countFloat;
-        }
-      }
-    }
-
     const data: HeatMapDataItem[] = [];
     // Each bucket becomes a rectangle spanning [lowerBound, upperBound] at the given x index
-    for (const [time, histogram] of series?.histograms ?? []) {
-      const itemIndexOnXaxis = xAxisCategories.findIndex((v) => v === time * 1000);
-
-      for (const bucket of histogram?.buckets ?? []) {
-        const [, lowerBound, upperBound, count] = bucket;
-        let lowerBoundFloat = parseFloat(lowerBound);
-        const upperBoundFloat = parseFloat(upperBound);
+    for (const bucket of buckets) {
+      const { upperBound, count } = bucket;
 
-        // For logarithmic scales, skip buckets that would be entirely non-positive
-        if (logBase !== undefined && upperBoundFloat <= 0) {
-          continue;
-        }
+      // For logarithmic scales, skip buckets that would be entirely non-positive
+      if (logBase !== undefined && upperBound <= 0) {
+        continue;
+      }
 
-        // For log scales, adjust non-positive lower bounds
-        if (logBase !== undefined) {
-          lowerBoundFloat = getEffectiveLowerBound(lowerBoundFloat, upperBoundFloat, logBase);
-        }
+      // For log scales, adjust non-positive lower bounds
+      const lowerBound =
+        logBase !== undefined ? getEffectiveLowerBound(bucket.lowerBound, upperBound, logBase) : bucket.lowerBound;
 
-        data.push({
-          value: [itemIndexOnXaxis, lowerBoundFloat, upperBoundFloat, parseFloat(count)],
-          label: count,
-        });
+      if (lowerBound < lowestBound) {
+        lowestBound = lowerBound;
+      }
+      if (upperBound > highestBound) {
+        highestBound = upperBound;
       }
+      if (count < countMin) {
+        countMin = count;
+      }
+      if (count > countMax) {
+        countMax = count;
+      }
+
+      data.push({
+        value: [xAxisIndexes.get(bucket.time) ?? -1, lowerBound, upperBound, count],
+        label: bucket.label,
+      });
     }
     return {
       data,
@@ -166,8 +154,9 @@ export function HeatMapChartPanel(props: HeatMapChartPanelProps): ReactElement |
       countMin,
       countMax,
       timeScale,
+      dataFormat,
     };
-  }, [pluginSpec.logBase, queryResults]);
+  }, [pluginSpec.logBase, pluginSpec.dataFormat, pluginSpec.bucketLayout, queryResults]);
 
   // Use configured min/max if provided, otherwise use calculated values
   // For logarithmic scales, ignore user-provided min if it's <= 0 (log of non-positive is undefined)
@@ -194,8 +183,8 @@ export function HeatMapChartPanel(props: HeatMapChartPanelProps): ReactElement |
     return pluginSpec.max ?? max;
   }, [pluginSpec.logBase, pluginSpec.max, max]);
 
-  // TODO: add support for multiple queries
-  if (queryResults.length > 1) {
+  // TODO: add support for multiple queries of native histograms
+  if (dataFormat === 'native-histogram' && queryResults.length > 1) {
     return (
       <Stack justifyContent="center" height="100%">
         <Typography variant="body2" textAlign="center">
@@ -210,7 +199,7 @@ export function HeatMapChartPanel(props: HeatMapChartPanelProps): ReactElement |
     return (
       <Stack justifyContent="center" height="100%">
         <Typography variant="body2" textAlign="center">
-          No data available (only native histograms are supported for now)
+          No data available
         </Typography>
       </Stack>
     );
@@ -234,6 +223,7 @@ export function HeatMapChartPanel(props: HeatMapChartPanelProps): ReactElement |
         min={finalMin}
         max={finalMax}
         logBase={pluginSpec.logBase}
+        colorScheme={pluginSpec.colorScheme}
       />
     </Stack>
   );
@@ -33,6 +33,109 @@ export const LOG_BASE_OPTIONS = Object.entries(LOG_BASE_CONFIG).map(([id, config
   ...config,
 }));
 
+// auto reads native histograms when the results have some, classic buckets when the series have a "le" label, and raw
+// series otherwise.
+export type HeatMapDataFormat = 'auto' | 'native-histogram' | 'classic-buckets' | 'raw-series';
+
+export const DATA_FORMAT_CONFIG: Record<HeatMapDataFormat, { label: string }> = {
+  auto: { label: 'Auto' },
+  'native-histogram': { label: 'Native histogram' },
+  'classic-buckets': { label: 'Classic buckets (le)' },
+  'raw-series': { label: 'Raw series' },
+};
+
+export const DATA_FORMAT_OPTIONS = Object.entries(DATA_FORMAT_CONFIG).map(([id, config]) => ({
+  id: id as HeatMapDataFormat,
+  ...config,
+}));
+
+export const SEQUENTIAL_PALETTES = {
+  blues: ['#f7fbff', '#c6dbef', '#6baed6', '#2171b5', '#08306b'],
+  greens: ['#f7fcf5', '#c7e9c0', '#74c476', '#238b45', '#00441b'],
+  oranges: ['#fff5eb', '#fdd0a2', '#fd8d3c', '#d94801', '#7f2704'],
+  reds: ['#fff5f0', '#fcbba1', '#fb6a4a', '#cb181d', '#67000d'],
+  purples: ['#fcfbfd', '#dadaeb', '#9e9ac8', '#6a51a3', '#3f007d'],
+  viridis: ['#440154', '#3b528b', '#21918c', '#5ec962', '#fde725'],
+  magma: ['#000004', '#3b0f70', '#8c2981', '#de4968', '#fe9f6d', '#fcfdbf'],
+};
+
+export const DIVERGING_PALETTES = {
+  'blue-yellow-red': [
+    '#313695',
+    '#4575b4',
+    '#74add1',
+    '#abd9e9',
+    '#e0f3f8',
+    '#ffffbf',
+    '#fee090',
+    '#fdae61',
+    '#f46d43',
+    '#d73027',
+    '#a50026',
+  ],
+  'blue-white-red': ['#2166ac', '#67a9cf', '#d1e5f0', '#f7f7f7', '#fddbc7', '#ef8a62', '#b2182b'],
+  'red-yellow-green': ['#d73027', '#fc8d59', '#fee08b', '#ffffbf', '#d9ef8b', '#91cf60', '#1a9850'],
+};
+
+export type SequentialPalette = keyof typeof SEQUENTIAL_PALETTES;
+export type DivergingPalette = keyof typeof DIVERGING_PALETTES;
+
+// The default coloring is a blue->yellow->red gradient
+export const DEFAULT_DIVERGING_PALETTE: DivergingPalette = 'blue-yellow-red';
+export const DEFAULT_SEQUENTIAL_PALETTE: SequentialPalette = 'blues';
+
+export interface HeatMapColorStep {
+  value: number;
+  color: string;
+}
+
+/**
+ * Colors the cells by count. A step of a custom scheme colors the counts from its value up to the value of the next
+ * step. A diverging scheme is centered on its midpoint when it is set.
+ */
+export type HeatMapColorScheme =
+  | { mode: 'sequential'; palette?: SequentialPalette }
+  | { mode: 'diverging'; palette?: DivergingPalette; midpoint?: number }
+  | { mode: 'custom'; steps: HeatMapColorStep[] };
+
+/**
+ * The buckets the raw series are bucketed in: a number of buckets over the range of the values or a size of bucket.
+ */
+export type HeatMapBucketLayout = { count: number } | { size: number };
+
+export const DEFAULT_BUCKET_COUNT = 10;
+
+// Options of the editor, a custom color scheme being only editable as JSON
+export const COLOR_SCHEME_OPTIONS: Array<{
+  id: string;
+  label: string;
+  colorScheme?: HeatMapColorScheme;
+  disabled?: boolean;
+}> = [
+  ...Object.keys(SEQUENTIAL_PALETTES).map((palette) => ({
+    id: `sequential:${palette}`,
+    label: `Sequential: ${palette}`,
+    colorScheme: { mode: 'sequential', palette: palette as SequentialPalette } as HeatMapColorScheme,
+  })),
+  ...Object.keys(DIVERGING_PALETTES).map((palette) => ({
+    id: `diverging:${palette}`,
+    label: `Diverging: ${palette}`,
+    colorScheme: { mode: 'diverging', palette: palette as DivergingPalette } as HeatMapColorScheme,
+  })),
+  { id: 'custom', label: 'Custom', disabled: true },
+];
+
+export function getColorSchemeOptionId(colorScheme?: HeatMapColorScheme): string {
+  switch (colorScheme?.mode) {
+    case 'custom':
+      return 'custom';
+    case 'sequential':
+      return `sequential:${colorScheme.palette ?? DEFAULT_SEQUENTIAL_PALETTE}`;
+    default:
+      return `diverging:${colorScheme?.palette ?? DEFAULT_DIVERGING_PALETTE}`;
+  }
+}
+
 /**
  * The schema for a HeatMapChart panel.
  */
@@ -50,6 +153,9 @@ export interface HeatMapChartOptions {
   logBase?: LOG_BASE;
   min?: number;
   max?: number;
+  colorScheme?: HeatMapColorScheme;
+  bucketLayout?: HeatMapBucketLayout;
+  dataFormat?: HeatMapDataFormat;
 }
 
 export type HeatMapChartOptionsEditorProps = OptionsEditorProps<HeatMapChartOptions>;
@@ -0,0 +1,129 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the \"License\");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an \"AS IS\" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import { TimeSeries } from '@perses-dev/core';
+import { getClassicBuckets, getNativeHistogramBuckets, getRawSeriesBuckets, resolveDataFormat } from './buckets';
+
+function series(...values: Array<[number, number | null]>): TimeSeries {
+  return { name: 'up', values };
+}
+
+function classicBucket(le: string, value: number): TimeSeries {
+  return { name: `le="${le}"`, labels: { le }, values: [[0, value]] };
+}
+
+describe('resolveDataFormat', () => {
+  it('should keep an explicit format', () => {
+    expect(resolveDataFormat([series([0, 1])], 'classic-buckets')).toEqual('classic-buckets');
+  });
+
+  it('should read native histograms when the series have some', () => {
+    const histogram: TimeSeries = { name: 'h', values: [], histograms: [[0, { count: 1, sum: '1', buckets: [] }]] };
+    expect(resolveDataFormat([series([0, 1]), histogram])).toEqual('native-histogram');
+  });
+
+  it('should read classic buckets when every series has a "le" label', () => {
+    expect(resolveDataFormat([classicBucket('0.1', 1), classicBucket('+Inf', 2)])).toEqual('classic-buckets');
+    expect(resolveDataFormat([classicBucket('0.1', 1), series([0, 1])])).toEqual('raw-series');
+  });
+
+  it('should read raw series otherwise', () => {
+    expect(resolveDataFormat([series([0, 1])])).toEqual('raw-series');
+    expect(resolveDataFormat([])).toEqual('raw-series');
+  });
+});
+
+describe('getNativeHistogramBuckets', () => {
+  it('should read the buckets of each histogram', () => {
+    const histogram: TimeSeries = {
+      name: 'h',
+      values: [],
+      histograms: [
+        [
+          10,
+          {
+            count: 5,
+            sum: '2',
+            buckets: [
+              [0, '0', '0.5', '2'],
+              [0, '0.5', '1', '3'],
+            ],
+          },
+        ],
+      ],
+    };
+    expect(getNativeHistogramBuckets(histogram)).toEqual([
+      { time: 10000, lowerBound: 0, upperBound: 0.5, count: 2, label: '2' },
+      { time: 10000, lowerBound: 0.5, upperBound: 1, count: 3, label: '3' },
+    ]);
+  });
+});
+
+describe('getClassicBuckets', () => {
+  it('should sum the series of the same bound and de-cumulate the counts', () => {
+    expect(
+      getClassicBuckets([
+        classicBucket('0.1', 1),
+        classicBucket('0.1', 2),
+        classicBucket('0.5', 7),
+        classicBucket('+Inf', 8),
+      ])
+    ).toEqual([
+      { time: 0, lowerBound: 0, upperBound: 0.1, count: 3, label: '3' },
+      { time: 0, lowerBound: 0.1, upperBound: 0.5, count: 4, label: '4' },
+    ]);
+  });
+
+  it('should clamp the negative differences to 0', () => {
+    expect(getClassicBuckets([classicBucket('1', 5), classicBucket('2', 3), classicBucket('4', 6)])).toEqual([
+      { time: 0, lowerBound: 0, upperBound: 1, count: 5, label: '5' },
+      { time: 0, lowerBound: 1, upperBound: 2, count: 0, label: '0' },
+      { time: 0, lowerBound: 2, upperBound: 4, count: 1, label: '1' },
+    ]);
+  });
+
+  it('should start the first bucket at 0, or as wide as the next one for a negative bound', () => {
+    expect(getClassicBuckets([classicBucket('-1', 1), classicBucket('1', 3)])).toEqual([
+      { time: 0, lowerBound: -3, upperBound: -1, count: 1, label: '1' },
+      { time: 0, lowerBound: -1, upperBound: 1, count: 2, label: '2' },
+    ]);
+  });
+});
+
+describe('getRawSeriesBuckets', () => {
+  it('should split the range of the values in count buckets, the max falling in the last one', () => {
+    const values = [series([0, 0]), series([0, 5]), series([0, 10], [1000, null])];
+    expect(getRawSeriesBuckets(values, { count: 2 })).toEqual([
+      { time: 0, lowerBound: 0, upperBound: 5, count: 1, label: '1' },
+      { time: 0, lowerBound: 5, upperBound: 10, count: 2, label: '2' },
+    ]);
+  });
+
+  it('should align the buckets of a size on multiples of the size', () => {
+    expect(getRawSeriesBuckets([series([0, 7], [1000, 12])], { size: 5 })).toEqual([
+      { time: 0, lowerBound: 5, upperBound: 10, count: 1, label: '1' },
+      { time: 1000, lowerBound: 10, upperBound: 15, count: 1, label: '1' },
+    ]);
+  });
+
+  it('should spread a single value over a bucket of size 1', () => {
+    expect(getRawSeriesBuckets([series([0, 4]), series([1000, 4])])).toEqual([
+      { time: 0, lowerBound: 4, upperBound: 5, count: 1, label: '1' },
+      { time: 1000, lowerBound: 4, upperBound: 5, count: 1, label: '1' },
+    ]);
+  });
+
+  it('should return no bucket without values', () => {
+    expect(getRawSeriesBuckets([series([0, null])])).toEqual([]);
+  });
+});
@@ -0,0 +1,177 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the \"License\");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an \"AS IS\" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import { TimeSeries } from '@perses-dev/core';
+import { DEFAULT_BUCKET_COUNT, HeatMapBucketLayout, HeatMapDataFormat } from '../heat-map-chart-model';
+
+export interface HeatMapBucket {
+  // Unix time in milliseconds
+  time: number;
+  lowerBound: number;
+  upperBound: number;
+  count: number;
+  // Raw count displayed in the tooltip
+  label: string;
+}
+
+const CLASSIC_BUCKET_LABEL = 'le';
+
+/**
+ * Resolves the auto data format: native histograms when the series have some, classic buckets when they have a "le"
+ * label, and raw series otherwise.
+ */
+export function resolveDataFormat(
+  series: TimeSeries[],
+  dataFormat: HeatMapDataFormat = 'auto'
+): Exclude<HeatMapDataFormat, 'auto'> {
+  if (dataFormat !== 'auto') {
+    return dataFormat;
+  }
+  if (series.some((s) => s.histograms !== undefined)) {
+    return 'native-histogram';
+  }
+  if (series.length > 0 && series.every((s) => s.labels?.[CLASSIC_BUCKET_LABEL] !== undefined)) {
+    return 'classic-buckets';
+  }
+  return 'raw-series';
+}
+
+/**
+ * Gets the buckets of a series of Prometheus native histograms.
+ */
+export function getNativeHistogramBuckets(series: TimeSeries): HeatMapBucket[] {
+  const buckets: HeatMapBucket[] = [];
+  for (const [time, histogram] of series.histograms ?? []) {
+    for (const [, lowerBound, upperBound, count] of histogram?.buckets ?? []) {
+      buckets.push({
+        time: time * 1000,
+        lowerBound: parseFloat(lowerBound),
+        upperBound: parseFloat(upperBound),
+        count: parseFloat(count),
+        label: count,
+      });
+    }
+  }
+  return buckets;
+}
+
+/**
+ * Gets the buckets of Prometheus classic histograms, made of one cumulative series per "le" upper bound. The series
+ * having the same bound, e.g. of several instances, are summed. The counts are de-cumulated, negative differences
+ * caused by counter resets or rate extrapolation are clamped to 0, and the +Inf bucket is dropped since it has no
+ * upper bound to draw.
+ */
+export function getClassicBuckets(series: TimeSeries[]): HeatMapBucket[] {
+  // time -> upper bound -> cumulative count
+  const countsByTime = new Map<number, Map<number, number>>();
+  for (const s of series) {
+    const upperBound = parseFloat(s.labels?.[CLASSIC_BUCKET_LABEL] ?? '');
+    if (Number.isNaN(upperBound)) {
+      continue;
+    }
+    for (const [time, value] of s.values) {
+      if (value === null || !Number.isFinite(value)) {
+        continue;
+      }
+      let counts = countsByTime.get(time);
+      if (counts === undefined) {
+        counts = new Map();
+        countsByTime.set(time, counts);
+      }
+      counts.set(upperBound, (counts.get(upperBound) ?? 0) + value);
+    }
+  }
+
+  const buckets: HeatMapBucket[] = [];
+  for (const [time, counts] of countsByTime) {
+    const bounds = [...counts.keys()].sort((a, b) => a - b);
+    let previousCount = 0;
+    for (let i = 0; i < bounds.length; i++) {
+      const upperBound = bounds[i]!;
+      const cumulativeCount = counts.get(upperBound)!;
+      const count = Math.max(cumulativeCount - previousCount, 0);
+      previousCount = Math.max(cumulativeCount, previousCount);
+      if (upperBound === Infinity) {
+        continue;
+      }
+      // The first bucket starts at 0 for positive bounds, otherwise it is as wide as the next one
+      const lowerBound =
+        i > 0 ? bounds[i - 1]! : upperBound > 0 ? 0 : upperBound - ((bounds[1] ?? upperBound + 1) - upperBound);
+      buckets.push({ time, lowerBound, upperBound, count, label: String(count) });
+    }
+  }
+  return buckets;
+}
+
+/**
+ * Buckets the values of the raw series client-side, counting at each timestamp the series whose value falls in each
+ * bucket. With a count, the buckets split the range of all the values; with a size, they are aligned on multiples of
+ * the size.
+ */
+export function getRawSeriesBuckets(series: TimeSeries[], layout?: HeatMapBucketLayout): HeatMapBucket[] {
+  let min = Infinity;
+  let max = -Infinity;
+  for (const s of series) {
+    for (const [, value] of s.values) {
+      if (value !== null && Number.isFinite(value)) {
+        min = Math.min(min, value);
+        max = Math.max(max, value);
+      }
+    }
+  }
+  if (min === Infinity) {
+    return [];
+  }
+
+  let start = min;
+  let size: number;
+  let bucketCount: number | undefined;
+  if (layout !== undefined && 'size' in layout) {
+    size = layout.size;
+    start = Math.floor(min / size) * size;
+  } else {
+    bucketCount = layout?.count ?? DEFAULT_BUCKET_COUNT;
+    // A single value spreads over a bucket of size 1
+    size = max > min ? (max - min) / bucketCount : 1;
+  }
+
+  // time -> bucket index -> count
+  const countsByTime = new Map<number, Map<number, number>>();
+  for (const s of series) {
+    for (const [time, value] of s.values) {
+      if (value === null || !Number.isFinite(value)) {
+        continue;
+      }
+      let index = Math.floor((value - start) / size);
+      if (bucketCount !== undefined) {
+        // the max value falls in the last bucket
+        index = Math.min(index, bucketCount - 1);
+      }
+      let counts = countsByTime.get(time);
+      if (counts === undefined) {
+        counts = new Map();
+        countsByTime.set(time, counts);
+      }
+      counts.set(index, (counts.get(index) ?? 0) + 1);
+    }
+  }
+
+  const buckets: HeatMapBucket[] = [];
+  for (const [time, counts] of countsByTime) {
+    for (const [index, count] of counts) {
+      const lowerBound = start + index * size;
+      buckets.push({ time, lowerBound, upperBound: lowerBound + size, count, label: String(count) });
+    }
+  }
+  return buckets;
+}
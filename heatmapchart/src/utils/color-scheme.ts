@@ -0,0 +1,76 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the \"License\");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an \"AS IS\" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import {
+  DEFAULT_DIVERGING_PALETTE,
+  DEFAULT_SEQUENTIAL_PALETTE,
+  DIVERGING_PALETTES,
+  HeatMapColorScheme,
+  SEQUENTIAL_PALETTES,
+} from '../heat-map-chart-model';
+
+// Counts below the first step of a custom color scheme are not drawn
+const OUT_OF_RANGE_COLOR = 'rgba(0, 0, 0, 0)';
+
+export type HeatMapVisualMapColors =
+  | { type: 'continuous'; min: number; max: number; inRange: { color: string[] } }
+  | {
+      type: 'piecewise';
+      pieces: Array<{ gte: number; lt?: number; color: string }>;
+      outOfRange: { color: string };
+    };
+
+/**
+ * Gets the part of the ECharts visual map coloring the counts, between countMin and countMax, with the color scheme.
+ * Without a scheme, the cells are colored with the blue->yellow->red diverging palette.
+ */
+export function getVisualMapColors(
+  colorScheme: HeatMapColorScheme | undefined,
+  countMin: number,
+  countMax: number
+): HeatMapVisualMapColors {
+  switch (colorScheme?.mode) {
+    case 'custom':
+      return {
+        type: 'piecewise',
+        pieces: colorScheme.steps.map((step, index) => ({
+          gte: step.value,
+          lt: colorScheme.steps[index + 1]?.value,
+          color: step.color,
+        })),
+        outOfRange: { color: OUT_OF_RANGE_COLOR },
+      };
+    case 'sequential':
+      return {
+        type: 'continuous',
+        min: countMin,
+        max: countMax,
+        inRange: { color: SEQUENTIAL_PALETTES[colorScheme.palette ?? DEFAULT_SEQUENTIAL_PALETTE] },
+      };
+    default: {
+      const palette = DIVERGING_PALETTES[colorScheme?.palette ?? DEFAULT_DIVERGING_PALETTE];
+      const midpoint = colorScheme?.midpoint;
+      if (midpoint === undefined) {
+        return { type: 'continuous', min: countMin, max: countMax, inRange: { color: palette } };
+      }
+      // The range is made symmetric around the midpoint so that it gets the middle color
+      const distance = Math.max(Math.abs(countMax - midpoint), Math.abs(midpoint - countMin));
+      return {
+        type: 'continuous',
+        min: midpoint - distance,
+        max: midpoint + distance,
+        inRange: { color: palette },
+      };
+    }
+  }
+}
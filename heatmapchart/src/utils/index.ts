@@ -11,6 +11,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+export * from './buckets';
+export * from './color-scheme';
 export * from './data-transform';
 export * from './get-formatted-axis-label';
 export * from './thresholds';
@@ -27,4 +27,26 @@ spec: close({
 	}
 	thresholds?: common.#thresholds
 	logBase?:    2 | 10
+	// auto reads native histograms when the results have some, and raw series otherwise
+	input?: "auto" | "native-histogram" | "raw-series"
+	// the bucket settings only apply to raw series: either a number of buckets over the range of the values,
+	// or a size of bucket starting at the offset
+	bucketCount?:  int & >0
+	bucketSize?:   number & >0
+	bucketOffset?: number
+	if bucketCount != _|_ {
+		bucketSize?: _|_ // bucketCount and bucketSize can't be both set
+	}
+	if bucketOffset != _|_ {
+		bucketSize: number
+	}
+	if logBase != _|_ {
+		bucketSize?: _|_ // the log buckets are spread evenly on the log scale, so only bucketCount applies
+		if min != _|_ {
+			min: >=0
+		}
+	}
+	if input != _|_ && (bucketCount != _|_ || bucketSize != _|_) {
+		input: "auto" | "raw-series"
+	}
 })
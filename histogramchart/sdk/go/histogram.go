@@ -14,18 +14,78 @@
 package histogram
 
 import (
+	"fmt"
+
 	"github.com/perses/perses/go-sdk/common"
 	"github.com/perses/perses/go-sdk/panel"
+	"github.com/perses/plugins/sdk/go/option"
 )
 
 const PluginKind = "HistogramChart"
 
+// InputMode is how the query results are read.
+type InputMode string
+
+const (
+	// AutoInput reads native histograms when the results have some, and raw series otherwise.
+	AutoInput InputMode = "auto"
+	// NativeHistogramInput draws the buckets of the Prometheus native histograms, one chart per series.
+	NativeHistogramInput InputMode = "native-histogram"
+	// RawSeriesInput buckets the values of all the series client-side, as set by the bucket settings.
+	RawSeriesInput InputMode = "raw-series"
+)
+
+// PluginSpec is the spec of a HistogramChart. BucketCount, BucketSize and BucketOffset only apply to raw series: the
+// values are either spread in BucketCount buckets over their range, or in buckets of BucketSize starting at
+// BucketOffset. With a LogBase, the buckets are spread evenly on the log scale, so only BucketCount applies.
 type PluginSpec struct {
-	Format     *common.Format     `json:"format,omitempty" yaml:"format,omitempty"`
-	Min        float64            `json:"min,omitempty" yaml:"min,omitempty"`
-	Max        float64            `json:"max,omitempty" yaml:"max,omitempty"`
-	Thresholds *common.Thresholds `json:"thresholds,omitempty" yaml:"thresholds,omitempty"`
-	LogBase    uint               `json:"logBase,omitempty" yaml:"logBase,omitempty"`
+	Format       *common.Format     `json:"format,omitempty" yaml:"format,omitempty"`
+	Min          float64            `json:"min,omitempty" yaml:"min,omitempty"`
+	Max          float64            `json:"max,omitempty" yaml:"max,omitempty"`
+	Thresholds   *common.Thresholds `json:"thresholds,omitempty" yaml:"thresholds,omitempty"`
+	LogBase      uint               `json:"logBase,omitempty" yaml:"logBase,omitempty"`
+	Input        InputMode          `json:"input,omitempty" yaml:"input,omitempty"`
+	BucketCount  uint               `json:"bucketCount,omitempty" yaml:"bucketCount,omitempty"`
+	BucketSize   float64            `json:"bucketSize,omitempty" yaml:"bucketSize,omitempty"`
+	BucketOffset float64            `json:"bucketOffset,omitempty" yaml:"bucketOffset,omitempty"`
+}
+
+func (s *PluginSpec) validate() error {
+	if s.Min != 0 && s.Max != 0 && s.Max < s.Min {
+		return fmt.Errorf("max (%g) must be greater than or equal to min (%g)", s.Max, s.Min)
+	}
+	switch s.LogBase {
+	case 0, 2, 10:
+	default:
+		return fmt.Errorf("logBase must be 2 or 10, got %d", s.LogBase)
+	}
+	switch s.Input {
+	case "", AutoInput, NativeHistogramInput, RawSeriesInput:
+	default:
+		return fmt.Errorf("unknown input %q", s.Input)
+	}
+	hasBucketSettings := s.BucketCount > 0 || s.BucketSize != 0 || s.BucketOffset != 0
+	if hasBucketSettings && s.Input == NativeHistogramInput {
+		return fmt.Errorf("the bucket settings only apply to raw series, not to the %s input", s.Input)
+	}
+	if s.BucketCount > 0 && s.BucketSize != 0 {
+		return fmt.Errorf("bucketCount and bucketSize cannot be both set")
+	}
+	if s.BucketSize < 0 {
+		return fmt.Errorf("bucketSize must be positive, got %g", s.BucketSize)
+	}
+	if s.BucketOffset != 0 && s.BucketSize == 0 {
+		return fmt.Errorf("bucketOffset requires a bucketSize")
+	}
+	if s.LogBase > 0 {
+		if s.BucketSize != 0 {
+			return fmt.Errorf("bucketSize cannot be used with a logBase, the log buckets are set by bucketCount")
+		}
+		if s.Min < 0 {
+			return fmt.Errorf("min must be positive when a logBase is set, got %g", s.Min)
+		}
+	}
+	return nil
 }
 
 type Option func(plugin *Builder) error
@@ -43,10 +103,8 @@ func create(options ...Option) (Builder, error) {
 		Format(common.Format{Unit: &unit, DecimalPlaces: 2}),
 	}
 
-	for _, opt := range append(defaults, options...) {
-		if err := opt(builder); err != nil {
-			return *builder, err
-		}
+	if err := option.ApplyAndValidate(PluginKind, builder, append(defaults, options...), builder.validate); err != nil {
+		return *builder, err
 	}
 
 	return *builder, nil
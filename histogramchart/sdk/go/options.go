@@ -51,3 +51,27 @@ func WithLogBase(logBase uint) Option {
 		return nil
 	}
 }
+
+func WithInput(input InputMode) Option {
+	return func(builder *Builder) error {
+		builder.Input = input
+		return nil
+	}
+}
+
+// WithBucketCount spreads the values of the raw series in count buckets over their range.
+func WithBucketCount(count uint) Option {
+	return func(builder *Builder) error {
+		builder.BucketCount = count
+		return nil
+	}
+}
+
+// WithBucketSize buckets the values of the raw series in buckets of the size, starting at the offset.
+func WithBucketSize(size float64, offset float64) Option {
+	return func(builder *Builder) error {
+		builder.BucketSize = size
+		builder.BucketOffset = offset
+		return nil
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package histogram
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBucketSettings(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		options  []Option
+		expected string
+	}{
+		{
+			title:    "bucket count",
+			options:  []Option{WithInput(RawSeriesInput), WithBucketCount(20)},
+			expected: `"input":"raw-series","bucketCount":20`,
+		},
+		{
+			title:    "bucket size and offset",
+			options:  []Option{WithBucketSize(0.5, 0.25)},
+			expected: `"bucketSize":0.5,"bucketOffset":0.25`,
+		},
+		{
+			title:    "log buckets",
+			options:  []Option{WithLogBase(2), WithBucketCount(8)},
+			expected: `"logBase":2,"bucketCount":8`,
+		},
+		{
+			title:    "native histograms",
+			options:  []Option{WithInput(NativeHistogramInput)},
+			expected: `"input":"native-histogram"`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			builder, err := create(test.options...)
+			if err != nil {
+				t.Fatalf("create failed: %v", err)
+			}
+			jsonBytes, err := json.Marshal(builder.PluginSpec)
+			if err != nil {
+				t.Fatalf("Failed to marshal spec: %v", err)
+			}
+			if !strings.Contains(string(jsonBytes), test.expected) {
+				t.Errorf("Expected %s to contain %s", jsonBytes, test.expected)
+			}
+		})
+	}
+}
+
+func TestBucketSettings_Invalid(t *testing.T) {
+	testSuites := []struct {
+		title   string
+		options []Option
+		err     string
+	}{
+		{
+			title:   "bucket count and size",
+			options: []Option{WithBucketCount(10), WithBucketSize(5, 0)},
+			err:     "bucketCount and bucketSize cannot be both set",
+		},
+		{
+			title:   "negative bucket size",
+			options: []Option{WithBucketSize(-1, 0)},
+			err:     "bucketSize must be positive",
+		},
+		{
+			title:   "offset without size",
+			options: []Option{WithBucketCount(10), WithBucketSize(0, 2)},
+			err:     "bucketOffset requires a bucketSize",
+		},
+		{
+			title:   "bucket settings with native histograms",
+			options: []Option{WithInput(NativeHistogramInput), WithBucketCount(10)},
+			err:     "the bucket settings only apply to raw series",
+		},
+		{
+			title:   "log base with bucket size",
+			options: []Option{WithLogBase(10), WithBucketSize(5, 0)},
+			err:     "bucketSize cannot be used with a logBase",
+		},
+		{
+			title:   "log base with negative min",
+			options: []Option{WithLogBase(10), Min(-1)},
+			err:     "min must be positive when a logBase is set",
+		},
+		{
+			title:   "unknown log base",
+			options: []Option{WithLogBase(3)},
+			err:     "logBase must be 2 or 10",
+		},
+		{
+			title:   "unknown input",
+			options: []Option{WithInput("classic-buckets")},
+			err:     `unknown input "classic-buckets"`,
+		},
+		{
+			title:   "max lower than min",
+			options: []Option{Min(10), Max(5)},
+			err:     "max (5) must be greater than or equal to min (10)",
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			_, err := create(test.options...)
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if !strings.Contains(err.Error(), test.err) {
+				t.Errorf("Expected error containing %q, got %q", test.err, err.Error())
+			}
+		})
+	}
+}
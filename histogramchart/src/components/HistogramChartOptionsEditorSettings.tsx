@@ -27,6 +27,7 @@ import { produce } from 'immer';
 import merge from 'lodash/merge';
 import { ReactElement } from 'react';
 import {
+  DEFAULT_BUCKET_COUNT,
   DEFAULT_FORMAT,
   DEFAULT_MAX_PERCENT,
   DEFAULT_MAX_PERCENT_DECIMAL,
@@ -35,6 +36,8 @@ import {
   DEFAULT_THRESHOLDS,
   HistogramChartOptions,
   HistogramChartOptionsEditorProps,
+  INPUT_CONFIG,
+  INPUT_OPTIONS,
   LOG_BASE_CONFIG,
   LOG_BASE_OPTIONS,
 } from '../histogram-chart-model';
@@ -81,6 +84,25 @@ export function HistogramChartOptionsEditorSettings(props: HistogramChartOptions
     maxPlaceholder = DEFAULT_MAX_PERCENT_DECIMAL.toString();
   }
 
+  const input = value.input ?? 'auto';
+
+  // bucketCount and bucketSize can't be both set
+  const handleBucketChange = (field: 'bucketCount' | 'bucketSize' | 'bucketOffset', newValue?: number): void => {
+    onChange(
+      produce(value, (draft: HistogramChartOptions) => {
+        draft[field] = newValue;
+        if (field === 'bucketCount' && newValue !== undefined) {
+          draft.bucketSize = undefined;
+          draft.bucketOffset = undefined;
+        } else if (field === 'bucketSize' && newValue !== undefined) {
+          draft.bucketCount = undefined;
+        } else if (field === 'bucketSize') {
+          draft.bucketOffset = undefined;
+        }
+      })
+    );
+  };
+
   return (
     <OptionsEditorGrid>
       <OptionsEditorColumn>
@@ -139,6 +161,37 @@ export function HistogramChartOptionsEditorSettings(props: HistogramChartOptions
                   onChange(
                     produce(value, (draft: HistogramChartOptions) => {
                       draft.logBase = newValue.log;
+                      // the log buckets are spread evenly on the log scale, so only the bucket count applies
+                      if (newValue.log !== undefined) {
+                        draft.bucketSize = undefined;
+                        draft.bucketOffset = undefined;
+                      }
+                    })
+                  );
+                }}
+                disableClearable
+              />
+            }
+          />
+        </OptionsEditorGroup>
+        <OptionsEditorGroup title="Buckets">
+          <OptionsEditorControl
+            label="Input"
+            control={
+              <SettingsAutocomplete
+                value={{ ...INPUT_CONFIG[input], id: input }}
+                options={INPUT_OPTIONS}
+                onChange={(__, newValue) => {
+                  onChange(
+                    produce(value, (draft: HistogramChartOptions) => {
+                      // auto is the default, so the property is removed
+                      draft.input = newValue.id === 'auto' ? undefined : newValue.id;
+                      // the bucket settings only apply to raw series
+                      if (newValue.id === 'native-histogram') {
+                        draft.bucketCount = undefined;
+                        draft.bucketSize = undefined;
+                        draft.bucketOffset = undefined;
+                      }
                     })
                   );
                 }}
@@ -146,6 +199,58 @@ export function HistogramChartOptionsEditorSettings(props: HistogramChartOptions
               />
             }
           />
+          {input !== 'native-histogram' && (
+            <>
+              <OptionsEditorControl
+                label="Bucket Count"
+                control={
+                  <TextField
+                    type="number"
+                    value={value.bucketCount ?? ''}
+                    onChange={(e) => {
+                      const newValue = e.target.value ? Math.max(1, Math.round(Number(e.target.value))) : undefined;
+                      handleBucketChange('bucketCount', newValue);
+                    }}
+                    placeholder={value.bucketSize === undefined ? DEFAULT_BUCKET_COUNT.toString() : ''}
+                    sx={{ width: '100%' }}
+                  />
+                }
+              />
+              <OptionsEditorControl
+                label="Bucket Size"
+                control={
+                  <TextField
+                    type="number"
+                    value={value.bucketSize ?? ''}
+                    onChange={(e) => {
+                      const newValue = Number(e.target.value) > 0 ? Number(e.target.value) : undefined;
+                      handleBucketChange('bucketSize', newValue);
+                    }}
+                    disabled={value.logBase !== undefined}
+                    helperText={value.logBase !== undefined ? 'Not available with a log base' : undefined}
+                    placeholder="Auto"
+                    sx={{ width: '100%' }}
+                  />
+                }
+              />
+              <OptionsEditorControl
+                label="Bucket Offset"
+                control={
+                  <TextField
+                    type="number"
+                    value={value.bucketOffset ?? ''}
+                    onChange={(e) => {
+                      const newValue = e.target.value ? Number(e.target.value) : undefined;
+                      handleBucketChange('bucketOffset', newValue);
+                    }}
+                    disabled={value.bucketSize === undefined}
+                    placeholder="0"
+                    sx={{ width: '100%' }}
+                  />
+                }
+              />
+            </>
+          )}
         </OptionsEditorGroup>
       </OptionsEditorColumn>
       <OptionsEditorColumn>
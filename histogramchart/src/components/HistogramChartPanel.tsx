@@ -18,6 +18,7 @@ import merge from 'lodash/merge';
 import { ReactElement, useMemo } from 'react';
 import { useChartsTheme } from '@perses-dev/components';
 import { DEFAULT_FORMAT, DEFAULT_THRESHOLDS, HistogramChartOptions } from '../histogram-chart-model';
+import { getRawSeriesBuckets, resolveInput } from '../utils';
 import { HistogramChart, HistogramChartData } from './HistogramChart';
 
 const HISTOGRAM_MIN_WIDTH = 90;
@@ -26,7 +27,7 @@ export type HistogramChartPanelProps = PanelProps<HistogramChartOptions, TimeSer
 
 export function HistogramChartPanel(props: HistogramChartPanelProps): ReactElement | null {
   const { spec: pluginSpec, contentDimensions, queryResults } = props;
-  const { min, max } = pluginSpec;
+  const { min, max, logBase, input, bucketCount, bucketSize, bucketOffset } = pluginSpec;
 
   const chartsTheme = useChartsTheme();
   // ensures all default format properties set if undef
@@ -36,6 +37,25 @@ export function HistogramChartPanel(props: HistogramChartPanelProps): ReactEleme
   const histogramData: HistogramChartData[] = useMemo(() => {
     const histograms: HistogramChartData[] = [];
 
+    const resolvedInput = resolveInput(queryResults.flatMap((result) => result.data.series), input);
+    if (resolvedInput === 'raw-series') {
+      // one chart per query, bucketing the values of all its series
+      for (const result of queryResults) {
+        const buckets = getRawSeriesBuckets(result.data.series, {
+          min,
+          max,
+          logBase,
+          bucketCount,
+          bucketSize,
+          bucketOffset,
+        });
+        if (buckets.length > 0) {
+          histograms.push({ buckets });
+        }
+      }
+      return histograms;
+    }
+
     for (const result of queryResults) {
       for (const timeSeries of result.data.series) {
         if (!timeSeries.histograms || timeSeries.histograms.length === 0) {
@@ -49,14 +69,14 @@ export function HistogramChartPanel(props: HistogramChartPanelProps): ReactEleme
       }
     }
     return histograms;
-  }, [queryResults]);
+  }, [queryResults, input, min, max, logBase, bucketCount, bucketSize, bucketOffset]);
 
   // no data message handled inside chart component
   if (histogramData.length === 0) {
     return (
       <Stack justifyContent="center" height="100%">
         <Typography variant="body2" textAlign="center">
-          No data available (only native histograms are supported for now)
+          No data available
         </Typography>
       </Stack>
     );
@@ -92,7 +112,7 @@ export function HistogramChartPanel(props: HistogramChartPanelProps): ReactEleme
               min={min}
               max={max}
               thresholds={thresholds}
-              logBase={pluginSpec.logBase}
+              logBase={logBase}
             />
           </Box>
         );
@@ -36,6 +36,23 @@ export const LOG_BASE_OPTIONS = Object.entries(LOG_BASE_CONFIG).map(([id, config
   ...config,
 }));
 
+// auto reads native histograms when the results have some, and raw series otherwise.
+export type HistogramInput = 'auto' | 'native-histogram' | 'raw-series';
+
+export const INPUT_CONFIG: Record<HistogramInput, { label: string }> = {
+  auto: { label: 'Auto' },
+  'native-histogram': { label: 'Native histogram' },
+  'raw-series': { label: 'Raw series' },
+};
+
+// Options array for SettingsAutocomplete
+export const INPUT_OPTIONS = Object.entries(INPUT_CONFIG).map(([id, config]) => ({
+  id: id as HistogramInput,
+  ...config,
+}));
+
+export const DEFAULT_BUCKET_COUNT = 10;
+
 /**
  * The schema for a HistogramChart panel.
  */
@@ -52,6 +69,12 @@ export interface HistogramChartOptions {
   max?: number;
   thresholds?: ThresholdOptions;
   logBase?: LOG_BASE;
+  input?: HistogramInput;
+  // The bucket settings only apply to raw series: either a number of buckets over the range of the values, or a size
+  // of bucket starting at the offset. With a log base, the buckets are spread evenly on the log scale.
+  bucketCount?: number;
+  bucketSize?: number;
+  bucketOffset?: number;
 }
 
 export type HistogramChartOptionsEditorProps = OptionsEditorProps<HistogramChartOptions>;
@@ -0,0 +1,59 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the \"License\");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an \"AS IS\" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+import { TimeSeries } from '@perses-dev/core';
+import { getRawSeriesBuckets, resolveInput } from './buckets';
+
+function series(...values: Array<number | null>): TimeSeries {
+  return { name: 'up', values: values.map((value, i) => [i * 1000, value]) };
+}
+
+describe('resolveInput', () => {
+  it('should read native histograms when the series have some', () => {
+    const histogram: TimeSeries = { name: 'h', values: [], histograms: [[0, { count: 1, sum: '1', buckets: [] }]] };
+    expect(resolveInput([series(1), histogram])).toEqual('native-histogram');
+    expect(resolveInput([series(1)])).toEqual('raw-series');
+    expect(resolveInput([series(1)], 'native-histogram')).toEqual('native-histogram');
+  });
+});
+
+describe('getRawSeriesBuckets', () => {
+  it('should split the range of the values in bucketCount buckets', () => {
+    expect(getRawSeriesBuckets([series(0, 1, 2, null), series(4)], { bucketCount: 2 })).toEqual([
+      [1, '0', '2', '2'],
+      [1, '2', '4', '2'],
+    ]);
+  });
+
+  it('should align the buckets of a size on the offset', () => {
+    expect(getRawSeriesBuckets([series(1, 2, 7)], { bucketSize: 5, bucketOffset: 1 })).toEqual([
+      [1, '1', '6', '2'],
+      [1, '6', '11', '1'],
+    ]);
+  });
+
+  it('should spread the buckets evenly on the log scale', () => {
+    const buckets = getRawSeriesBuckets([series(-1, 1, 10, 100)], { logBase: 10, bucketCount: 2 });
+    expect(buckets.map(([, lower, upper, count]) => [Number(lower), Number(upper), count])).toEqual([
+      [1, expect.closeTo(10), '1'],
+      [expect.closeTo(10), expect.closeTo(100), '2'],
+    ]);
+  });
+
+  it('should ignore the values outside of min and max', () => {
+    expect(getRawSeriesBuckets([series(-5, 0, 10, 50)], { min: 0, max: 10, bucketCount: 1 })).toEqual([
+      [1, '0', '10', '2'],
+    ]);
+  });
+});
@@ -0,0 +1,108 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the \"License\");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an \"AS IS\" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+import { BucketTuple, TimeSeries } from '@perses-dev/core';
+import { DEFAULT_BUCKET_COUNT, HistogramChartOptions, HistogramInput } from '../histogram-chart-model';
+
+// Prometheus boundary rule of a bucket closed on the left and open on the right
+const LEFT_CLOSED_BOUNDARY = 1;
+
+export type BucketSettings = Pick<
+  HistogramChartOptions,
+  'min' | 'max' | 'logBase' | 'bucketCount' | 'bucketSize' | 'bucketOffset'
+>;
+
+/**
+ * Resolves the auto input: native histograms when the series have some, and raw series otherwise.
+ */
+export function resolveInput(series: TimeSeries[], input: HistogramInput = 'auto'): Exclude<HistogramInput, 'auto'> {
+  if (input !== 'auto') {
+    return input;
+  }
+  return series.some((s) => s.histograms !== undefined && s.histograms.length > 0) ? 'native-histogram' : 'raw-series';
+}
+
+/**
+ * Buckets the values of the raw series client-side. The values outside of min and max are ignored. With a size, the
+ * buckets are aligned on the offset; otherwise the range of the values is split in bucketCount buckets, evenly on the
+ * log scale when a log base is set. Only the non-empty buckets are returned, sorted by bound.
+ */
+export function getRawSeriesBuckets(series: TimeSeries[], settings: BucketSettings = {}): BucketTuple[] {
+  const { min, max, logBase, bucketSize, bucketOffset = 0 } = settings;
+  const values: number[] = [];
+  let dataMin = Infinity;
+  let dataMax = -Infinity;
+  for (const s of series) {
+    for (const [, value] of s.values) {
+      if (value === null || !Number.isFinite(value)) {
+        continue;
+      }
+      // log(0) and log(negative) are undefined
+      if (logBase !== undefined && value <= 0) {
+        continue;
+      }
+      if ((min !== undefined && value < min) || (max !== undefined && value > max)) {
+        continue;
+      }
+      values.push(value);
+      dataMin = Math.min(dataMin, value);
+      dataMax = Math.max(dataMax, value);
+    }
+  }
+  if (values.length === 0) {
+    return [];
+  }
+
+  const lowest = min !== undefined && (logBase === undefined || min > 0) ? min : dataMin;
+  const highest = max ?? dataMax;
+
+  // bucket index -> [lower bound, upper bound]
+  let getIndex: (value: number) => number;
+  let getBounds: (index: number) => [number, number];
+  if (bucketSize !== undefined && bucketSize > 0) {
+    const start = bucketOffset + Math.floor((lowest - bucketOffset) / bucketSize) * bucketSize;
+    getIndex = (value): number => Math.floor((value - start) / bucketSize);
+    getBounds = (index): [number, number] => [start + index * bucketSize, start + (index + 1) * bucketSize];
+  } else {
+    const bucketCount = settings.bucketCount ?? DEFAULT_BUCKET_COUNT;
+    if (logBase !== undefined) {
+      // a single value spreads over a bucket up to the next power of the log base
+      const logLowest = Math.log(lowest);
+      const logStep = highest > lowest ? (Math.log(highest) - logLowest) / bucketCount : Math.log(logBase);
+      getIndex = (value): number => Math.min(Math.floor((Math.log(value) - logLowest) / logStep), bucketCount - 1);
+      getBounds = (index): [number, number] => [
+        Math.exp(logLowest + index * logStep),
+        Math.exp(logLowest + (index + 1) * logStep),
+      ];
+    } else {
+      // a single value spreads over a bucket of size 1
+      const size = highest > lowest ? (highest - lowest) / bucketCount : 1;
+      // the highest value falls in the last bucket
+      getIndex = (value): number => Math.min(Math.floor((value - lowest) / size), bucketCount - 1);
+      getBounds = (index): [number, number] => [lowest + index * size, lowest + (index + 1) * size];
+    }
+  }
+
+  const counts = new Map<number, number>();
+  for (const value of values) {
+    const index = getIndex(value);
+    counts.set(index, (counts.get(index) ?? 0) + 1);
+  }
+  return [...counts.entries()]
+    .sort(([a], [b]) => a - b)
+    .map(([index, count]) => {
+      const [lowerBound, upperBound] = getBounds(index);
+      return [LEFT_CLOSED_BOUNDARY, String(lowerBound), String(upperBound), String(count)];
+    });
+}
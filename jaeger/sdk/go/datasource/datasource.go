@@ -19,6 +19,7 @@ import (
 
 	"github.com/perses/perses/go-sdk/datasource"
 	"github.com/perses/perses/pkg/model/api/v1/datasource/http"
+	"github.com/perses/plugins/sdk/go/option"
 )
 
 const PluginKind = "JaegerDatasource"
@@ -71,10 +72,8 @@ func create(options ...Option) (Builder, error) {
 		PluginSpec: PluginSpec{},
 	}
 
-	for _, opt := range options {
-		if err := opt(builder); err != nil {
-			return *builder, err
-		}
+	if err := option.Apply(PluginKind, builder, options); err != nil {
+		return *builder, err
 	}
 
 	return *builder, nil
@@ -18,10 +18,30 @@ import (
 	"github.com/perses/perses/go-sdk/query"
 	"github.com/perses/perses/pkg/model/api/v1/common"
 	"github.com/perses/perses/pkg/model/api/v1/plugin"
+	jaegerDatasource "github.com/perses/plugins/jaeger/sdk/go/datasource"
+	"github.com/perses/plugins/sdk/go/analysis"
+	"github.com/perses/plugins/sdk/go/option"
+	"github.com/perses/plugins/sdk/go/selector"
 )
 
 const PluginKind = "JaegerTraceQuery"
 
+func init() {
+	selector.Register(PluginKind, jaegerDatasource.PluginKind)
+	analysis.Register(PluginKind, analysis.Decode(func(spec PluginSpec) []analysis.Field {
+		return []analysis.Field{
+			analysis.Datasource(spec.Datasource),
+			{Path: "traceId", Value: spec.TraceID},
+			{Path: "service", Value: spec.Service},
+			{Path: "operation", Value: spec.Operation},
+			{Path: "spanKind", Value: spec.SpanKind},
+			{Path: "tags", Value: spec.Tags},
+			{Path: "minDuration", Value: spec.MinDuration},
+			{Path: "maxDuration", Value: spec.MaxDuration},
+		}
+	}))
+}
+
 type PluginSpec struct {
 	Datasource  *datasource.Selector `json:"datasource,omitempty" yaml:"datasource,omitempty"`
 	TraceID     string               `json:"traceId,omitempty" yaml:"traceId,omitempty"`
@@ -41,10 +61,10 @@ func create(options ...Option) (Builder, error) {
 		PluginSpec: PluginSpec{},
 	}
 
-	for _, opt := range options {
-		if err := opt(builder); err != nil {
-			return *builder, err
-		}
+	if err := option.ApplyAndValidate(PluginKind, builder, options, func() error {
+		return selector.Check(PluginKind, builder.Datasource)
+	}); err != nil {
+		return *builder, err
 	}
 
 	return *builder, nil
@@ -14,6 +14,7 @@
 package model
 
 import (
+	"strings"
 	"github.com/perses/shared/cue/common"
 	commonProxy "github.com/perses/shared/cue/common/proxy"
 )
@@ -23,6 +24,25 @@ import (
 kind: #kind
 spec: {
 	commonProxy.#baseHTTPDatasourceSpec
+	derivedFields?: [...#derivedField]
+}
+
+// A derived field extracts a value from each log line and renders it as a link.
+// When a datasource is selected, the link is internal and url holds the query sent to this datasource,
+// the extracted value being sent as is when url is omitted.
+#derivedField: {
+	common.#datasourceSelector
+	name:        strings.MinRunes(1)
+	matcherType: "regex" | "jsonPath"
+	matcher:     strings.MinRunes(1)
+	if matcherType == "jsonPath" {
+		matcher: =~"^\\$"
+	}
+	url?: strings.MinRunes(1)
+	if datasource == _|_ {
+		url: strings.MinRunes(1)
+	}
+	label?: string
 }
 
 #selector: common.#datasourceSelector & {_kind: #kind}
@@ -16,18 +16,67 @@ package datasource
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
+	"github.com/dlclark/regexp2"
 	"github.com/perses/perses/go-sdk/datasource"
 	"github.com/perses/perses/pkg/model/api/v1/datasource/http"
+	"github.com/perses/plugins/sdk/go/option"
 )
 
 const (
 	PluginKind = "LokiDatasource"
 )
 
+type MatcherType string
+
+const (
+	RegexMatcherType    MatcherType = "regex"
+	JSONPathMatcherType MatcherType = "jsonPath"
+)
+
+// DerivedField extracts a value from a log line and turns it into a link.
+// URL is a template where ${__value.raw} is replaced by the extracted value.
+// When Datasource is set, the link is internal and URL is used as the query sent to that datasource,
+// the extracted value being sent as is when URL is empty.
+type DerivedField struct {
+	Name        string               `json:"name" yaml:"name"`
+	MatcherType MatcherType          `json:"matcherType" yaml:"matcherType"`
+	Matcher     string               `json:"matcher" yaml:"matcher"`
+	URL         string               `json:"url,omitempty" yaml:"url,omitempty"`
+	Datasource  *datasource.Selector `json:"datasource,omitempty" yaml:"datasource,omitempty"`
+	Label       string               `json:"label,omitempty" yaml:"label,omitempty"`
+}
+
+func (f *DerivedField) validate() error {
+	if len(f.Name) == 0 {
+		return fmt.Errorf("derived field name cannot be empty")
+	}
+	if len(f.Matcher) == 0 {
+		return fmt.Errorf("matcher of the derived field %q cannot be empty", f.Name)
+	}
+	switch f.MatcherType {
+	case RegexMatcherType:
+		if _, err := regexp2.Compile(f.Matcher, regexp2.ECMAScript); err != nil {
+			return fmt.Errorf("invalid regex matcher for the derived field %q, it must be a valid JavaScript regex: %w", f.Name, err)
+		}
+	case JSONPathMatcherType:
+		if !strings.HasPrefix(f.Matcher, "$") {
+			return fmt.Errorf("invalid JSON path matcher for the derived field %q: it must start with '$'", f.Name)
+		}
+	default:
+		return fmt.Errorf("unknown matcherType %q for the derived field %q", f.MatcherType, f.Name)
+	}
+	if len(f.URL) == 0 && f.Datasource == nil {
+		return fmt.Errorf("url of the derived field %q cannot be empty when no datasource is set", f.Name)
+	}
+	return nil
+}
+
 type PluginSpec struct {
-	DirectURL string      `json:"directUrl,omitempty" yaml:"directUrl,omitempty"`
-	Proxy     *http.Proxy `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+	DirectURL     string         `json:"directUrl,omitempty" yaml:"directUrl,omitempty"`
+	Proxy         *http.Proxy    `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+	DerivedFields []DerivedField `json:"derivedFields,omitempty" yaml:"derivedFields,omitempty"`
 }
 
 func (s *PluginSpec) UnmarshalJSON(data []byte) error {
@@ -63,6 +112,22 @@ func (s *PluginSpec) validate() error {
 	if len(s.DirectURL) > 0 && s.Proxy != nil {
 		return fmt.Errorf("at most directUrl or proxy must be configured")
 	}
+	return validateDerivedFields(s.DerivedFields)
+}
+
+// validateDerivedFields checks each derived field and that their names are unique.
+func validateDerivedFields(fields []DerivedField) error {
+	names := make(map[string]bool, len(fields))
+	for i := range fields {
+		field := &fields[i]
+		if err := field.validate(); err != nil {
+			return err
+		}
+		if names[field.Name] {
+			return fmt.Errorf("derived field %q is defined more than once", field.Name)
+		}
+		names[field.Name] = true
+	}
 	return nil
 }
 
@@ -75,10 +140,8 @@ func create(options ...Option) (Builder, error) {
 
 	var defaults []Option
 
-	for _, opt := range append(defaults, options...) {
-		if err := opt(builder); err != nil {
-			return *builder, err
-		}
+	if err := option.Apply(PluginKind, builder, append(defaults, options...)); err != nil {
+		return *builder, err
 	}
 
 	return *builder, nil
@@ -14,6 +14,8 @@
 package datasource
 
 import (
+	"fmt"
+
 	"github.com/perses/perses/go-sdk/http"
 )
 
@@ -34,3 +36,50 @@ func HTTPProxy(url string, options ...http.Option) Option {
 		return nil
 	}
 }
+
+func WithDerivedFields(fields []DerivedField) Option {
+	return func(builder *Builder) error {
+		if err := validateDerivedFields(fields); err != nil {
+			return err
+		}
+		builder.DerivedFields = fields
+		return nil
+	}
+}
+
+func WithDerivedField(field DerivedField) Option {
+	return func(builder *Builder) error {
+		if err := field.validate(); err != nil {
+			return err
+		}
+		for _, f := range builder.DerivedFields {
+			if f.Name == field.Name {
+				return fmt.Errorf("derived field %q is defined more than once", field.Name)
+			}
+		}
+		builder.DerivedFields = append(builder.DerivedFields, field)
+		return nil
+	}
+}
+
+// RegexDerivedField returns a derived field extracting the first capture group of the given regex
+// (or the whole match when there is no group) and linking it to the given URL template.
+func RegexDerivedField(name string, regex string, url string) DerivedField {
+	return DerivedField{
+		Name:        name,
+		MatcherType: RegexMatcherType,
+		Matcher:     regex,
+		URL:         url,
+	}
+}
+
+// JSONPathDerivedField returns a derived field extracting the value at the given JSON path of a JSON log line
+// and linking it to the given URL template.
+func JSONPathDerivedField(name string, path string, url string) DerivedField {
+	return DerivedField{
+		Name:        name,
+		MatcherType: JSONPathMatcherType,
+		Matcher:     path,
+		URL:         url,
+	}
+}
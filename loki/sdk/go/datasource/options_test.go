@@ -0,0 +1,156 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasource
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/perses/perses/go-sdk/datasource"
+)
+
+func TestLokiOptions(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		options  []Option
+		expected string
+	}{
+		{
+			title: "regex and JSON path derived fields",
+			options: []Option{
+				DirectURL("http://localhost:3100"),
+				WithDerivedField(RegexDerivedField("traceID", `traceID=(\w+)`, "https://tempo.example.com/trace/${__value.raw}")),
+				WithDerivedField(JSONPathDerivedField("user", "$.user.id", "https://admin.example.com/users/${__value.raw}")),
+			},
+			expected: `{"directUrl":"http://localhost:3100","derivedFields":[{"name":"traceID","matcherType":"regex","matcher":"traceID=(\\w+)","url":"https://tempo.example.com/trace/${__value.raw}"},{"name":"user","matcherType":"jsonPath","matcher":"$.user.id","url":"https://admin.example.com/users/${__value.raw}"}]}`,
+		},
+		{
+			title: "internal link to a datasource",
+			options: []Option{
+				DirectURL("http://localhost:3100"),
+				WithDerivedFields([]DerivedField{
+					{
+						Name:        "traceID",
+						MatcherType: RegexMatcherType,
+						Matcher:     `traceID=(\w+)`,
+						URL:         "${__value.raw}",
+						Datasource:  &datasource.Selector{Kind: "TempoDatasource", Name: "tempo"},
+						Label:       "Open trace",
+					},
+				}),
+			},
+			expected: `{"directUrl":"http://localhost:3100","derivedFields":[{"name":"traceID","matcherType":"regex","matcher":"traceID=(\\w+)","url":"${__value.raw}","datasource":{"kind":"TempoDatasource","name":"tempo"},"label":"Open trace"}]}`,
+		},
+		{
+			title: "JavaScript regex and internal link without url",
+			options: []Option{
+				DirectURL("http://localhost:3100"),
+				WithDerivedField(DerivedField{
+					Name:        "traceID",
+					MatcherType: RegexMatcherType,
+					Matcher:     `^(?!debug).*traceID=(\w+)`,
+					Datasource:  &datasource.Selector{Kind: "TempoDatasource"},
+				}),
+			},
+			expected: `{"directUrl":"http://localhost:3100","derivedFields":[{"name":"traceID","matcherType":"regex","matcher":"^(?!debug).*traceID=(\\w+)","datasource":{"kind":"TempoDatasource"}}]}`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			builder, err := create(test.options...)
+			if err != nil {
+				t.Fatalf("create failed: %v", err)
+			}
+			jsonBytes, err := json.Marshal(builder.PluginSpec)
+			if err != nil {
+				t.Fatalf("Failed to marshal spec: %v", err)
+			}
+			if string(jsonBytes) != test.expected {
+				t.Errorf("Expected %s, got %s", test.expected, jsonBytes)
+			}
+			// the built spec must be accepted when decoded
+			var spec PluginSpec
+			if err := json.Unmarshal(jsonBytes, &spec); err != nil {
+				t.Errorf("Failed to unmarshal the built spec: %v", err)
+			}
+		})
+	}
+}
+
+func TestLokiOptions_Invalid(t *testing.T) {
+	testSuites := []struct {
+		title   string
+		options []Option
+		errMsg  string
+	}{
+		{
+			title: "duplicate names in WithDerivedFields",
+			options: []Option{WithDerivedFields([]DerivedField{
+				RegexDerivedField("traceID", `traceID=(\w+)`, "https://tempo.example.com/trace/${__value.raw}"),
+				JSONPathDerivedField("traceID", "$.traceID", "https://tempo.example.com/trace/${__value.raw}"),
+			})},
+			errMsg: `derived field "traceID" is defined more than once`,
+		},
+		{
+			title: "duplicate names in WithDerivedField",
+			options: []Option{
+				WithDerivedFields([]DerivedField{RegexDerivedField("traceID", `traceID=(\w+)`, "https://tempo.example.com/trace/${__value.raw}")}),
+				WithDerivedField(JSONPathDerivedField("traceID", "$.traceID", "https://tempo.example.com/trace/${__value.raw}")),
+			},
+			errMsg: `derived field "traceID" is defined more than once`,
+		},
+		{
+			title:   "empty name",
+			options: []Option{WithDerivedField(RegexDerivedField("", "id=(\\d+)", "https://example.com/${__value.raw}"))},
+			errMsg:  "derived field name cannot be empty",
+		},
+		{
+			title:   "invalid regex",
+			options: []Option{WithDerivedField(RegexDerivedField("id", "id=(\\d+", "https://example.com/${__value.raw}"))},
+			errMsg:  `invalid regex matcher for the derived field "id"`,
+		},
+		{
+			title:   "regex valid in Go but not in JavaScript",
+			options: []Option{WithDerivedField(RegexDerivedField("id", `(?P<id>\d+)`, "https://example.com/${__value.raw}"))},
+			errMsg:  `invalid regex matcher for the derived field "id", it must be a valid JavaScript regex`,
+		},
+		{
+			title:   "JSON path not starting with $",
+			options: []Option{WithDerivedField(JSONPathDerivedField("id", "user.id", "https://example.com/${__value.raw}"))},
+			errMsg:  `invalid JSON path matcher for the derived field "id"`,
+		},
+		{
+			title:   "empty url",
+			options: []Option{WithDerivedField(RegexDerivedField("id", "id=(\\d+)", ""))},
+			errMsg:  `url of the derived field "id" cannot be empty when no datasource is set`,
+		},
+		{
+			title:   "unknown matcher type",
+			options: []Option{WithDerivedField(DerivedField{Name: "id", MatcherType: "logfmt", Matcher: "id", URL: "https://example.com/${__value.raw}"})},
+			errMsg:  `unknown matcherType "logfmt" for the derived field "id"`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			_, err := create(test.options...)
+			if err == nil {
+				t.Fatalf("expected an error containing %q, got nil", test.errMsg)
+			}
+			if !strings.Contains(err.Error(), test.errMsg) {
+				t.Errorf("expected an error containing %q, got %q", test.errMsg, err.Error())
+			}
+		})
+	}
+}
@@ -18,10 +18,21 @@ import (
 	"github.com/perses/perses/go-sdk/query"
 	"github.com/perses/perses/pkg/model/api/v1/common"
 	"github.com/perses/perses/pkg/model/api/v1/plugin"
+	lokiDatasource "github.com/perses/plugins/loki/sdk/go/datasource"
+	"github.com/perses/plugins/sdk/go/analysis"
+	"github.com/perses/plugins/sdk/go/option"
+	"github.com/perses/plugins/sdk/go/selector"
 )
 
 const PluginKind = "LokiLogQuery"
 
+func init() {
+	selector.Register(PluginKind, lokiDatasource.PluginKind)
+	analysis.Register(PluginKind, analysis.Decode(func(spec PluginSpec) []analysis.Field {
+		return []analysis.Field{analysis.Datasource(spec.Datasource), {Path: "query", Value: spec.Query}}
+	}))
+}
+
 type Direction string
 
 const (
@@ -46,10 +57,10 @@ func create(query string, options ...Option) (Builder, error) {
 		Query(query),
 	}
 
-	for _, opt := range append(defaults, options...) {
-		if err := opt(builder); err != nil {
-			return *builder, err
-		}
+	if err := option.ApplyAndValidate(PluginKind, builder, append(defaults, options...), func() error {
+		return selector.Check(PluginKind, builder.Datasource)
+	}); err != nil {
+		return *builder, err
 	}
 
 	return *builder, nil
@@ -11,9 +11,22 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-import { HTTPProxy } from '@perses-dev/core';
+import { DatasourceSelector, HTTPProxy } from '@perses-dev/core';
+
+export type LokiDerivedFieldMatcherType = 'regex' | 'jsonPath';
+
+export interface LokiDerivedField {
+  name: string;
+  matcherType: LokiDerivedFieldMatcherType;
+  matcher: string;
+  // optional when datasource is set, the extracted value being then the query sent to the datasource
+  url?: string;
+  datasource?: DatasourceSelector;
+  label?: string;
+}
 
 export interface LokiDatasourceSpec {
   directUrl?: string;
   proxy?: HTTPProxy;
+  derivedFields?: LokiDerivedField[];
 }
@@ -13,10 +13,20 @@
 
 package markdown
 
-import "github.com/perses/perses/go-sdk/panel"
+import (
+	"github.com/perses/perses/go-sdk/panel"
+	"github.com/perses/plugins/sdk/go/analysis"
+	"github.com/perses/plugins/sdk/go/option"
+)
 
 const PluginKind = "Markdown"
 
+func init() {
+	analysis.Register(PluginKind, analysis.Decode(func(spec PluginSpec) []analysis.Field {
+		return []analysis.Field{{Path: "text", Value: spec.Text, Text: true}}
+	}))
+}
+
 type PluginSpec struct {
 	Text string `json:"text" yaml:"text"`
 }
@@ -36,10 +46,8 @@ func create(text string, options ...Option) (Builder, error) {
 		Text(text),
 	}
 
-	for _, opt := range append(defaults, options...) {
-		if err := opt(builder); err != nil {
-			return *builder, err
-		}
+	if err := option.Apply(PluginKind, builder, append(defaults, options...)); err != nil {
+		return *builder, err
 	}
 
 	return *builder, nil
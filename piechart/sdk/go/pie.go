@@ -16,6 +16,7 @@ package pie
 import (
 	"github.com/perses/perses/go-sdk/common"
 	"github.com/perses/perses/go-sdk/panel"
+	"github.com/perses/plugins/sdk/go/option"
 )
 
 const PluginKind = "PieChart"
@@ -140,10 +141,8 @@ func create(options ...Option) (Builder, error) {
 		Calculation(common.LastCalculation),
 	}
 
-	for _, opt := range append(defaults, options...) {
-		if err := opt(builder); err != nil {
-			return *builder, err
-		}
+	if err := option.Apply(PluginKind, builder, append(defaults, options...)); err != nil {
+		return *builder, err
 	}
 
 	return *builder, nil
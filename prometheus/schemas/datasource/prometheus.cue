@@ -22,9 +22,38 @@ kind: #kind
 spec: {
 	commonProxy.#baseHTTPDatasourceSpec
 	scrapeInterval?: =~#durationRegex
+	flavor?:         "prometheus" | "thanos" | "mimir" | "victoriametrics"
+	thanos?:         #thanos
+	if thanos != _|_ {
+		flavor: "thanos"
+	}
+	mimir?: #mimir
+	if mimir != _|_ {
+		flavor: "mimir"
+	}
+	victoriaMetrics?: #victoriaMetrics
+	if victoriaMetrics != _|_ {
+		flavor: "victoriametrics"
+	}
+	// raw query parameters, prefer the typed flavor options when they cover the parameter
 	queryParams?: {[string]: string}
 }
 
+#thanos: {
+	dedup?:               bool
+	partialResponse?:     bool
+	maxSourceResolution?: "auto" | =~#durationRegex
+}
+
+#mimir: {
+	disableResultsCache?: bool
+}
+
+#victoriaMetrics: {
+	extraLabels?: {[=~"^[a-zA-Z_][a-zA-Z0-9_]*$"]: string}
+	noCache?: bool
+}
+
 #kind: "PrometheusDatasource"
 
 #durationRegex: "^(\\d+y)?(\\d+w)?(\\d+d)?(\\d+h)?(\\d+m)?(\\d+s)?(\\d+ms)?$"
@@ -16,21 +16,111 @@ package datasource
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 
 	"github.com/perses/perses/go-sdk/datasource"
 	"github.com/perses/perses/pkg/model/api/v1/common"
 	"github.com/perses/perses/pkg/model/api/v1/datasource/http"
+	"github.com/perses/plugins/sdk/go/option"
 )
 
 const (
 	PluginKind = "PrometheusDatasource"
 )
 
+var labelNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Flavor is the Prometheus-compatible implementation the datasource talks to.
+type Flavor string
+
+const (
+	PrometheusFlavor      Flavor = "prometheus"
+	ThanosFlavor          Flavor = "thanos"
+	MimirFlavor           Flavor = "mimir"
+	VictoriaMetricsFlavor Flavor = "victoriametrics"
+)
+
+// AutoResolution lets Thanos pick the downsampling resolution from the query step.
+const AutoResolution = "auto"
+
+type ThanosOptions struct {
+	// Dedup is sent as the `dedup` query parameter.
+	Dedup *bool `json:"dedup,omitempty" yaml:"dedup,omitempty"`
+	// PartialResponse is sent as the `partial_response` query parameter.
+	PartialResponse *bool `json:"partialResponse,omitempty" yaml:"partialResponse,omitempty"`
+	// MaxSourceResolution is sent as the `max_source_resolution` query parameter.
+	// It is either "auto" or a duration such as "0s", "5m" or "1h".
+	MaxSourceResolution string `json:"maxSourceResolution,omitempty" yaml:"maxSourceResolution,omitempty"`
+}
+
+func (o *ThanosOptions) validate() error {
+	if len(o.MaxSourceResolution) > 0 && o.MaxSourceResolution != AutoResolution {
+		if _, err := common.ParseDuration(o.MaxSourceResolution); err != nil {
+			return fmt.Errorf("invalid thanos maxSourceResolution %q: it must be %q or a duration", o.MaxSourceResolution, AutoResolution)
+		}
+	}
+	return nil
+}
+
+// managedQueryParams returns the query parameters set by these options.
+func (o *ThanosOptions) managedQueryParams() []string {
+	var params []string
+	if o.Dedup != nil {
+		params = append(params, "dedup")
+	}
+	if o.PartialResponse != nil {
+		params = append(params, "partial_response")
+	}
+	if len(o.MaxSourceResolution) > 0 {
+		params = append(params, "max_source_resolution")
+	}
+	return params
+}
+
+type MimirOptions struct {
+	// DisableResultsCache bypasses the query-frontend results cache by sending the `Cache-Control: no-store` header.
+	DisableResultsCache bool `json:"disableResultsCache,omitempty" yaml:"disableResultsCache,omitempty"`
+}
+
+type VictoriaMetricsOptions struct {
+	// ExtraLabels are sent as `extra_label=<name>=<value>` query parameters to enforce label filters on every query.
+	ExtraLabels map[string]string `json:"extraLabels,omitempty" yaml:"extraLabels,omitempty"`
+	// NoCache is sent as the `nocache=1` query parameter to bypass the rollup result cache.
+	NoCache bool `json:"noCache,omitempty" yaml:"noCache,omitempty"`
+}
+
+func (o *VictoriaMetricsOptions) validate() error {
+	for name := range o.ExtraLabels {
+		if !labelNameRegexp.MatchString(name) {
+			return fmt.Errorf("invalid victoriaMetrics extra label name %q", name)
+		}
+	}
+	return nil
+}
+
+// managedQueryParams returns the query parameters set by these options.
+func (o *VictoriaMetricsOptions) managedQueryParams() []string {
+	var params []string
+	if len(o.ExtraLabels) > 0 {
+		params = append(params, "extra_label")
+	}
+	if o.NoCache {
+		params = append(params, "nocache")
+	}
+	return params
+}
+
 type PluginSpec struct {
-	DirectURL      string            `json:"directUrl,omitempty" yaml:"directUrl,omitempty"`
-	Proxy          *http.Proxy       `json:"proxy,omitempty" yaml:"proxy,omitempty"`
-	ScrapeInterval common.Duration   `json:"scrapeInterval,omitempty" yaml:"scrapeInterval,omitempty"`
-	QueryParams    map[string]string `json:"queryParams,omitempty" yaml:"queryParams,omitempty"`
+	DirectURL       string                  `json:"directUrl,omitempty" yaml:"directUrl,omitempty"`
+	Proxy           *http.Proxy             `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+	ScrapeInterval  common.Duration         `json:"scrapeInterval,omitempty" yaml:"scrapeInterval,omitempty"`
+	Flavor          Flavor                  `json:"flavor,omitempty" yaml:"flavor,omitempty"`
+	Thanos          *ThanosOptions          `json:"thanos,omitempty" yaml:"thanos,omitempty"`
+	Mimir           *MimirOptions           `json:"mimir,omitempty" yaml:"mimir,omitempty"`
+	VictoriaMetrics *VictoriaMetricsOptions `json:"victoriaMetrics,omitempty" yaml:"victoriaMetrics,omitempty"`
+	// QueryParams are raw query parameters appended to every request.
+	// Prefer the typed flavor options when they cover the parameter.
+	QueryParams map[string]string `json:"queryParams,omitempty" yaml:"queryParams,omitempty"`
 }
 
 func (s *PluginSpec) UnmarshalJSON(data []byte) error {
@@ -66,6 +156,61 @@ func (s *PluginSpec) validate() error {
 	if len(s.DirectURL) > 0 && s.Proxy != nil {
 		return fmt.Errorf("at most directUrl or proxy must be configured")
 	}
+	return s.validateFlavor()
+}
+
+// GetFlavor returns the flavor of the datasource. When it is omitted, it is inferred from the typed options,
+// as the CUE schema does.
+func (s *PluginSpec) GetFlavor() Flavor {
+	switch {
+	case len(s.Flavor) > 0:
+		return s.Flavor
+	case s.Thanos != nil:
+		return ThanosFlavor
+	case s.Mimir != nil:
+		return MimirFlavor
+	case s.VictoriaMetrics != nil:
+		return VictoriaMetricsFlavor
+	}
+	return ""
+}
+
+// validateFlavor checks that the typed options match the flavor, inferred when it is omitted, and don't conflict
+// with the raw query parameters.
+func (s *PluginSpec) validateFlavor() error {
+	flavor := s.GetFlavor()
+	switch flavor {
+	case "", PrometheusFlavor, ThanosFlavor, MimirFlavor, VictoriaMetricsFlavor:
+	default:
+		return fmt.Errorf("unknown flavor %q", flavor)
+	}
+	var managedParams []string
+	if s.Thanos != nil {
+		if flavor != ThanosFlavor {
+			return fmt.Errorf("thanos options require the flavor %q, got %q", ThanosFlavor, flavor)
+		}
+		if err := s.Thanos.validate(); err != nil {
+			return err
+		}
+		managedParams = s.Thanos.managedQueryParams()
+	}
+	if s.Mimir != nil && flavor != MimirFlavor {
+		return fmt.Errorf("mimir options require the flavor %q, got %q", MimirFlavor, flavor)
+	}
+	if s.VictoriaMetrics != nil {
+		if flavor != VictoriaMetricsFlavor {
+			return fmt.Errorf("victoriaMetrics options require the flavor %q, got %q", VictoriaMetricsFlavor, flavor)
+		}
+		if err := s.VictoriaMetrics.validate(); err != nil {
+			return err
+		}
+		managedParams = s.VictoriaMetrics.managedQueryParams()
+	}
+	for _, key := range managedParams {
+		if _, ok := s.QueryParams[key]; ok {
+			return fmt.Errorf("query parameter %q is already set by the %s options, remove it from queryParams", key, flavor)
+		}
+	}
 	return nil
 }
 
@@ -78,10 +223,8 @@ func create(options ...Option) (Builder, error) {
 
 	var defaults []Option
 
-	for _, opt := range append(defaults, options...) {
-		if err := opt(builder); err != nil {
-			return *builder, err
-		}
+	if err := option.ApplyAndValidate(PluginKind, builder, append(defaults, options...), builder.validateFlavor); err != nil {
+		return *builder, err
 	}
 
 	return *builder, nil
@@ -91,6 +234,34 @@ type Builder struct {
 	PluginSpec `json:",inline" yaml:",inline"`
 }
 
+func (b *Builder) setFlavor(flavor Flavor) error {
+	if len(b.Flavor) > 0 && b.Flavor != flavor {
+		return fmt.Errorf("flavor is already set to %q, cannot switch to %q", b.Flavor, flavor)
+	}
+	b.Flavor = flavor
+	return nil
+}
+
+func (b *Builder) thanos() (*ThanosOptions, error) {
+	if err := b.setFlavor(ThanosFlavor); err != nil {
+		return nil, err
+	}
+	if b.Thanos == nil {
+		b.Thanos = &ThanosOptions{}
+	}
+	return b.Thanos, nil
+}
+
+func (b *Builder) victoriaMetrics() (*VictoriaMetricsOptions, error) {
+	if err := b.setFlavor(VictoriaMetricsFlavor); err != nil {
+		return nil, err
+	}
+	if b.VictoriaMetrics == nil {
+		b.VictoriaMetrics = &VictoriaMetricsOptions{}
+	}
+	return b.VictoriaMetrics, nil
+}
+
 func Prometheus(options ...Option) datasource.Option {
 	return func(builder *datasource.Builder) error {
 		plugin, err := create(options...)
@@ -51,3 +51,116 @@ func QueryParam(key, value string) Option {
 		return nil
 	}
 }
+
+func WithFlavor(flavor Flavor) Option {
+	return func(builder *Builder) error {
+		return builder.setFlavor(flavor)
+	}
+}
+
+func Thanos(options ThanosOptions) Option {
+	return func(builder *Builder) error {
+		if err := builder.setFlavor(ThanosFlavor); err != nil {
+			return err
+		}
+		if err := options.validate(); err != nil {
+			return err
+		}
+		builder.Thanos = &options
+		return nil
+	}
+}
+
+func ThanosDedup(enabled bool) Option {
+	return func(builder *Builder) error {
+		thanos, err := builder.thanos()
+		if err != nil {
+			return err
+		}
+		thanos.Dedup = &enabled
+		return nil
+	}
+}
+
+func ThanosPartialResponse(enabled bool) Option {
+	return func(builder *Builder) error {
+		thanos, err := builder.thanos()
+		if err != nil {
+			return err
+		}
+		thanos.PartialResponse = &enabled
+		return nil
+	}
+}
+
+func ThanosMaxSourceResolution(resolution string) Option {
+	return func(builder *Builder) error {
+		thanos, err := builder.thanos()
+		if err != nil {
+			return err
+		}
+		thanos.MaxSourceResolution = resolution
+		return thanos.validate()
+	}
+}
+
+func Mimir(options MimirOptions) Option {
+	return func(builder *Builder) error {
+		if err := builder.setFlavor(MimirFlavor); err != nil {
+			return err
+		}
+		builder.Mimir = &options
+		return nil
+	}
+}
+
+func MimirDisableResultsCache() Option {
+	return func(builder *Builder) error {
+		if err := builder.setFlavor(MimirFlavor); err != nil {
+			return err
+		}
+		if builder.Mimir == nil {
+			builder.Mimir = &MimirOptions{}
+		}
+		builder.Mimir.DisableResultsCache = true
+		return nil
+	}
+}
+
+func VictoriaMetrics(options VictoriaMetricsOptions) Option {
+	return func(builder *Builder) error {
+		if err := builder.setFlavor(VictoriaMetricsFlavor); err != nil {
+			return err
+		}
+		if err := options.validate(); err != nil {
+			return err
+		}
+		builder.VictoriaMetrics = &options
+		return nil
+	}
+}
+
+func VictoriaMetricsExtraLabel(name, value string) Option {
+	return func(builder *Builder) error {
+		vm, err := builder.victoriaMetrics()
+		if err != nil {
+			return err
+		}
+		if vm.ExtraLabels == nil {
+			vm.ExtraLabels = make(map[string]string)
+		}
+		vm.ExtraLabels[name] = value
+		return vm.validate()
+	}
+}
+
+func VictoriaMetricsNoCache() Option {
+	return func(builder *Builder) error {
+		vm, err := builder.victoriaMetrics()
+		if err != nil {
+			return err
+		}
+		vm.NoCache = true
+		return nil
+	}
+}
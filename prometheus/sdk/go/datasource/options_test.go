@@ -0,0 +1,188 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasource
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPrometheusOptions(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		options  []Option
+		expected string
+	}{
+		{
+			title: "thanos options",
+			options: []Option{
+				DirectURL("http://localhost:10902"),
+				ThanosDedup(true),
+				ThanosPartialResponse(false),
+				ThanosMaxSourceResolution(AutoResolution),
+			},
+			expected: `{"directUrl":"http://localhost:10902","flavor":"thanos","thanos":{"dedup":true,"partialResponse":false,"maxSourceResolution":"auto"}}`,
+		},
+		{
+			title: "thanos options at once",
+			options: []Option{
+				DirectURL("http://localhost:10902"),
+				Thanos(ThanosOptions{MaxSourceResolution: "5m"}),
+			},
+			expected: `{"directUrl":"http://localhost:10902","flavor":"thanos","thanos":{"maxSourceResolution":"5m"}}`,
+		},
+		{
+			title: "mimir options",
+			options: []Option{
+				DirectURL("http://localhost:9009/prometheus"),
+				MimirDisableResultsCache(),
+			},
+			expected: `{"directUrl":"http://localhost:9009/prometheus","flavor":"mimir","mimir":{"disableResultsCache":true}}`,
+		},
+		{
+			title: "victoriametrics options",
+			options: []Option{
+				DirectURL("http://localhost:8428"),
+				WithFlavor(VictoriaMetricsFlavor),
+				VictoriaMetricsExtraLabel("tenant", "team-a"),
+				VictoriaMetricsNoCache(),
+				QueryParam("timeout", "30s"),
+			},
+			expected: `{"directUrl":"http://localhost:8428","flavor":"victoriametrics","victoriaMetrics":{"extraLabels":{"tenant":"team-a"},"noCache":true},"queryParams":{"timeout":"30s"}}`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			builder, err := create(test.options...)
+			if err != nil {
+				t.Fatalf("create failed: %v", err)
+			}
+			jsonBytes, err := json.Marshal(builder.PluginSpec)
+			if err != nil {
+				t.Fatalf("Failed to marshal spec: %v", err)
+			}
+			if string(jsonBytes) != test.expected {
+				t.Errorf("Expected %s, got %s", test.expected, jsonBytes)
+			}
+			// the built spec must be accepted when decoded
+			var spec PluginSpec
+			if err := json.Unmarshal(jsonBytes, &spec); err != nil {
+				t.Errorf("Failed to unmarshal the built spec: %v", err)
+			}
+		})
+	}
+}
+
+func TestPrometheusOptions_Invalid(t *testing.T) {
+	testSuites := []struct {
+		title   string
+		options []Option
+		errMsg  string
+	}{
+		{
+			title:   "conflicting flavors",
+			options: []Option{DirectURL("http://localhost:9090"), WithFlavor(MimirFlavor), ThanosDedup(true)},
+			errMsg:  `flavor is already set to "mimir", cannot switch to "thanos"`,
+		},
+		{
+			title:   "invalid thanos max source resolution",
+			options: []Option{DirectURL("http://localhost:10902"), ThanosMaxSourceResolution("raw")},
+			errMsg:  `invalid thanos maxSourceResolution "raw"`,
+		},
+		{
+			title:   "invalid victoriametrics extra label name",
+			options: []Option{DirectURL("http://localhost:8428"), VictoriaMetricsExtraLabel("team-name", "a")},
+			errMsg:  `invalid victoriaMetrics extra label name "team-name"`,
+		},
+		{
+			title:   "query parameter managed by the typed options",
+			options: []Option{DirectURL("http://localhost:8428"), VictoriaMetricsNoCache(), QueryParam("nocache", "1")},
+			errMsg:  `query parameter "nocache" is already set by the victoriametrics options`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			_, err := create(test.options...)
+			if err == nil {
+				t.Fatalf("expected an error containing %q, got nil", test.errMsg)
+			}
+			if !strings.Contains(err.Error(), test.errMsg) {
+				t.Errorf("expected an error containing %q, got %q", test.errMsg, err.Error())
+			}
+		})
+	}
+}
+
+func TestPluginSpec_UnmarshalJSON(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		input    string
+		expected Flavor
+		errMsg   string
+	}{
+		{
+			title:    "flavor inferred from the thanos options",
+			input:    `{"directUrl":"http://localhost:10902","thanos":{"dedup":true}}`,
+			expected: ThanosFlavor,
+		},
+		{
+			title:    "flavor inferred from the mimir options",
+			input:    `{"directUrl":"http://localhost:9009","mimir":{"disableResultsCache":true}}`,
+			expected: MimirFlavor,
+		},
+		{
+			title:    "flavor inferred from the victoriametrics options",
+			input:    `{"directUrl":"http://localhost:8428","victoriaMetrics":{"noCache":true}}`,
+			expected: VictoriaMetricsFlavor,
+		},
+		{
+			title:  "typed options of two flavors",
+			input:  `{"directUrl":"http://localhost:9090","thanos":{"dedup":true},"mimir":{"disableResultsCache":true}}`,
+			errMsg: `mimir options require the flavor "mimir", got "thanos"`,
+		},
+		{
+			title:  "typed options not matching the flavor",
+			input:  `{"directUrl":"http://localhost:9090","flavor":"prometheus","thanos":{"dedup":true}}`,
+			errMsg: `thanos options require the flavor "thanos", got "prometheus"`,
+		},
+		{
+			title:  "unknown flavor",
+			input:  `{"directUrl":"http://localhost:9090","flavor":"cortex"}`,
+			errMsg: `unknown flavor "cortex"`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			var spec PluginSpec
+			err := json.Unmarshal([]byte(test.input), &spec)
+			if len(test.errMsg) > 0 {
+				if err == nil || !strings.Contains(err.Error(), test.errMsg) {
+					t.Fatalf("expected an error containing %q, got %v", test.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if spec.GetFlavor() != test.expected {
+				t.Errorf("expected flavor %q, got %q", test.expected, spec.GetFlavor())
+			}
+			// the validation must not alter the decoded spec
+			if len(spec.Flavor) > 0 {
+				t.Errorf("expected the flavor to stay omitted, got %q", spec.Flavor)
+			}
+		})
+	}
+}
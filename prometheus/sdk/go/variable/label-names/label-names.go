@@ -20,10 +20,21 @@ import (
 	"github.com/perses/perses/go-sdk/datasource"
 	list_variable "github.com/perses/perses/go-sdk/variable/list-variable"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
+	promDatasource "github.com/perses/plugins/prometheus/sdk/go/datasource"
+	"github.com/perses/plugins/sdk/go/analysis"
+	"github.com/perses/plugins/sdk/go/option"
+	"github.com/perses/plugins/sdk/go/selector"
 )
 
 const PluginKind = "PrometheusLabelNamesVariable"
 
+func init() {
+	selector.Register(PluginKind, promDatasource.PluginKind)
+	analysis.Register(PluginKind, analysis.Decode(func(spec PluginSpec) []analysis.Field {
+		return append([]analysis.Field{analysis.Datasource(spec.Datasource)}, analysis.List("matchers", spec.Matchers)...)
+	}))
+}
+
 type PluginSpec struct {
 	Datasource *datasource.Selector `json:"datasource,omitempty" yaml:"datasource,omitempty"`
 	Matchers   []string             `json:"matchers,omitempty" yaml:"matchers,omitempty"`
@@ -36,13 +47,11 @@ func create(options ...Option) (Builder, error) {
 		PluginSpec: PluginSpec{},
 	}
 
-	for _, opt := range options {
-		if err := opt(builder); err != nil {
-			return *builder, err
-		}
-	}
-
-	if err := builder.ApplyFilters(); err != nil {
+	// the filters are applied once all the matchers are set
+	steps := append(options, (*Builder).ApplyFilters)
+	if err := option.ApplyAndValidate(PluginKind, builder, steps, func() error {
+		return selector.Check(PluginKind, builder.Datasource)
+	}); err != nil {
 		return *builder, err
 	}
 
@@ -16,10 +16,21 @@ package promql
 import (
 	"github.com/perses/perses/go-sdk/datasource"
 	listvariable "github.com/perses/perses/go-sdk/variable/list-variable"
+	promDatasource "github.com/perses/plugins/prometheus/sdk/go/datasource"
+	"github.com/perses/plugins/sdk/go/analysis"
+	"github.com/perses/plugins/sdk/go/option"
+	"github.com/perses/plugins/sdk/go/selector"
 )
 
 const PluginKind = "PrometheusPromQLVariable"
 
+func init() {
+	selector.Register(PluginKind, promDatasource.PluginKind)
+	analysis.Register(PluginKind, analysis.Decode(func(spec PluginSpec) []analysis.Field {
+		return []analysis.Field{analysis.Datasource(spec.Datasource), {Path: "expr", Value: spec.Expr}, {Path: "labelName", Value: spec.LabelName}}
+	}))
+}
+
 type PluginSpec struct {
 	Datasource *datasource.Selector `json:"datasource,omitempty" yaml:"datasource,omitempty"`
 	Expr       string               `json:"expr" yaml:"expr"`
@@ -37,10 +48,10 @@ func create(expr string, options ...Option) (Builder, error) {
 		Expr(expr),
 	}
 
-	for _, opt := range append(defaults, options...) {
-		if err := opt(builder); err != nil {
-			return *builder, err
-		}
+	if err := option.ApplyAndValidate(PluginKind, builder, append(defaults, options...), func() error {
+		return selector.Check(PluginKind, builder.Datasource)
+	}); err != nil {
+		return *builder, err
 	}
 
 	return *builder, nil
@@ -0,0 +1,44 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import { getFlavor, getFlavorHeaders, getFlavorQueryParams } from './flavor';
+import { PrometheusDatasourceSpec } from './types';
+
+describe('getFlavor', () => {
+  it('should keep an explicit flavor', () => {
+    expect(getFlavor({ directUrl: 'http://localhost:9090', flavor: 'prometheus' })).toEqual('prometheus');
+  });
+
+  it('should infer the flavor from the typed options', () => {
+    expect(getFlavor({ directUrl: 'http://localhost:10902', thanos: { dedup: true } })).toEqual('thanos');
+    expect(getFlavor({ directUrl: 'http://localhost:9009', mimir: {} })).toEqual('mimir');
+    expect(getFlavor({ directUrl: 'http://localhost:8428', victoriaMetrics: { noCache: true } })).toEqual(
+      'victoriametrics'
+    );
+    expect(getFlavor({ directUrl: 'http://localhost:9090' })).toBeUndefined();
+  });
+});
+
+describe('flavor options without an explicit flavor', () => {
+  it('should apply the thanos and victoriametrics query parameters', () => {
+    const thanos: PrometheusDatasourceSpec = { directUrl: 'http://localhost:10902', thanos: { dedup: false } };
+    expect(getFlavorQueryParams(thanos)).toEqual({ dedup: 'false' });
+    const vm: PrometheusDatasourceSpec = { directUrl: 'http://localhost:8428', victoriaMetrics: { noCache: true } };
+    expect(getFlavorQueryParams(vm)).toEqual({ nocache: '1' });
+  });
+
+  it('should apply the mimir headers', () => {
+    const spec: PrometheusDatasourceSpec = { directUrl: 'http://localhost:9009', mimir: { disableResultsCache: true } };
+    expect(getFlavorHeaders(spec)).toEqual({ 'Cache-Control': 'no-store' });
+  });
+});
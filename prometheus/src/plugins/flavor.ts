@@ -0,0 +1,66 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import { QueryParamValues } from '@perses-dev/components';
+import { RequestHeaders } from '@perses-dev/core';
+import { mergeQueryParams } from '../model';
+import { PrometheusDatasourceSpec, PrometheusFlavor } from './types';
+
+/**
+ * Returns the flavor of the datasource. When it is omitted, it is inferred from the typed flavor options, as the CUE
+ * schema and the Go SDK do.
+ */
+export function getFlavor(spec: PrometheusDatasourceSpec): PrometheusFlavor | undefined {
+  if (spec.flavor !== undefined) return spec.flavor;
+  if (spec.thanos !== undefined) return 'thanos';
+  if (spec.mimir !== undefined) return 'mimir';
+  if (spec.victoriaMetrics !== undefined) return 'victoriametrics';
+  return undefined;
+}
+
+/**
+ * Returns the query parameters derived from the typed flavor options of the datasource.
+ */
+export function getFlavorQueryParams(spec: PrometheusDatasourceSpec): QueryParamValues | undefined {
+  const params: QueryParamValues = {};
+  const { thanos, victoriaMetrics } = spec;
+  const flavor = getFlavor(spec);
+  if (flavor === 'thanos' && thanos) {
+    if (thanos.dedup !== undefined) params['dedup'] = String(thanos.dedup);
+    if (thanos.partialResponse !== undefined) params['partial_response'] = String(thanos.partialResponse);
+    if (thanos.maxSourceResolution !== undefined) params['max_source_resolution'] = thanos.maxSourceResolution;
+  }
+  if (flavor === 'victoriametrics' && victoriaMetrics) {
+    const extraLabels = Object.entries(victoriaMetrics.extraLabels ?? {}).map(([name, value]) => `${name}=${value}`);
+    if (extraLabels.length > 0) params['extra_label'] = extraLabels;
+    if (victoriaMetrics.noCache) params['nocache'] = '1';
+  }
+  return Object.keys(params).length > 0 ? params : undefined;
+}
+
+/**
+ * Returns the request headers derived from the typed flavor options of the datasource.
+ */
+export function getFlavorHeaders(spec: PrometheusDatasourceSpec): RequestHeaders | undefined {
+  if (getFlavor(spec) === 'mimir' && spec.mimir?.disableResultsCache) {
+    return { 'Cache-Control': 'no-store' };
+  }
+  return undefined;
+}
+
+/**
+ * Returns the query parameters of the datasource: the ones derived from the flavor options, then the raw ones.
+ */
+export function getDatasourceQueryParams(spec: PrometheusDatasourceSpec): QueryParamValues | undefined {
+  return mergeQueryParams(getFlavorQueryParams(spec), spec.queryParams);
+}
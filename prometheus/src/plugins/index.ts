@@ -11,6 +11,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+export * from './flavor';
 export * from './MatcherEditor';
 export * from './prometheus-datasource';
 export * from './prometheus-time-series-query';
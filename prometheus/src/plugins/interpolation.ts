@@ -15,6 +15,7 @@ import { DatasourceSelector, DatasourceSpec } from '@perses-dev/core';
 import { interpolateHeaders, interpolateQueryParams, VariableStateMap } from '@perses-dev/components';
 import { DatasourceStore } from '@perses-dev/plugin-system';
 import { ClientRequestOptions, PrometheusClient } from '../model';
+import { getDatasourceQueryParams, getFlavorHeaders } from './flavor';
 import { PrometheusDatasourceSpec } from './types';
 
 export interface ResolvedPrometheusDatasource {
@@ -40,8 +41,9 @@ export function interpolateDatasourceProxyParams(
   variableState: VariableStateMap
 ): ClientRequestOptions {
   const spec = datasource.plugin.spec;
-  const rawHeaders = spec.proxy?.spec?.headers;
-  const rawQueryParams = spec.queryParams;
+  const flavorHeaders = getFlavorHeaders(spec);
+  const rawHeaders = flavorHeaders ? { ...flavorHeaders, ...spec.proxy?.spec?.headers } : spec.proxy?.spec?.headers;
+  const rawQueryParams = getDatasourceQueryParams(spec);
 
   return {
     headers: rawHeaders ? interpolateHeaders(rawHeaders, variableState) : undefined,
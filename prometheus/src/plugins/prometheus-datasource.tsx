@@ -28,6 +28,7 @@ import {
   rangeQuery,
   series,
 } from '../model';
+import { getDatasourceQueryParams, getFlavorHeaders } from './flavor';
 import { PrometheusDatasourceEditor } from './PrometheusDatasourceEditor';
 import { PrometheusDatasourceSpec } from './types';
 
@@ -50,7 +51,8 @@ function wrapClientMethod<P, R>(
  * Creates a PrometheusClient for a specific datasource spec.
  */
 const createClient: DatasourcePlugin<PrometheusDatasourceSpec, PrometheusClient>['createClient'] = (spec, options) => {
-  const { directUrl, proxy, queryParams } = spec;
+  const { directUrl, proxy } = spec;
+  const queryParams = getDatasourceQueryParams(spec);
   const { proxyUrl } = options;
 
   // Use the direct URL if specified, but fallback to the proxyUrl by default if not specified
@@ -59,7 +61,8 @@ const createClient: DatasourcePlugin<PrometheusDatasourceSpec, PrometheusClient>
     throw new Error('No URL specified for Prometheus client. You can use directUrl in the spec to configure it.');
   }
 
-  const specHeaders = proxy?.spec.headers;
+  const flavorHeaders = getFlavorHeaders(spec);
+  const specHeaders = flavorHeaders ? { ...flavorHeaders, ...proxy?.spec.headers } : proxy?.spec.headers;
 
   // Could think about this becoming a class, although it definitely doesn't have to be
   return {
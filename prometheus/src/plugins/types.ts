@@ -18,10 +18,31 @@ import { PrometheusDatasourceSelector } from '../model';
 
 export const DEFAULT_SCRAPE_INTERVAL: DurationString = '1m';
 
+export type PrometheusFlavor = 'prometheus' | 'thanos' | 'mimir' | 'victoriametrics';
+
+export interface ThanosOptions {
+  dedup?: boolean;
+  partialResponse?: boolean;
+  maxSourceResolution?: 'auto' | DurationString;
+}
+
+export interface MimirOptions {
+  disableResultsCache?: boolean;
+}
+
+export interface VictoriaMetricsOptions {
+  extraLabels?: Record<string, string>;
+  noCache?: boolean;
+}
+
 export interface PrometheusDatasourceSpec {
   directUrl?: string;
   proxy?: HTTPProxy;
   scrapeInterval?: DurationString; // default to 1m
+  flavor?: PrometheusFlavor;
+  thanos?: ThanosOptions;
+  mimir?: MimirOptions;
+  victoriaMetrics?: VictoriaMetricsOptions;
   queryParams?: QueryParamValues;
 }
 
@@ -19,6 +19,7 @@ import (
 
 	"github.com/perses/perses/go-sdk/datasource"
 	"github.com/perses/perses/pkg/model/api/v1/datasource/http"
+	"github.com/perses/plugins/sdk/go/option"
 )
 
 const PluginKind = "PyroscopeDatasource"
@@ -73,10 +74,8 @@ func create(options ...Option) (Builder, error) {
 
 	var defaults []Option
 
-	for _, opt := range append(defaults, options...) {
-		if err := opt(builder); err != nil {
-			return *builder, err
-		}
+	if err := option.Apply(PluginKind, builder, append(defaults, options...)); err != nil {
+		return *builder, err
 	}
 
 	return *builder, nil
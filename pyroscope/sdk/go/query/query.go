@@ -14,14 +14,36 @@
 package query
 
 import (
+	"fmt"
+
 	"github.com/perses/perses/go-sdk/datasource"
 	"github.com/perses/perses/go-sdk/query"
 	"github.com/perses/perses/pkg/model/api/v1/common"
 	"github.com/perses/perses/pkg/model/api/v1/plugin"
+	pyroscopeDatasource "github.com/perses/plugins/pyroscope/sdk/go/datasource"
+	"github.com/perses/plugins/sdk/go/analysis"
+	"github.com/perses/plugins/sdk/go/option"
+	"github.com/perses/plugins/sdk/go/selector"
 )
 
 const PluginKind = "PyroscopeProfileQuery"
 
+func init() {
+	selector.Register(PluginKind, pyroscopeDatasource.PluginKind)
+	analysis.Register(PluginKind, analysis.Decode(func(spec PluginSpec) []analysis.Field {
+		fields := []analysis.Field{analysis.Datasource(spec.Datasource)}
+		if spec.Service != nil {
+			fields = append(fields, analysis.Field{Path: "service", Value: *spec.Service})
+		}
+		for i, filter := range spec.Filters {
+			if filter.LabelValue != nil {
+				fields = append(fields, analysis.Field{Path: fmt.Sprintf("filters[%d].labelValue", i), Value: *filter.LabelValue})
+			}
+		}
+		return fields
+	}))
+}
+
 type LabelFilter struct {
 	LabelName  *string `json:"labelName,omitempty" yaml:"labelName,omitempty"`
 	LabelValue *string `json:"labelValue,omitempty" yaml:"labelValue,omitempty"`
@@ -43,10 +65,10 @@ func create(options ...Option) (Builder, error) {
 		PluginSpec: PluginSpec{},
 	}
 
-	for _, opt := range options {
-		if err := opt(builder); err != nil {
-			return *builder, err
-		}
+	if err := option.ApplyAndValidate(PluginKind, builder, options, func() error {
+		return selector.Check(PluginKind, builder.Datasource)
+	}); err != nil {
+		return *builder, err
 	}
 
 	return *builder, nil
@@ -0,0 +1,200 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package affected computes the plugins affected by the changes made since a git ref,
+// so the CI only builds, lints and tests what can have been broken.
+package affected
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/perses/plugins/scripts/gomodule"
+	"github.com/perses/plugins/scripts/graph"
+)
+
+// globalFiles are the files at the root of the repository used by every plugin, to build them or by the tooling
+// building them. Changing one of them affects all plugins.
+var globalFiles = []string{
+	"go.mod",
+	"go.sum",
+	"Makefile",
+	"package.json",
+	"package-lock.json",
+	"turbo.json",
+	"tsconfig.json",
+	"tsconfig.base.json",
+	"rsbuild.shared.ts",
+	"jest.shared.ts",
+	"stylesMock.js",
+	".swcrc",
+	".cjs.swcrc",
+}
+
+// globalDirs are the folders of the tooling running on every plugin (the scripts and the CI).
+// Changing a file in one of them affects all plugins.
+var globalDirs = []string{
+	"scripts/",
+	".github/",
+}
+
+// Dependencies returns, for each workspace, the workspaces and Go modules (see gomodule.Modules) it depends on with the
+// given kind of dependency, read from cue.mod/module.cue for CUE and from go.mod for Go.
+func Dependencies(dir string, workspaces []string, kind graph.Kind) (map[string][]string, error) {
+	modules := append(slices.Clone(workspaces), gomodule.Modules...)
+	g, err := graph.Build(dir, modules)
+	if err != nil {
+		return nil, err
+	}
+	deps := make(map[string][]string, len(workspaces))
+	for _, workspace := range workspaces {
+		for _, dep := range g.DependenciesOf(workspace, kind) {
+			if slices.Contains(modules, dep) {
+				deps[workspace] = append(deps[workspace], dep)
+			}
+		}
+	}
+	return deps, nil
+}
+
+// ChangedFiles returns the files changed between the merge base of the ref and HEAD, plus the uncommitted changes and
+// the untracked files (except the ignored ones). A renamed file gives both its old and its new path.
+func ChangedFiles(dir string, ref string) ([]string, error) {
+	mergeBase, err := git(dir, "merge-base", ref, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	diff, err := git(dir, "diff", "--name-only", "--no-renames", strings.TrimSpace(mergeBase))
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := git(dir, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, line := range strings.Split(diff+"\n"+untracked, "\n") {
+		if line = strings.TrimSpace(line); len(line) > 0 && !slices.Contains(files, line) {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return string(output), nil
+}
+
+// Compute returns the workspaces affected by the changed files, keeping the order of workspaces.
+// A workspace is affected when one of its files changed, when the CUE schemas of a workspace it depends on changed,
+// when the Go code of a workspace or Go module (see gomodule.Modules) it depends on changed (directly or through
+// another dependency in both cases), or when a file shared by all the plugins or a file of the tooling changed.
+func Compute(changedFiles []string, workspaces []string, cueDeps map[string][]string, goDeps map[string][]string) []string {
+	affected := make(map[string]bool)
+	// schemaAffected are the workspaces whose CUE module, as seen by the modules importing it, changed.
+	schemaAffected := make(map[string]bool)
+	// goAffected are the workspaces and Go modules whose Go module, as seen by the modules requiring it, changed.
+	goAffected := make(map[string]bool)
+	for _, file := range changedFiles {
+		file = filepath.ToSlash(file)
+		if slices.Contains(globalFiles, file) || slices.ContainsFunc(globalDirs, func(dir string) bool {
+			return strings.HasPrefix(file, dir)
+		}) {
+			return slices.Clone(workspaces)
+		}
+		module, rest, found := strings.Cut(file, "/")
+		if !found {
+			continue
+		}
+		if gomodule.IsModule(module) {
+			goAffected[module] = true
+			continue
+		}
+		if !slices.Contains(workspaces, module) {
+			continue
+		}
+		affected[module] = true
+		if strings.HasPrefix(rest, "schemas/") || strings.HasPrefix(rest, "cue.mod/") {
+			schemaAffected[module] = true
+		}
+		if isGoFile(rest) {
+			goAffected[module] = true
+		}
+	}
+	propagate(affected, schemaAffected, cueDeps)
+	propagate(affected, goAffected, goDeps)
+	var result []string
+	for _, workspace := range workspaces {
+		if affected[workspace] {
+			result = append(result, workspace)
+		}
+	}
+	return result
+}
+
+// propagate marks as affected the workspaces depending on a changed module until nothing changes.
+func propagate(affected map[string]bool, changedModules map[string]bool, deps map[string][]string) {
+	for changed := true; changed; {
+		changed = false
+		for workspace, workspaceDeps := range deps {
+			if changedModules[workspace] {
+				continue
+			}
+			for _, dep := range workspaceDeps {
+				if changedModules[dep] {
+					affected[workspace] = true
+					changedModules[workspace] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+}
+
+// isGoFile returns true if the file, relative to its module, is part of the Go module as seen by the modules requiring it.
+func isGoFile(file string) bool {
+	return file == "go.mod" || file == "go.sum" || strings.HasSuffix(file, ".go")
+}
+
+// Filter returns the workspaces affected by the changes made since ref. When ref is empty, all workspaces are returned.
+func Filter(dir string, ref string, workspaces []string) ([]string, error) {
+	if len(ref) == 0 {
+		return workspaces, nil
+	}
+	files, err := ChangedFiles(dir, ref)
+	if err != nil {
+		return nil, err
+	}
+	cueDeps, err := Dependencies(dir, workspaces, graph.CUE)
+	if err != nil {
+		return nil, err
+	}
+	goDeps, err := Dependencies(dir, workspaces, graph.Go)
+	if err != nil {
+		return nil, err
+	}
+	return Compute(files, workspaces, cueDeps, goDeps), nil
+}
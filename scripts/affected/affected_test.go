@@ -0,0 +1,163 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package affected
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/perses/plugins/scripts/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testWorkspaces = []string{"datasourcevariable", "loki", "markdown", "prometheus", "table", "tempo"}
+
+var testCUEDeps = map[string][]string{
+	"datasourcevariable": {"loki", "prometheus", "tempo"},
+	"markdown":           {"datasourcevariable"},
+}
+
+var testGoDeps = map[string][]string{
+	"loki":       {"sdk"},
+	"prometheus": {"sdk"},
+	"table":      {"prometheus"},
+}
+
+func TestCompute(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		files    []string
+		expected []string
+	}{
+		{
+			title:    "no change",
+			files:    nil,
+			expected: nil,
+		},
+		{
+			title:    "change outside of the plugins",
+			files:    []string{"docs/prometheus/model.md", "README.md"},
+			expected: nil,
+		},
+		{
+			title:    "frontend change",
+			files:    []string{"prometheus/src/plugins/prometheus-datasource.tsx"},
+			expected: []string{"prometheus"},
+		},
+		{
+			title:    "schema change affects the importing modules transitively",
+			files:    []string{"prometheus/schemas/datasource/prometheus.cue"},
+			expected: []string{"datasourcevariable", "markdown", "prometheus"},
+		},
+		{
+			title:    "schema change of a module nobody imports",
+			files:    []string{"table/schemas/table.cue"},
+			expected: []string{"table"},
+		},
+		{
+			title:    "change of a Go module affects the workspaces requiring it transitively",
+			files:    []string{"sdk/go/option/option.go"},
+			expected: []string{"loki", "prometheus", "table"},
+		},
+		{
+			title:    "go.mod change affects the workspaces requiring it",
+			files:    []string{"prometheus/go.mod"},
+			expected: []string{"prometheus", "table"},
+		},
+		{
+			title:    "Go SDK change affects the workspaces requiring it",
+			files:    []string{"prometheus/sdk/go/query/query.go"},
+			expected: []string{"prometheus", "table"},
+		},
+		{
+			title:    "scripts change affects all",
+			files:    []string{"scripts/npm/npm.go"},
+			expected: testWorkspaces,
+		},
+		{
+			title:    "CI change affects all",
+			files:    []string{".github/workflows/ci.yml"},
+			expected: testWorkspaces,
+		},
+		{
+			title:    "root go.mod affects all",
+			files:    []string{"go.mod"},
+			expected: testWorkspaces,
+		},
+		{
+			title:    "Makefile affects all",
+			files:    []string{"Makefile"},
+			expected: testWorkspaces,
+		},
+		{
+			title:    "root package.json affects all",
+			files:    []string{"table/src/Table.tsx", "package.json"},
+			expected: testWorkspaces,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			assert.Equal(t, test.expected, Compute(test.files, testWorkspaces, testCUEDeps, testGoDeps))
+		})
+	}
+}
+
+func TestDependencies(t *testing.T) {
+	workspaces := []string{"datasourcevariable", "loki", "prometheus", "pyroscope", "tempo"}
+	deps, err := Dependencies("../..", workspaces, graph.CUE)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"loki", "prometheus", "pyroscope", "tempo"}, deps["datasourcevariable"])
+	assert.Empty(t, deps["prometheus"])
+	goDeps, err := Dependencies("../..", workspaces, graph.Go)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sdk"}, goDeps["prometheus"])
+}
+
+func TestChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@perses.dev", "-c", "commit.gpgsign=false"}, args...)...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	write := func(file string) {
+		t.Helper()
+		p := filepath.Join(dir, file)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte("content of a file long enough to be detected as renamed\n"), 0o600))
+	}
+	git("init", "--quiet")
+	write("prometheus/src/utils.ts")
+	write(".gitignore")
+	git("add", "-A")
+	git("commit", "--quiet", "-m", "init")
+	git("tag", "base")
+
+	// move a file from a plugin to another one
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "loki", "src"), 0o755))
+	git("mv", "prometheus/src/utils.ts", "loki/src/utils.ts")
+	git("commit", "--quiet", "-m", "move")
+	write("tempo/src/new.ts")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("*.log\n"), 0o600))
+	write("table/debug.log")
+
+	files, err := ChangedFiles(dir, "base")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{".gitignore", "loki/src/utils.ts", "prometheus/src/utils.ts", "tempo/src/new.ts"}, files)
+}
@@ -0,0 +1,251 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package archive builds the plugin archives in a reproducible way: building twice the same plugin gives
+// byte-identical archives, whatever the machine, the user or the time of the build.
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// File is a file or a folder of the plugin to put in the archive.
+type File struct {
+	// Source is the path relative to the plugin folder.
+	Source string
+	// Name is the path in the archive.
+	Name string
+	Dir  bool
+}
+
+// PluginFiles are the files and folders of a plugin that go in its archive.
+var PluginFiles = []File{
+	{Source: "dist", Name: "dist", Dir: true},
+	{Source: "cue.mod", Name: "cue.mod", Dir: true},
+	{Source: "schemas", Name: "schemas", Dir: true},
+	{Source: "package.json", Name: "package.json"},
+	{Source: "README.md", Name: "README.md"},
+	{Source: filepath.Join("..", "LICENSE"), Name: "LICENSE"},
+}
+
+// ChecksumsFile is the name of the file listing the SHA-256 of the archives.
+const ChecksumsFile = "SHA256SUMS"
+
+var junkFiles = []string{".DS_Store", "Thumbs.db", "desktop.ini", ".git", "__MACOSX", "node_modules"}
+
+// IsJunk tells whether the file or folder must be excluded from an archive.
+func IsJunk(name string) bool {
+	base := path.Base(filepath.ToSlash(name))
+	return slices.Contains(junkFiles, base) ||
+		strings.HasPrefix(base, "._") ||
+		strings.HasSuffix(base, ".swp") ||
+		strings.HasSuffix(base, "~")
+}
+
+// ModTime returns the modification time set on every entry of the archives.
+// It is taken from SOURCE_DATE_EPOCH when defined (see https://reproducible-builds.org/specs/source-date-epoch/),
+// otherwise it is the Unix epoch.
+func ModTime() (time.Time, error) {
+	epoch := os.Getenv("SOURCE_DATE_EPOCH")
+	if len(epoch) == 0 {
+		return time.Unix(0, 0).UTC(), nil
+	}
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid SOURCE_DATE_EPOCH %q: %w", epoch, err)
+	}
+	return time.Unix(seconds, 0).UTC(), nil
+}
+
+type entry struct {
+	name   string
+	source string
+	info   fs.FileInfo
+}
+
+// Write creates the gzipped tar archive at archivePath with the files of the plugin located in pluginPath.
+// The entries are sorted by name, owned by root and all have the same modification time. Junk files are skipped.
+func Write(archivePath string, pluginPath string, files []File, modTime time.Time) error {
+	var entries []entry
+	for _, f := range files {
+		fileEntries, err := collect(filepath.Join(pluginPath, f.Source), f.Name, f.Dir)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, fileEntries...)
+	}
+	slices.SortFunc(entries, func(a, b entry) int {
+		return strings.Compare(a.name, b.name)
+	})
+
+	out, err := os.Create(archivePath)
+	if err != nil {
+		return fmt.Errorf("unable to create the archive %s: %w", archivePath, err)
+	}
+	if writeErr := write(out, entries, modTime); writeErr != nil {
+		_ = out.Close()
+		_ = os.Remove(archivePath)
+		return fmt.Errorf("unable to write the archive %s: %w", archivePath, writeErr)
+	}
+	return out.Close()
+}
+
+func collect(source string, name string, isDir bool) ([]entry, error) {
+	info, err := os.Lstat(source)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find the file or folder %s: %w", source, err)
+	}
+	if info.IsDir() != isDir {
+		if isDir {
+			return nil, fmt.Errorf("%s is expected to be a folder", source)
+		}
+		return nil, fmt.Errorf("%s is expected to be a file", source)
+	}
+	if !isDir {
+		return []entry{{name: name, source: source, info: info}}, nil
+	}
+	var entries []entry
+	walkErr := filepath.WalkDir(source, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if IsJunk(d.Name()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(source, p)
+		if err != nil {
+			return err
+		}
+		fileInfo, err := d.Info()
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry{name: path.Join(name, filepath.ToSlash(rel)), source: p, info: fileInfo})
+		return nil
+	})
+	return entries, walkErr
+}
+
+func write(out io.Writer, entries []entry, modTime time.Time) error {
+	// No name and no modification time in the gzip header, so it doesn't depend on the build.
+	gz, err := gzip.NewWriterLevel(out, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		if err := writeEntry(tw, e, modTime); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func writeEntry(tw *tar.Writer, e entry, modTime time.Time) error {
+	header := &tar.Header{
+		Name:    e.name,
+		ModTime: modTime,
+	}
+	mode := e.info.Mode()
+	switch {
+	case mode.IsDir():
+		header.Typeflag = tar.TypeDir
+		header.Name += "/"
+		header.Mode = 0o755
+	case mode&fs.ModeSymlink != 0:
+		target, err := os.Readlink(e.source)
+		if err != nil {
+			return err
+		}
+		header.Typeflag = tar.TypeSymlink
+		header.Linkname = filepath.ToSlash(target)
+		header.Mode = 0o777
+	case mode.IsRegular():
+		header.Typeflag = tar.TypeReg
+		header.Size = e.info.Size()
+		header.Mode = 0o644
+		if mode&0o111 != 0 {
+			header.Mode = 0o755
+		}
+	default:
+		return fmt.Errorf("%s is not a regular file, a folder or a symbolic link", e.source)
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	if header.Typeflag != tar.TypeReg {
+		return nil
+	}
+	f, err := os.Open(e.source)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := io.Copy(tw, f); err != nil {
+		return fmt.Errorf("unable to copy %s: %w", e.source, err)
+	}
+	return nil
+}
+
+// Checksum returns the hex-encoded SHA-256 of the file.
+func Checksum(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// WriteChecksums writes in dir a SHA256SUMS file with the checksum of the given archives, in the format of the
+// sha256sum command, so it can be checked with `sha256sum -c SHA256SUMS`.
+// The archives must be in dir. Only these are listed, so an archive left in dir by a previous run is not.
+func WriteChecksums(dir string, archives []string) error {
+	archives = slices.Clone(archives)
+	slices.Sort(archives)
+	var sb strings.Builder
+	for _, a := range archives {
+		if filepath.Dir(a) != filepath.Clean(dir) {
+			return fmt.Errorf("the archive %s is not in the folder %s", a, dir)
+		}
+		sum, err := Checksum(a)
+		if err != nil {
+			return fmt.Errorf("unable to compute the checksum of %s: %w", a, err)
+		}
+		fmt.Fprintf(&sb, "%s  %s\n", sum, filepath.Base(a))
+	}
+	return os.WriteFile(filepath.Join(dir, ChecksumsFile), []byte(sb.String()), 0o644)
+}
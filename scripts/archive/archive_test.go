@@ -0,0 +1,128 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePlugin(t *testing.T, root string, mtime time.Time) string {
+	t.Helper()
+	pluginPath := filepath.Join(root, "plugin")
+	files := map[string]string{
+		"LICENSE":                      "license",
+		"plugin/package.json":          "{}",
+		"plugin/README.md":             "# plugin",
+		"plugin/dist/mf-manifest.json": "{}",
+		"plugin/dist/b.js":             "b",
+		"plugin/dist/a.js":             "a",
+		"plugin/dist/.DS_Store":        "junk",
+		"plugin/dist/._a.js":           "junk",
+		"plugin/schemas/plugin.cue":    "package model",
+		"plugin/cue.mod/module.cue":    "module: \"test\"",
+	}
+	for name, content := range files {
+		p := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+		require.NoError(t, os.Chtimes(p, mtime, mtime))
+	}
+	return pluginPath
+}
+
+func TestWriteIsReproducible(t *testing.T) {
+	modTime := time.Unix(1700000000, 0)
+	first := writePlugin(t, t.TempDir(), time.Now())
+	second := writePlugin(t, t.TempDir(), time.Now().Add(-48*time.Hour))
+
+	firstArchive := filepath.Join(t.TempDir(), "first.tar.gz")
+	secondArchive := filepath.Join(t.TempDir(), "second.tar.gz")
+	require.NoError(t, Write(firstArchive, first, PluginFiles, modTime))
+	require.NoError(t, Write(secondArchive, second, PluginFiles, modTime))
+
+	firstData, err := os.ReadFile(firstArchive)
+	require.NoError(t, err)
+	secondData, err := os.ReadFile(secondArchive)
+	require.NoError(t, err)
+	assert.Equal(t, firstData, secondData)
+
+	f, err := os.Open(firstArchive)
+	require.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, header.Name)
+		assert.Equal(t, 0, header.Uid)
+		assert.True(t, header.ModTime.Equal(modTime))
+	}
+	assert.Equal(t, []string{
+		"LICENSE",
+		"README.md",
+		"cue.mod/",
+		"cue.mod/module.cue",
+		"dist/",
+		"dist/a.js",
+		"dist/b.js",
+		"dist/mf-manifest.json",
+		"package.json",
+		"schemas/",
+		"schemas/plugin.cue",
+	}, names)
+}
+
+func TestWriteChecksums(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b-0.1.0.tar.gz"), []byte("b"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a-0.1.0.tar.gz"), []byte("a"), 0o600))
+	// an archive left by a previous run must not be listed
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a-0.0.9.tar.gz"), []byte("old"), 0o600))
+	require.NoError(t, WriteChecksums(dir, []string{filepath.Join(dir, "b-0.1.0.tar.gz"), filepath.Join(dir, "a-0.1.0.tar.gz")}))
+	data, err := os.ReadFile(filepath.Join(dir, ChecksumsFile))
+	require.NoError(t, err)
+	assert.Equal(t, "ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb  a-0.1.0.tar.gz\n"+
+		"3e23e8160039594a33894f6564e1b1348bbd7a0088d42c4acb73eeaed59c009d  b-0.1.0.tar.gz\n", string(data))
+}
+
+func TestWriteChecksums_ArchiveOutsideFolder(t *testing.T) {
+	dir := t.TempDir()
+	other := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(other, "a-0.1.0.tar.gz"), []byte("a"), 0o600))
+	assert.ErrorContains(t, WriteChecksums(dir, []string{filepath.Join(other, "a-0.1.0.tar.gz")}), "is not in the folder")
+}
+
+func TestIsJunk(t *testing.T) {
+	for _, name := range []string{".DS_Store", "dist/._index.js", "schemas/model.cue~", ".model.cue.swp", "a/.git"} {
+		assert.True(t, IsJunk(name), name)
+	}
+	for _, name := range []string{"index.js", "dist/.well-known", "README.md"} {
+		assert.False(t, IsJunk(name), name)
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package archive
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/perses/perses/pkg/model/api/v1/plugin"
+	"github.com/perses/plugins/scripts/manifest"
+)
+
+const manifestPath = "dist/mf-manifest.json"
+
+var kindRegexp = regexp.MustCompile(`(?m)^kind:\s*(?:"(\w+)"|(#\w+))`)
+
+func definitionRegexp(definition string) *regexp.Regexp {
+	return regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(definition) + `:\s*"(\w+)"`)
+}
+
+type packageJSON struct {
+	Name    string            `json:"name"`
+	Version string            `json:"version"`
+	Perses  plugin.ModuleSpec `json:"perses"`
+}
+
+// read loads in memory the regular files of the archive, indexed by their name (without a leading "./").
+// The directories are indexed with a trailing "/".
+func read(archivePath string) (map[string][]byte, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("not a gzip file: %w", err)
+	}
+	tr := tar.NewReader(gz)
+	files := make(map[string][]byte)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("not a valid tar file: %w", err)
+		}
+		name := strings.TrimPrefix(path.Clean(header.Name), "./")
+		switch header.Typeflag {
+		case tar.TypeDir:
+			files[name+"/"] = nil
+		case tar.TypeReg:
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read %s: %w", header.Name, err)
+			}
+			files[name] = data
+			// Some tar implementations don't write the entries of the parent folders.
+			for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+				files[dir+"/"] = nil
+			}
+		}
+	}
+}
+
+// Verify checks that the archive contains all the files of a plugin, that its manifest matches the name of the archive
+// and the package.json (name and version), and that every CUE schema declares a kind listed in the package.json.
+// It returns all the issues found joined together.
+func Verify(archivePath string) error {
+	files, err := read(archivePath)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, f := range PluginFiles {
+		name := f.Name
+		if f.Dir {
+			name += "/"
+		}
+		if _, ok := files[name]; !ok {
+			errs = append(errs, fmt.Errorf("missing %s", name))
+		}
+	}
+
+	var pkg packageJSON
+	pkgData, hasPackage := files["package.json"]
+	if hasPackage {
+		if jsonErr := json.Unmarshal(pkgData, &pkg); jsonErr != nil {
+			errs = append(errs, fmt.Errorf("unable to parse package.json: %w", jsonErr))
+			hasPackage = false
+		}
+	}
+
+	if manifestData, ok := files[manifestPath]; !ok {
+		errs = append(errs, fmt.Errorf("missing %s", manifestPath))
+	} else {
+		if manif, parseErr := manifest.Parse(manifestData); parseErr != nil {
+			errs = append(errs, fmt.Errorf("unable to parse %s: %w", manifestPath, parseErr))
+		} else {
+			expectedName := fmt.Sprintf("%s-%s.tar.gz", manif.ID, manif.Metadata.BuildInfo.Version)
+			if filepath.Base(archivePath) != expectedName {
+				errs = append(errs, fmt.Errorf("the manifest (id %q, buildVersion %q) expects the archive to be named %s", manif.ID, manif.Metadata.BuildInfo.Version, expectedName))
+			}
+			if hasPackage && !matchPackageName(manif.ID, pkg.Name) {
+				errs = append(errs, fmt.Errorf("the manifest id %q doesn't match the name %q of package.json", manif.ID, pkg.Name))
+			}
+			if hasPackage && pkg.Version != manif.Metadata.BuildInfo.Version {
+				errs = append(errs, fmt.Errorf("the buildVersion %q of the manifest doesn't match the version %q of package.json", manif.Metadata.BuildInfo.Version, pkg.Version))
+			}
+		}
+	}
+
+	if hasPackage {
+		errs = append(errs, verifySchemaKinds(files, pkg.Perses)...)
+	}
+	return errors.Join(errs...)
+}
+
+// matchPackageName tells whether the manifest id is the name of the npm package without its scope, its "-plugin" suffix
+// and its dashes, ignoring the case: e.g. "HeatMapChart" for "@perses-dev/heatmap-chart-plugin".
+func matchPackageName(id string, packageName string) bool {
+	if i := strings.LastIndex(packageName, "/"); i >= 0 {
+		packageName = packageName[i+1:]
+	}
+	packageName = strings.ReplaceAll(strings.TrimSuffix(packageName, "-plugin"), "-", "")
+	return strings.EqualFold(id, packageName)
+}
+
+func verifySchemaKinds(files map[string][]byte, module plugin.ModuleSpec) []error {
+	schemasPath := module.SchemasPath
+	if len(schemasPath) == 0 {
+		schemasPath = "schemas"
+	}
+	var kinds []string
+	for _, p := range module.Plugins {
+		kinds = append(kinds, p.Spec.Name)
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var errs []error
+	for _, name := range names {
+		if !strings.HasPrefix(name, schemasPath+"/") || path.Ext(name) != ".cue" {
+			continue
+		}
+		kind, found := SchemaKind(files[name])
+		if !found {
+			// helper file, not a schema
+			continue
+		}
+		if len(kind) == 0 {
+			errs = append(errs, fmt.Errorf("%s: unable to resolve the kind", name))
+		} else if !slices.Contains(kinds, kind) {
+			errs = append(errs, fmt.Errorf("%s: the kind %q is not listed in package.json", name, kind))
+		}
+	}
+	return errs
+}
+
+// SchemaKind returns the kind declared at the top level of a CUE schema (kind: "X", or kind: #kind with #kind: "X").
+// found is false when the file doesn't declare any kind.
+func SchemaKind(data []byte) (kind string, found bool) {
+	match := kindRegexp.FindSubmatch(data)
+	if match == nil {
+		return "", false
+	}
+	if len(match[1]) > 0 {
+		return string(match[1]), true
+	}
+	definition := definitionRegexp(string(match[2])).FindSubmatch(data)
+	if definition == nil {
+		return "", true
+	}
+	return string(definition[1]), true
+}
+
+// ReadChecksums parses a SHA256SUMS file and returns the checksums indexed by file name.
+func ReadChecksums(checksumsPath string) (map[string]string, error) {
+	f, err := os.Open(checksumsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(strings.TrimSpace(line)) == 0 {
+			continue
+		}
+		sum, name, ok := strings.Cut(line, "  ")
+		if !ok {
+			return nil, fmt.Errorf("invalid line %q in %s", line, checksumsPath)
+		}
+		sums[name] = sum
+	}
+	return sums, scanner.Err()
+}
@@ -0,0 +1,141 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPackageJSON = `{
+  "name": "@perses-dev/foo-plugin",
+  "version": "0.2.0",
+  "perses": {
+    "schemasPath": "schemas",
+    "plugins": [
+      {"kind": "Datasource", "spec": {"display": {"name": "Foo Datasource"}, "name": "FooDatasource"}},
+      {"kind": "TimeSeriesQuery", "spec": {"display": {"name": "Foo Query"}, "name": "FooTimeSeriesQuery"}}
+    ]
+  }
+}`
+
+func writeValidPlugin(t *testing.T) string {
+	t.Helper()
+	pluginPath := writePlugin(t, t.TempDir(), time.Now())
+	files := map[string]string{
+		"package.json":                      testPackageJSON,
+		"dist/mf-manifest.json":             `{"id": "Foo", "name": "Foo", "metaData": {"buildInfo": {"buildVersion": "0.2.0"}}}`,
+		"schemas/plugin.cue":                "package model\n\n#kind: \"FooDatasource\"\nkind: #kind\n",
+		"schemas/query/query.cue":           "package model\n\nkind: \"FooTimeSeriesQuery\"\n",
+		"schemas/query/migrate/migrate.cue": "package migrate\n\nkind: \"FooTimeSeriesQuery\"\n",
+		"schemas/common/common.cue":         "package common\n\n#helper: string\n",
+	}
+	for name, content := range files {
+		p := filepath.Join(pluginPath, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+	}
+	return pluginPath
+}
+
+func TestVerify(t *testing.T) {
+	testSuites := []struct {
+		title       string
+		archiveName string
+		change      func(pluginPath string)
+		files       []File
+		expected    []string
+	}{
+		{
+			title:       "valid archive",
+			archiveName: "Foo-0.2.0.tar.gz",
+		},
+		{
+			title:       "wrong archive name",
+			archiveName: "Foo-0.1.0.tar.gz",
+			expected:    []string{`the manifest (id "Foo", buildVersion "0.2.0") expects the archive to be named Foo-0.2.0.tar.gz`},
+		},
+		{
+			title:       "version mismatch with package.json",
+			archiveName: "Foo-0.2.0.tar.gz",
+			change: func(pluginPath string) {
+				_ = os.WriteFile(filepath.Join(pluginPath, "dist", "mf-manifest.json"), []byte(`{"id": "Foo", "name": "Foo", "metaData": {"buildInfo": {"buildVersion": "0.1.0"}}}`), 0o600)
+			},
+			expected: []string{
+				`the manifest (id "Foo", buildVersion "0.1.0") expects the archive to be named Foo-0.1.0.tar.gz`,
+				`the buildVersion "0.1.0" of the manifest doesn't match the version "0.2.0" of package.json`,
+			},
+		},
+		{
+			title:       "manifest id mismatch with package.json",
+			archiveName: "Bar-0.2.0.tar.gz",
+			change: func(pluginPath string) {
+				_ = os.WriteFile(filepath.Join(pluginPath, "dist", "mf-manifest.json"), []byte(`{"id": "Bar", "name": "Bar", "metaData": {"buildInfo": {"buildVersion": "0.2.0"}}}`), 0o600)
+			},
+			expected: []string{`the manifest id "Bar" doesn't match the name "@perses-dev/foo-plugin" of package.json`},
+		},
+		{
+			title:       "unknown kind",
+			archiveName: "Foo-0.2.0.tar.gz",
+			change: func(pluginPath string) {
+				_ = os.WriteFile(filepath.Join(pluginPath, "schemas", "query", "query.cue"), []byte("package model\n\nkind: \"BarQuery\"\n"), 0o600)
+			},
+			expected: []string{`schemas/query/query.cue: the kind "BarQuery" is not listed in package.json`},
+		},
+		{
+			title:       "missing files",
+			archiveName: "Foo-0.2.0.tar.gz",
+			files:       PluginFiles[:4],
+			expected:    []string{"missing README.md", "missing LICENSE"},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			pluginPath := writeValidPlugin(t)
+			if test.change != nil {
+				test.change(pluginPath)
+			}
+			files := test.files
+			if files == nil {
+				files = PluginFiles
+			}
+			archivePath := filepath.Join(t.TempDir(), test.archiveName)
+			require.NoError(t, Write(archivePath, pluginPath, files, time.Unix(0, 0)))
+			err := Verify(archivePath)
+			if len(test.expected) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			var messages []string
+			for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+				messages = append(messages, e.Error())
+			}
+			assert.Equal(t, test.expected, messages)
+		})
+	}
+}
+
+func TestMatchPackageName(t *testing.T) {
+	assert.True(t, matchPackageName("HeatMapChart", "@perses-dev/heatmap-chart-plugin"))
+	assert.True(t, matchPackageName("VictoriaLogs", "@perses-dev/victorialogs-plugin"))
+	assert.True(t, matchPackageName("Foo", "foo"))
+	assert.False(t, matchPackageName("Prometheus", "@perses-dev/prometheus-pluginx"))
+	assert.False(t, matchPackageName("Tempo", "@perses-dev/jaeger-plugin"))
+}
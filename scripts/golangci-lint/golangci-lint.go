@@ -17,7 +17,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 
+	"github.com/perses/plugins/scripts/gomodule"
 	"github.com/perses/plugins/scripts/npm"
 	"github.com/sirupsen/logrus"
 )
@@ -25,9 +27,14 @@ import (
 func main() {
 	var isError bool
 
-	for _, workspace := range npm.MustGetWorkspaces(".") {
+	workspaces, err := npm.GetWorkspaces(".")
+	if err != nil {
+		logrus.WithError(err).Fatal("unable to get the workspaces")
+	}
+	// the Go modules that are not plugins (e.g. sdk) have no schemas but are always linted
+	for _, workspace := range append(slices.Clone(gomodule.Modules), workspaces...) {
 		schemasPath := filepath.Join(workspace, "schemas")
-		if _, err := os.Stat(schemasPath); os.IsNotExist(err) {
+		if _, err := os.Stat(schemasPath); os.IsNotExist(err) && !gomodule.IsModule(workspace) {
 			// No schemas, skip go validation
 			logrus.Infof("skipping golangci-lint for %s (no schemas)", workspace)
 			continue
@@ -0,0 +1,45 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gomodule lists the Go modules of this repository that are not plugins, like the helpers shared by the Go SDK
+// of every plugin. They are not npm workspaces, so their version is read from their VERSION file instead of package.json.
+package gomodule
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/perses/plugins/scripts/tag"
+)
+
+const versionFile = "VERSION"
+
+// Modules are the folders of the Go modules that are not plugins.
+var Modules = []string{"sdk"}
+
+// IsModule tells whether the folder is a Go module that is not a plugin.
+func IsModule(name string) bool {
+	return slices.Contains(Modules, name)
+}
+
+// GetVersion returns the version of the Go module, read from its VERSION file.
+func GetVersion(modulePath string) (tag.Version, error) {
+	data, err := os.ReadFile(filepath.Join(modulePath, versionFile))
+	if err != nil {
+		return tag.Version{}, fmt.Errorf("unable to read the version of the module %s: %w", modulePath, err)
+	}
+	return tag.ParseVersion(strings.TrimSpace(string(data)))
+}
@@ -0,0 +1,44 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gomodule
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/perses/plugins/scripts/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetVersion(t *testing.T) {
+	dir := t.TempDir()
+	_, err := GetVersion(dir)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "VERSION"), []byte("0.2.0-rc.1\n"), 0o600))
+	version, err := GetVersion(dir)
+	require.NoError(t, err)
+	assert.Equal(t, tag.Version{Minor: 2, Prerelease: []string{"rc", "1"}}, version)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "VERSION"), []byte("latest\n"), 0o600))
+	_, err = GetVersion(dir)
+	assert.Error(t, err)
+}
+
+func TestIsModule(t *testing.T) {
+	assert.True(t, IsModule("sdk"))
+	assert.False(t, IsModule("prometheus"))
+}
@@ -0,0 +1,163 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package graph builds the dependency graph between the modules of this repository, from the CUE modules
+// (cue.mod/module.cue) and the Go modules (go.mod).
+package graph
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type Kind string
+
+const (
+	CUE Kind = "cue"
+	Go  Kind = "go"
+)
+
+var (
+	// matches `"github.com/perses/plugins/prometheus@v0": {` followed by `v: "v0.56.0"`
+	cueDependencyRegexp = regexp.MustCompile(`"github\.com/perses/plugins/([\w-]+)@v\d+":\s*\{\s*v:\s*"([^"]+)"`)
+	// matches `github.com/perses/plugins/sdk v0.1.0` in a require directive
+	goDependencyRegexp = regexp.MustCompile(`(?m)^(?:require\s+|\s+)github\.com/perses/plugins/([\w-]+)\s+(v\S+)`)
+)
+
+// Dependency is a module of this repository required by another one.
+type Dependency struct {
+	// Module is the folder of the module required.
+	Module string
+	// Version is the version required, starting with "v".
+	Version string
+	Kind    Kind
+}
+
+// Tag is the git tag of the dependency release.
+func (d Dependency) Tag() string {
+	return fmt.Sprintf("%s/%s", d.Module, d.Version)
+}
+
+// Graph gives the dependencies of each module.
+type Graph map[string][]Dependency
+
+// Build reads the CUE and Go modules of the given folders and returns their dependencies on other modules of this repository.
+func Build(dir string, modules []string) (Graph, error) {
+	g := make(Graph, len(modules))
+	for _, module := range modules {
+		cueDeps, err := readDependencies(filepath.Join(dir, module, "cue.mod", "module.cue"), cueDependencyRegexp, CUE)
+		if err != nil {
+			return nil, err
+		}
+		goDeps, err := readDependencies(filepath.Join(dir, module, "go.mod"), goDependencyRegexp, Go)
+		if err != nil {
+			return nil, err
+		}
+		for _, dep := range append(cueDeps, goDeps...) {
+			if dep.Module != module {
+				g[module] = append(g[module], dep)
+			}
+		}
+	}
+	return g, nil
+}
+
+func readDependencies(filePath string, re *regexp.Regexp, kind Kind) ([]Dependency, error) {
+	data, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var deps []Dependency
+	for _, match := range re.FindAllStringSubmatch(string(data), -1) {
+		deps = append(deps, Dependency{Module: match[1], Version: match[2], Kind: kind})
+	}
+	return deps, nil
+}
+
+// DependenciesOf returns the names of the modules the module depends on, optionally restricted to some kinds.
+func (g Graph) DependenciesOf(module string, kinds ...Kind) []string {
+	var result []string
+	for _, dep := range g[module] {
+		if (len(kinds) == 0 || slices.Contains(kinds, dep.Kind)) && !slices.Contains(result, dep.Module) {
+			result = append(result, dep.Module)
+		}
+	}
+	return result
+}
+
+// Sort returns the modules ordered so that each module comes after the modules it depends on.
+// Modules without any dependency between them keep their relative order. A cycle is an error.
+func (g Graph) Sort(modules []string) ([]string, error) {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(modules))
+	var result []string
+	var visit func(module string, path []string) error
+	visit = func(module string, path []string) error {
+		switch state[module] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle between the modules: %s -> %s", strings.Join(path, " -> "), module)
+		}
+		state[module] = visiting
+		for _, dep := range g.DependenciesOf(module) {
+			if !slices.Contains(modules, dep) {
+				continue
+			}
+			if err := visit(dep, append(path, module)); err != nil {
+				return err
+			}
+		}
+		state[module] = done
+		result = append(result, module)
+		return nil
+	}
+	for _, module := range modules {
+		if err := visit(module, nil); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// IsTagged tells whether the git tag exists in the repository located in dir.
+func IsTagged(dir string, tag string) bool {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", "refs/tags/"+tag)
+	cmd.Dir = dir
+	return cmd.Run() == nil
+}
+
+// Unpublished returns the dependencies of the module that have not been released yet.
+// A dependency is released when its tag exists, or when isReleased says so (e.g. released earlier in the same run).
+func (g Graph) Unpublished(dir string, module string, isReleased func(dep Dependency) bool) []Dependency {
+	var result []Dependency
+	for _, dep := range g[module] {
+		if IsTagged(dir, dep.Tag()) || (isReleased != nil && isReleased(dep)) {
+			continue
+		}
+		result = append(result, dep)
+	}
+	return result
+}
@@ -0,0 +1,115 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestBuild(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "datasourcevariable", "cue.mod", "module.cue"), `module: "github.com/perses/plugins/datasourcevariable@v0"
+deps: {
+	"github.com/perses/perses/cue@v0": {
+		v:       "v0.53.0"
+		default: true
+	}
+	"github.com/perses/plugins/prometheus@v0": {
+		v:       "v0.56.0"
+		default: true
+	}
+}
+`)
+	writeFile(t, filepath.Join(dir, "datasourcevariable", "go.mod"), `module github.com/perses/plugins/datasourcevariable
+
+go 1.26.0
+
+require (
+	github.com/perses/perses v0.53.1
+	github.com/perses/plugins/sdk v0.1.0
+)
+
+replace github.com/perses/plugins/sdk => ../sdk
+`)
+	writeFile(t, filepath.Join(dir, "prometheus", "go.mod"), "module github.com/perses/plugins/prometheus\n\nrequire github.com/perses/plugins/sdk v0.1.0\n")
+
+	g, err := Build(dir, []string{"datasourcevariable", "prometheus", "sdk"})
+	require.NoError(t, err)
+	assert.Equal(t, Graph{
+		"datasourcevariable": {
+			{Module: "prometheus", Version: "v0.56.0", Kind: CUE},
+			{Module: "sdk", Version: "v0.1.0", Kind: Go},
+		},
+		"prometheus": {
+			{Module: "sdk", Version: "v0.1.0", Kind: Go},
+		},
+	}, g)
+	assert.Equal(t, []string{"prometheus"}, g.DependenciesOf("datasourcevariable", CUE))
+	assert.Equal(t, "prometheus/v0.56.0", g["datasourcevariable"][0].Tag())
+}
+
+func TestSort(t *testing.T) {
+	g := Graph{
+		"datasourcevariable": {{Module: "prometheus", Kind: CUE}, {Module: "tempo", Kind: CUE}},
+		"prometheus":         {{Module: "sdk", Kind: Go}},
+		"tempo":              {{Module: "sdk", Kind: Go}},
+	}
+	result, err := g.Sort([]string{"barchart", "datasourcevariable", "prometheus", "tempo"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"barchart", "prometheus", "tempo", "datasourcevariable"}, result)
+
+	g["prometheus"] = append(g["prometheus"], Dependency{Module: "datasourcevariable", Kind: CUE})
+	_, err = g.Sort([]string{"datasourcevariable", "prometheus"})
+	assert.EqualError(t, err, "dependency cycle between the modules: datasourcevariable -> prometheus -> datasourcevariable")
+}
+
+func TestUnpublished(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		c := exec.Command("git", args...)
+		c.Dir = dir
+		out, err := c.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	git("init", "--quiet")
+	git("-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "--allow-empty", "-m", "init")
+	git("tag", "prometheus/v0.56.0")
+
+	g := Graph{
+		"datasourcevariable": {
+			{Module: "prometheus", Version: "v0.56.0", Kind: CUE},
+			{Module: "sdk", Version: "v0.1.0", Kind: Go},
+		},
+	}
+	assert.Equal(t, []Dependency{{Module: "sdk", Version: "v0.1.0", Kind: Go}}, g.Unpublished(dir, "datasourcevariable", nil))
+	// the sdk released earlier in the same run is not reported
+	assert.Empty(t, g.Unpublished(dir, "datasourcevariable", func(dep Dependency) bool {
+		return dep.Tag() == "sdk/v0.1.0"
+	}))
+	git("tag", "sdk/v0.1.0")
+	assert.Empty(t, g.Unpublished(dir, "datasourcevariable", nil))
+}
@@ -15,10 +15,9 @@ package manifest
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
-
-	"github.com/sirupsen/logrus"
 )
 
 type BuildInfo struct {
@@ -36,20 +35,34 @@ type Manifest struct {
 	Metadata Metadata `json:"metaData"`
 }
 
+// Parse decodes the content of a mf-manifest.json file and checks that it gives the ID, the name and the version of the plugin.
+func Parse(data []byte) (*Manifest, error) {
+	manif := &Manifest{}
+	if err := json.Unmarshal(data, manif); err != nil {
+		return nil, err
+	}
+	if len(manif.ID) == 0 {
+		return nil, fmt.Errorf("manifest ID is empty")
+	}
+	if len(manif.Name) == 0 {
+		return nil, fmt.Errorf("manifest name is empty")
+	}
+	if len(manif.Metadata.BuildInfo.Version) == 0 {
+		return nil, fmt.Errorf("manifest build version is empty")
+	}
+	return manif, nil
+}
+
+// Read reads the manifest generated by the build of the plugin located in pluginPath.
 func Read(pluginPath string) (*Manifest, error) {
 	manifestFilePath := filepath.Join(pluginPath, "dist", "mf-manifest.json")
 	data, err := os.ReadFile(manifestFilePath)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("unable to read the manifest of the plugin %s: %w", pluginPath, err)
 	}
-	manifestData := &Manifest{}
-	return manifestData, json.Unmarshal(data, manifestData)
-}
-
-func MustRead(pluginPath string) *Manifest {
-	manif, err := Read(pluginPath)
+	manif, err := Parse(data)
 	if err != nil {
-		logrus.WithError(err).Fatalf("unable to read manifest file for plugin %s", pluginPath)
+		return nil, fmt.Errorf("invalid manifest %s: %w", manifestFilePath, err)
 	}
-	return manif
+	return manif, nil
 }
@@ -0,0 +1,77 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		data     string
+		expected *Manifest
+		err      string
+	}{
+		{
+			title: "valid manifest",
+			data:  `{"id": "Prometheus", "name": "Prometheus", "metaData": {"buildInfo": {"buildVersion": "0.58.0", "buildName": "@perses-dev/prometheus-plugin"}}}`,
+			expected: &Manifest{ID: "Prometheus", Name: "Prometheus", Metadata: Metadata{
+				BuildInfo: BuildInfo{Version: "0.58.0", Name: "@perses-dev/prometheus-plugin"},
+			}},
+		},
+		{
+			title: "invalid JSON",
+			data:  `{"id": `,
+			err:   "unexpected end of JSON input",
+		},
+		{
+			title: "missing ID",
+			data:  `{"name": "Prometheus", "metaData": {"buildInfo": {"buildVersion": "0.58.0"}}}`,
+			err:   "manifest ID is empty",
+		},
+		{
+			title: "missing version",
+			data:  `{"id": "Prometheus", "name": "Prometheus"}`,
+			err:   "manifest build version is empty",
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			result, err := Parse([]byte(test.data))
+			if len(test.err) > 0 {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	pluginPath := t.TempDir()
+	_, err := Read(pluginPath)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginPath, "dist"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginPath, "dist", "mf-manifest.json"), []byte(`{"id": "Tempo"}`), 0o600))
+	_, err = Read(pluginPath)
+	assert.ErrorContains(t, err, "manifest name is empty")
+}
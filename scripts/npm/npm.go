@@ -14,15 +14,31 @@
 package npm
 
 import (
+	"fmt"
 	"slices"
 
 	"github.com/perses/perses/scripts/pkg/npm"
+	"github.com/perses/plugins/scripts/tag"
 )
 
-func MustGetWorkspaces(dirPath string) []string {
-	excludedWorkspaces := []string{"e2e"}
-	workspaces := npm.MustGetWorkspaces(dirPath)
+var excludedWorkspaces = []string{"e2e"}
+
+// GetWorkspaces returns the plugin workspaces declared in the package.json located in dirPath.
+func GetWorkspaces(dirPath string) ([]string, error) {
+	workspaces, err := npm.GetWorkspaces(dirPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the workspaces from package.json: %w", err)
+	}
 	return slices.DeleteFunc(workspaces, func(w string) bool {
 		return slices.Contains(excludedWorkspaces, w)
-	})
+	}), nil
+}
+
+// GetVersion returns the version of the plugin located in pluginPath, read from its package.json.
+func GetVersion(pluginPath string) (tag.Version, error) {
+	version, err := npm.GetVersion(pluginPath)
+	if err != nil {
+		return tag.Version{}, fmt.Errorf("unable to read the version of the plugin %s: %w", pluginPath, err)
+	}
+	return tag.ParseVersion(version)
 }
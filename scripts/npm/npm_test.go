@@ -0,0 +1,73 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package npm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/perses/plugins/scripts/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetWorkspaces(t *testing.T) {
+	dir := t.TempDir()
+	_, err := GetWorkspaces(dir)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"workspaces": ["prometheus", "e2e", "tempo"]}`), 0o600))
+	workspaces, err := GetWorkspaces(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prometheus", "tempo"}, workspaces)
+}
+
+func TestGetVersion(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		version  string
+		expected tag.Version
+		err      bool
+	}{
+		{
+			title:    "release",
+			version:  "0.58.0",
+			expected: tag.Version{Minor: 58},
+		},
+		{
+			title:    "prerelease",
+			version:  "0.58.0-beta.0",
+			expected: tag.Version{Minor: 58, Prerelease: []string{"beta", "0"}},
+		},
+		{
+			title:   "invalid version",
+			version: "latest",
+			err:     true,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"version": "`+test.version+`"}`), 0o600))
+			result, err := GetVersion(dir)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
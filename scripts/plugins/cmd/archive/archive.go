@@ -0,0 +1,120 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package archive
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	pluginArchive "github.com/perses/plugins/scripts/archive"
+	"github.com/perses/plugins/scripts/manifest"
+	"github.com/perses/plugins/scripts/plugins/cmd"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const groupArchiveFolder = "plugins-archive"
+
+type option struct {
+	group      bool
+	workspaces []string
+	// groupArchives are the archives written in the group folder by this run.
+	groupArchives []string
+	mutex         sync.Mutex
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("no args are supported by the command 'archive'")
+	}
+	workspaces, err := cmd.GlobalOptions.Workspaces()
+	if err != nil {
+		return err
+	}
+	o.workspaces = workspaces
+	return nil
+}
+
+func (o *option) Validate() error {
+	return nil
+}
+
+func (o *option) createArchive(pluginName string) error {
+	manif, err := manifest.Read(pluginName)
+	if err != nil {
+		return err
+	}
+	modTime, err := pluginArchive.ModTime()
+	if err != nil {
+		return err
+	}
+	archiveName := fmt.Sprintf("%s-%s.tar.gz", manif.ID, manif.Metadata.BuildInfo.Version)
+	archivePaths := []string{filepath.Join(pluginName, archiveName)}
+	if o.group {
+		// The archive is written again rather than copied, it gives the same bytes anyway.
+		archivePaths = append(archivePaths, filepath.Join(groupArchiveFolder, archiveName))
+	}
+	for _, archivePath := range archivePaths {
+		if cmd.GlobalOptions.DryRun {
+			logrus.Infof("[dry-run] writing the archive %s", archivePath)
+			continue
+		}
+		if writeErr := pluginArchive.Write(archivePath, pluginName, pluginArchive.PluginFiles, modTime); writeErr != nil {
+			return writeErr
+		}
+	}
+	if cmd.GlobalOptions.DryRun {
+		return nil
+	}
+	if o.group {
+		o.mutex.Lock()
+		o.groupArchives = append(o.groupArchives, archivePaths[1])
+		o.mutex.Unlock()
+	}
+	return pluginArchive.WriteChecksums(pluginName, archivePaths[:1])
+}
+
+func (o *option) Execute() error {
+	if o.group && !cmd.GlobalOptions.DryRun {
+		if err := os.MkdirAll(groupArchiveFolder, 0o755); err != nil {
+			return fmt.Errorf("unable to create the folder %s: %w", groupArchiveFolder, err)
+		}
+	}
+	results := cmd.ForEachPlugin(0, o.workspaces, func(_ context.Context, pluginName string, _ io.Writer) error {
+		return o.createArchive(pluginName)
+	})
+	if o.group && !cmd.GlobalOptions.DryRun {
+		if err := pluginArchive.WriteChecksums(groupArchiveFolder, o.groupArchives); err != nil {
+			return fmt.Errorf("unable to write the checksums of the folder %s: %w", groupArchiveFolder, err)
+		}
+	}
+	return cmd.Report(os.Stdout, "archive", results)
+}
+
+func NewCMD() *cobra.Command {
+	o := &option{}
+	c := &cobra.Command{
+		Use:   "archive",
+		Short: "Create the reproducible archive of the built plugins, with their SHA256SUMS",
+		RunE: func(c *cobra.Command, args []string) error {
+			return cmd.Run(o, c, args)
+		},
+	}
+	c.Flags().BoolVar(&o.group, "group", false, fmt.Sprintf("also put the archives in the folder %s", groupArchiveFolder))
+	return c
+}
@@ -0,0 +1,88 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/perses/plugins/scripts/gomodule"
+	"github.com/perses/plugins/scripts/plugins/cmd"
+	"github.com/perses/plugins/scripts/tag"
+	"github.com/spf13/cobra"
+)
+
+// taskTimeout is the default time given to build a plugin.
+const taskTimeout = 20 * time.Minute
+
+type option struct {
+	tag        string
+	workspaces []string
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("no args are supported by the command 'build'")
+	}
+	if len(o.tag) > 0 {
+		t, err := tag.Parse(o.tag)
+		if err != nil {
+			return err
+		}
+		if gomodule.IsModule(t.Plugin) {
+			return fmt.Errorf("%s is a Go module, there is no plugin to build", t.Plugin)
+		}
+		o.workspaces = []string{t.Plugin}
+		return nil
+	}
+	workspaces, err := cmd.GlobalOptions.Workspaces()
+	if err != nil {
+		return err
+	}
+	o.workspaces = workspaces
+	return nil
+}
+
+func (o *option) Validate() error {
+	return nil
+}
+
+func (o *option) Execute() error {
+	results := cmd.ForEachPlugin(taskTimeout, o.workspaces, func(ctx context.Context, workspace string, log io.Writer) error {
+		return cmd.Exec(ctx, log, "", "percli", "plugin", "build", fmt.Sprintf("--plugin.path=%s", workspace), "--skip.npm-install=true")
+	})
+	return cmd.Report(os.Stdout, "build", results)
+}
+
+func NewCMD() *cobra.Command {
+	o := &option{}
+	c := &cobra.Command{
+		Use:   "build",
+		Short: "Build the plugins and their archive with percli",
+		Example: `
+# Build all the plugins
+$ plugins build
+
+# Build the plugin of a release
+$ plugins build --tag=prometheus/v0.58.0`,
+		RunE: func(c *cobra.Command, args []string) error {
+			return cmd.Run(o, c, args)
+		},
+	}
+	c.Flags().StringVar(&o.tag, "tag", "", "build only the plugin of this release tag")
+	return c
+}
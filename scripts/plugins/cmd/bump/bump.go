@@ -0,0 +1,80 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bump
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/perses/plugins/scripts/plugins/cmd"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var bumpTypes = []string{"major", "minor", "patch"}
+
+type pluginOption struct {
+	bumpType string
+}
+
+func (o *pluginOption) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("no args are supported by the command 'bump plugin'")
+	}
+	return nil
+}
+
+func (o *pluginOption) Validate() error {
+	if !slices.Contains(bumpTypes, o.bumpType) {
+		return fmt.Errorf("--type must be one of %v", bumpTypes)
+	}
+	return nil
+}
+
+func (o *pluginOption) Execute() error {
+	if len(cmd.GlobalOptions.Plugins) == 0 {
+		logrus.Info("bumping all the plugins")
+		return cmd.RunCommand("", "npm", "version", o.bumpType, "--workspaces", "--no-git-tag-version")
+	}
+	for _, plugin := range cmd.GlobalOptions.Plugins {
+		logrus.Infof("bumping %s", plugin)
+		if err := cmd.RunCommand("", "npm", "version", o.bumpType, "--workspace", plugin, "--no-git-tag-version"); err != nil {
+			return fmt.Errorf("unable to bump the version of the plugin %s: %w", plugin, err)
+		}
+	}
+	return nil
+}
+
+func newPluginCMD() *cobra.Command {
+	o := &pluginOption{}
+	c := &cobra.Command{
+		Use:   "plugin",
+		Short: "Bump the version of the plugins (all of them, or the ones set with --plugins)",
+		RunE: func(c *cobra.Command, args []string) error {
+			return cmd.Run(o, c, args)
+		},
+	}
+	c.Flags().StringVar(&o.bumpType, "type", "minor", "the type of version bump (major, minor, patch)")
+	return c
+}
+
+func NewCMD() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "bump",
+		Short: "Bump the dependencies or the version of the plugins",
+	}
+	c.AddCommand(newDepsCMD())
+	c.AddCommand(newPluginCMD())
+	return c
+}
@@ -0,0 +1,379 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bump
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/perses/plugins/scripts/gomodule"
+	"github.com/perses/plugins/scripts/plugins/cmd"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	persesGoModule  = "github.com/perses/perses"
+	persesCUEModule = "github.com/perses/perses/cue"
+	sharedCUEModule = "github.com/perses/shared/cue"
+)
+
+var (
+	// sharedPackageNames contains the list of packages release by the repository perses/shared to bump
+	sharedPackageNames = []string{"components", "dashboards", "plugin-system", "explore"}
+	persesPackageName  = "core"
+	goDepRegexp        = regexp.MustCompile(`(?m)^(\s*(?:require\s+)?` + regexp.QuoteMeta(persesGoModule) + `\s+)(v\S+)`)
+)
+
+func npmDepRegexp(name string) *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf(`"@perses-dev/%s":\s*"(\^)?([0-9]+\.[0-9]+\.[0-9]+(-(alpha|beta|rc)\.[0-9]+)?)"`, name))
+}
+
+func cueDepRegexp(module string) *regexp.Regexp {
+	return regexp.MustCompile(`("` + regexp.QuoteMeta(module) + `@v\d+":\s*\{\s*v:\s*")([^"]+)(")`)
+}
+
+func replaceNPMPackage(data []byte, version string, componentNames ...string) []byte {
+	newData := data
+	for _, name := range componentNames {
+		newData = npmDepRegexp(name).ReplaceAll(newData, []byte(fmt.Sprintf(`"@perses-dev/%s": "^%s"`, name, version)))
+	}
+	return newData
+}
+
+// change is a dependency bumped in a workspace.
+type change struct {
+	Workspace  string `json:"workspace"`
+	Kind       string `json:"kind"`
+	Dependency string `json:"dependency"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+}
+
+// edit is the planned modification of a file.
+type edit struct {
+	path   string
+	before []byte
+	after  []byte
+	// write is false when the file is modified by a command (go get, cue mod get) rather than by the bump itself.
+	write bool
+}
+
+// step is a command run after the files are edited.
+type step struct {
+	dir  string
+	name string
+	args []string
+}
+
+// plan is everything the bump will do. It is computed before modifying anything, so it can be displayed with --dry-run.
+type plan struct {
+	edits   []edit
+	changes []change
+	steps   []step
+	// touched are the files modified by the edits or the steps, saved before the bump to be restored if it fails.
+	touched []string
+}
+
+func workspaceName(workspace string) string {
+	if len(workspace) == 0 {
+		return "."
+	}
+	return workspace
+}
+
+// read returns the content of the file as planned so far: the result of its pending edit, or its content on disk.
+// Several bumps can edit the same file (e.g. --version and --shared-version both edit package.json), so each one must
+// start from the previous one.
+func (p *plan) read(path string) ([]byte, error) {
+	for _, e := range p.edits {
+		if e.path == path {
+			return e.after, nil
+		}
+	}
+	return os.ReadFile(path)
+}
+
+// addEdit records the new content of the file, merging it with its pending edit if any.
+func (p *plan) addEdit(path string, before []byte, after []byte, write bool) {
+	for i, e := range p.edits {
+		if e.path == path {
+			p.edits[i].after = after
+			p.edits[i].write = e.write || write
+			return
+		}
+	}
+	p.edits = append(p.edits, edit{path: path, before: before, after: after, write: write})
+	p.touched = append(p.touched, path)
+}
+
+func (p *plan) planNPM(workspace string, version string, componentNames ...string) error {
+	pkgPath := filepath.Join(workspace, "package.json")
+	data, err := p.read(pkgPath)
+	if err != nil {
+		return fmt.Errorf("unable to read the file %s: %w", pkgPath, err)
+	}
+	for _, name := range componentNames {
+		if match := npmDepRegexp(name).FindSubmatch(data); match != nil && string(match[2]) != version {
+			p.changes = append(p.changes, change{Workspace: workspaceName(workspace), Kind: "npm", Dependency: "@perses-dev/" + name, From: string(match[2]), To: version})
+		}
+	}
+	newData := replaceNPMPackage(data, version, componentNames...)
+	if string(newData) != string(data) {
+		p.addEdit(pkgPath, data, newData, true)
+	}
+	return nil
+}
+
+func (p *plan) planGo(workspace string, version string) error {
+	goModPath := filepath.Join(workspace, "go.mod")
+	data, err := p.read(goModPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read the file %s: %w", goModPath, err)
+	}
+	match := goDepRegexp.FindSubmatch(data)
+	if match == nil || string(match[2]) == "v"+version {
+		return nil
+	}
+	p.changes = append(p.changes, change{Workspace: workspace, Kind: "go", Dependency: persesGoModule, From: strings.TrimPrefix(string(match[2]), "v"), To: version})
+	p.addEdit(goModPath, data, goDepRegexp.ReplaceAll(data, []byte("${1}v"+version)), false)
+	p.touched = append(p.touched, filepath.Join(workspace, "go.sum"))
+	p.steps = append(p.steps,
+		step{dir: workspace, name: "go", args: []string{"get", fmt.Sprintf("%s@v%s", persesGoModule, version)}},
+		step{dir: workspace, name: "go", args: []string{"mod", "tidy"}},
+	)
+	return nil
+}
+
+func (p *plan) planCUE(workspace string, version string, module string) error {
+	cueModPath := filepath.Join(workspace, "cue.mod", "module.cue")
+	data, err := p.read(cueModPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read the file %s: %w", cueModPath, err)
+	}
+	re := cueDepRegexp(module)
+	match := re.FindSubmatch(data)
+	if match == nil || string(match[2]) == "v"+version {
+		return nil
+	}
+	p.changes = append(p.changes, change{Workspace: workspace, Kind: "cue", Dependency: module, From: strings.TrimPrefix(string(match[2]), "v"), To: version})
+	p.addEdit(cueModPath, data, re.ReplaceAll(data, []byte("${1}v"+version+"${3}")), false)
+	p.steps = append(p.steps,
+		step{dir: workspace, name: "cue", args: []string{"mod", "get", fmt.Sprintf("%s@v%s", module, version)}},
+		step{dir: workspace, name: "cue", args: []string{"mod", "tidy"}},
+	)
+	return nil
+}
+
+// diff returns the planned edits as a unified diff.
+func (p *plan) diff() (string, error) {
+	var sb strings.Builder
+	for _, e := range p.edits {
+		d, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(e.before)),
+			B:        difflib.SplitLines(string(e.after)),
+			FromFile: "a/" + filepath.ToSlash(e.path),
+			ToFile:   "b/" + filepath.ToSlash(e.path),
+			Context:  3,
+		})
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(d)
+	}
+	return sb.String(), nil
+}
+
+// apply runs the bump. If any step fails, every file touched is restored to its original content.
+func (p *plan) apply() (err error) {
+	s := newSnapshot()
+	if snapshotErr := s.add(p.touched...); snapshotErr != nil {
+		return snapshotErr
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		if restoreErr := s.restore(); restoreErr != nil {
+			err = fmt.Errorf("%w (and unable to restore the files: %v)", err, restoreErr)
+			return
+		}
+		logrus.Warn("the bump failed, all the files have been restored")
+	}()
+	for _, e := range p.edits {
+		if !e.write {
+			continue
+		}
+		if writeErr := os.WriteFile(e.path, e.after, 0644); writeErr != nil {
+			return fmt.Errorf("unable to write the file %s: %w", e.path, writeErr)
+		}
+	}
+	for _, st := range p.steps {
+		if runErr := cmd.RunCommand(st.dir, st.name, st.args...); runErr != nil {
+			return fmt.Errorf("unable to bump the dependencies of %s: %w", workspaceName(st.dir), runErr)
+		}
+	}
+	return nil
+}
+
+func (p *plan) printSummary() error {
+	if cmd.GlobalOptions.Output == cmd.JSONOutput {
+		data, err := json.MarshalIndent(struct {
+			DryRun  bool     `json:"dryRun"`
+			Changes []change `json:"changes"`
+		}{DryRun: cmd.GlobalOptions.DryRun, Changes: p.changes}, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(os.Stdout, string(data))
+		return err
+	}
+	if len(p.changes) == 0 {
+		logrus.Info("all the dependencies are already up to date")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "WORKSPACE\tKIND\tDEPENDENCY\tFROM\tTO")
+	for _, c := range p.changes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.Workspace, c.Kind, c.Dependency, c.From, c.To)
+	}
+	return w.Flush()
+}
+
+type depsOption struct {
+	version       string
+	sharedVersion string
+	workspaces    []string
+	// goModules are the Go modules that are not plugins (see gomodule.Modules). Their github.com/perses/perses
+	// requirement is always bumped, as the plugins require them.
+	goModules []string
+}
+
+func (o *depsOption) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("no args are supported by the command 'bump deps'")
+	}
+	workspaces, err := cmd.GlobalOptions.Workspaces()
+	if err != nil {
+		return err
+	}
+	o.workspaces = workspaces
+	o.goModules = gomodule.Modules
+	return nil
+}
+
+func (o *depsOption) Validate() error {
+	if o.version == "" && o.sharedVersion == "" {
+		return fmt.Errorf("you must provide a version to use for the bump")
+	}
+	return nil
+}
+
+func (o *depsOption) plan() (*plan, error) {
+	p := &plan{}
+	if o.version != "" {
+		if err := p.planNPM("", o.version, persesPackageName); err != nil {
+			return nil, err
+		}
+		for _, module := range o.goModules {
+			if err := p.planGo(module, o.version); err != nil {
+				return nil, err
+			}
+		}
+		for _, workspace := range o.workspaces {
+			if err := p.planGo(workspace, o.version); err != nil {
+				return nil, err
+			}
+			if err := p.planNPM(workspace, o.version, persesPackageName); err != nil {
+				return nil, err
+			}
+			if err := p.planCUE(workspace, o.version, persesCUEModule); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if o.sharedVersion != "" {
+		if err := p.planNPM("", o.sharedVersion, sharedPackageNames...); err != nil {
+			return nil, err
+		}
+		for _, workspace := range o.workspaces {
+			if err := p.planNPM(workspace, o.sharedVersion, sharedPackageNames...); err != nil {
+				return nil, err
+			}
+			if err := p.planCUE(workspace, o.sharedVersion, sharedCUEModule); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(p.changes) > 0 {
+		p.touched = append(p.touched, "package-lock.json")
+		p.steps = append(p.steps, step{name: "npm", args: []string{"install"}})
+	}
+	return p, nil
+}
+
+func (o *depsOption) Execute() error {
+	p, err := o.plan()
+	if err != nil {
+		return err
+	}
+	if cmd.GlobalOptions.DryRun {
+		d, diffErr := p.diff()
+		if diffErr != nil {
+			return diffErr
+		}
+		fmt.Fprint(os.Stderr, d)
+		for _, st := range p.steps {
+			logrus.Infof("[dry-run] %s: %s %s", workspaceName(st.dir), st.name, strings.Join(st.args, " "))
+		}
+		logrus.Info("[dry-run] go.mod and cue.mod/module.cue are modified by the commands above, which may change more than the diff shows (go.sum, indirect dependencies)")
+		return p.printSummary()
+	}
+	if applyErr := p.apply(); applyErr != nil {
+		return applyErr
+	}
+	return p.printSummary()
+}
+
+func newDepsCMD() *cobra.Command {
+	o := &depsOption{}
+	c := &cobra.Command{
+		Use:   "deps",
+		Short: "Bump the perses and perses/shared dependencies (go, CUE and npm) of the plugins",
+		Long:  "Bump all the perses-dev and perses/shared dependencies for go, CUE and npm packages to the provided version, including the Go modules shared by the plugins (sdk). The version provided does not contain the prefix 'v'. With --dry-run, the planned edits are printed as a diff and nothing is modified. If any step fails, every file modified by the bump is restored.",
+		Example: `
+$ plugins bump deps --version=0.52.0-beta.4 --shared-version=0.10.0
+
+# Show the edits without modifying anything
+$ plugins --dry-run bump deps --version=0.52.0-beta.4`,
+		RunE: func(c *cobra.Command, args []string) error {
+			return cmd.Run(o, c, args)
+		},
+	}
+	c.Flags().StringVar(&o.version, "version", "", "the version to use for the bump.")
+	c.Flags().StringVar(&o.sharedVersion, "shared-version", "", "the version for the shared component to use for the bump.")
+	return c
+}
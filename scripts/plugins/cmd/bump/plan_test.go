@@ -0,0 +1,164 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bump
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "go.mod")
+	missing := filepath.Join(dir, "go.sum")
+	require.NoError(t, os.WriteFile(existing, []byte("module example\n"), 0644))
+
+	s := newSnapshot()
+	require.NoError(t, s.add(existing, missing))
+	require.NoError(t, os.WriteFile(existing, []byte("module modified\n"), 0644))
+	require.NoError(t, os.WriteFile(missing, []byte("checksum\n"), 0644))
+	// saving a file again must keep its first content
+	require.NoError(t, s.add(existing))
+
+	require.NoError(t, s.restore())
+	data, err := os.ReadFile(existing)
+	require.NoError(t, err)
+	assert.Equal(t, "module example\n", string(data))
+	assert.NoFileExists(t, missing)
+}
+
+func TestPlan(t *testing.T) {
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.WriteFile("package.json", []byte(`{
+  "devDependencies": {
+    "@perses-dev/core": "^0.52.0"
+  }
+}
+`), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join("example", "cue.mod"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join("example", "package.json"), []byte(`{
+  "peerDependencies": {
+    "@perses-dev/core": "^0.53.0",
+    "@perses-dev/components": "^0.53.0"
+  }
+}
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join("example", "go.mod"), []byte(`module github.com/perses/plugins/example
+
+go 1.25
+
+require github.com/perses/perses v0.52.0
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join("example", "cue.mod", "module.cue"), []byte(`module: "github.com/perses/plugins/example@v0"
+deps: {
+	"github.com/perses/perses/cue@v0": {
+		v: "v0.52.0"
+	}
+}
+`), 0644))
+
+	require.NoError(t, os.MkdirAll("sdk", 0755))
+	require.NoError(t, os.WriteFile(filepath.Join("sdk", "go.mod"), []byte(`module github.com/perses/plugins/sdk
+
+go 1.25
+
+require github.com/perses/perses v0.52.0
+`), 0644))
+
+	o := &depsOption{version: "0.53.0", workspaces: []string{"example"}, goModules: []string{"sdk"}}
+	p, err := o.plan()
+	require.NoError(t, err)
+	assert.Equal(t, []change{
+		{Workspace: ".", Kind: "npm", Dependency: "@perses-dev/core", From: "0.52.0", To: "0.53.0"},
+		{Workspace: "sdk", Kind: "go", Dependency: persesGoModule, From: "0.52.0", To: "0.53.0"},
+		{Workspace: "example", Kind: "go", Dependency: persesGoModule, From: "0.52.0", To: "0.53.0"},
+		{Workspace: "example", Kind: "cue", Dependency: persesCUEModule, From: "0.52.0", To: "0.53.0"},
+	}, p.changes)
+	assert.Contains(t, p.touched, "package-lock.json")
+	assert.Contains(t, p.touched, filepath.Join("example", "go.sum"))
+	assert.Contains(t, p.touched, filepath.Join("sdk", "go.sum"))
+
+	d, err := p.diff()
+	require.NoError(t, err)
+	assert.Contains(t, d, "-    \"@perses-dev/core\": \"^0.52.0\"\n+    \"@perses-dev/core\": \"^0.53.0\"\n")
+	assert.Contains(t, d, "-require github.com/perses/perses v0.52.0\n+require github.com/perses/perses v0.53.0\n")
+	assert.Contains(t, d, "-\t\tv: \"v0.52.0\"\n+\t\tv: \"v0.53.0\"\n")
+	assert.NotContains(t, d, "example/package.json")
+
+	// nothing is modified when planning
+	data, err := os.ReadFile("package.json")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "^0.52.0")
+}
+
+func TestPlan_VersionAndSharedVersion(t *testing.T) {
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.WriteFile("package.json", []byte(`{
+  "devDependencies": {
+    "@perses-dev/core": "^0.52.0",
+    "@perses-dev/components": "^0.9.0"
+  }
+}
+`), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join("example", "cue.mod"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join("example", "package.json"), []byte(`{
+  "peerDependencies": {
+    "@perses-dev/core": "^0.52.0",
+    "@perses-dev/plugin-system": "^0.9.0"
+  }
+}
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join("example", "cue.mod", "module.cue"), []byte(`module: "github.com/perses/plugins/example@v0"
+deps: {
+	"github.com/perses/perses/cue@v0": {
+		v: "v0.52.0"
+	}
+	"github.com/perses/shared/cue@v0": {
+		v: "v0.9.0"
+	}
+}
+`), 0644))
+
+	o := &depsOption{version: "0.53.0", sharedVersion: "0.10.0", workspaces: []string{"example"}}
+	p, err := o.plan()
+	require.NoError(t, err)
+	assert.Len(t, p.changes, 6)
+
+	// each file is edited once, with both bumps
+	var paths []string
+	for _, e := range p.edits {
+		paths = append(paths, e.path)
+	}
+	assert.ElementsMatch(t, []string{"package.json", filepath.Join("example", "package.json"), filepath.Join("example", "cue.mod", "module.cue")}, paths)
+	for _, e := range p.edits {
+		assert.NotContains(t, string(e.after), "0.52.0", e.path)
+		assert.NotContains(t, string(e.after), "0.9.0", e.path)
+	}
+
+	// the commands (cue mod get, npm install) are not available in the tests
+	p.steps = nil
+	require.NoError(t, p.apply())
+	root, err := os.ReadFile("package.json")
+	require.NoError(t, err)
+	assert.Contains(t, string(root), `"@perses-dev/core": "^0.53.0"`)
+	assert.Contains(t, string(root), `"@perses-dev/components": "^0.10.0"`)
+	workspace, err := os.ReadFile(filepath.Join("example", "package.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(workspace), `"@perses-dev/core": "^0.53.0"`)
+	assert.Contains(t, string(workspace), `"@perses-dev/plugin-system": "^0.10.0"`)
+}
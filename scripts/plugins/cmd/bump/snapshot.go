@@ -0,0 +1,68 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bump
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// snapshot keeps the content of files before they are modified, to restore them if the bump fails.
+type snapshot struct {
+	// files gives the original content of each file, nil when the file didn't exist.
+	files map[string][]byte
+	order []string
+}
+
+func newSnapshot() *snapshot {
+	return &snapshot{files: make(map[string][]byte)}
+}
+
+// add saves the current content of the files. A file already saved is kept as it was when saved the first time.
+func (s *snapshot) add(paths ...string) error {
+	for _, p := range paths {
+		p = filepath.Clean(p)
+		if _, ok := s.files[p]; ok {
+			continue
+		}
+		data, err := os.ReadFile(p)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("unable to save the file %s: %w", p, err)
+		}
+		s.files[p] = data
+		s.order = append(s.order, p)
+	}
+	return nil
+}
+
+// restore writes back the saved content of every file, and removes the files that didn't exist.
+func (s *snapshot) restore() error {
+	var errs []error
+	for _, p := range slices.Backward(s.order) {
+		data := s.files[p]
+		if data == nil {
+			if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if err := os.WriteFile(p, data, 0644); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
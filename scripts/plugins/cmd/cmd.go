@@ -0,0 +1,239 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cmd contains what is shared by the subcommands of the plugins CLI: the global flags, the selection of the
+// plugins to process and the report of the results.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/perses/plugins/scripts/affected"
+	"github.com/perses/plugins/scripts/npm"
+	"github.com/perses/plugins/scripts/runner"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	TextOutput  = "text"
+	JSONOutput  = "json"
+	JUnitOutput = "junit"
+)
+
+// Global are the options set with the global flags of the CLI.
+type Global struct {
+	DryRun      bool
+	Plugins     []string
+	Affected    string
+	Concurrency int
+	Timeout     time.Duration
+	TaskTimeout time.Duration
+	LogDir      string
+	Output      string
+}
+
+// GlobalOptions is filled by the persistent flags of the root command.
+var GlobalOptions = &Global{}
+
+// ctx is the context of the running command, cancelled on SIGINT.
+var ctx = context.Background()
+
+// Context returns the context of the running command. It is cancelled when the CLI is interrupted.
+func Context() context.Context {
+	return ctx
+}
+
+func (g *Global) AddFlags(cmd *cobra.Command) {
+	flags := cmd.PersistentFlags()
+	flags.BoolVar(&g.DryRun, "dry-run", false, "do not perform any changes, only print what would be done")
+	flags.StringSliceVar(&g.Plugins, "plugins", nil, "comma-separated list of the plugins (folder names) to process. By default, all the plugins are processed")
+	flags.StringVar(&g.Affected, "affected", "", "only process the plugins affected by the changes made since this git ref (e.g. origin/main)")
+	flags.IntVar(&g.Concurrency, "concurrency", 0, "maximum number of plugins processed at the same time. By default, the number of CPUs")
+	flags.DurationVar(&g.Timeout, "timeout", 0, "deadline of the whole command, the plugins not processed by then are reported as timed out. No deadline by default")
+	flags.DurationVar(&g.TaskTimeout, "task-timeout", 0, "deadline to process a plugin, after which the commands it runs are killed. By default, the timeout of the command (e.g. 20m for build, 3m for lint)")
+	flags.StringVar(&g.LogDir, "log-dir", "", "folder where the output of each plugin is written, in <command>-<plugin>.log")
+	flags.StringVar(&g.Output, "output", TextOutput, "format of the final report: text, json or junit")
+}
+
+func (g *Global) Validate() error {
+	if g.Output != TextOutput && g.Output != JSONOutput && g.Output != JUnitOutput {
+		return fmt.Errorf("--output must be %q, %q or %q", TextOutput, JSONOutput, JUnitOutput)
+	}
+	if g.Concurrency < 0 {
+		return fmt.Errorf("--concurrency must be positive")
+	}
+	if g.Timeout < 0 || g.TaskTimeout < 0 {
+		return fmt.Errorf("--timeout and --task-timeout must be positive")
+	}
+	return nil
+}
+
+// RunnerOptions returns the options of the runner processing the plugins. taskTimeout is the default timeout of the
+// command, used when --task-timeout is not set.
+func (g *Global) RunnerOptions(taskTimeout time.Duration) runner.Options {
+	if g.TaskTimeout > 0 {
+		taskTimeout = g.TaskTimeout
+	}
+	return runner.Options{Concurrency: g.Concurrency, Timeout: g.Timeout, TaskTimeout: taskTimeout}
+}
+
+// Workspaces returns the plugins to process, according to --plugins and --affected.
+func (g *Global) Workspaces() ([]string, error) {
+	workspaces, err := npm.GetWorkspaces(".")
+	if err != nil {
+		return nil, err
+	}
+	if len(g.Plugins) > 0 {
+		for _, p := range g.Plugins {
+			if !slices.Contains(workspaces, p) {
+				return nil, fmt.Errorf("unknown plugin %q, the plugins are: %s", p, strings.Join(workspaces, ", "))
+			}
+		}
+		workspaces = slices.DeleteFunc(workspaces, func(w string) bool {
+			return !slices.Contains(g.Plugins, w)
+		})
+	}
+	if len(g.Affected) == 0 {
+		return workspaces, nil
+	}
+	result, err := affected.Filter(".", g.Affected, workspaces)
+	if err != nil {
+		return nil, err
+	}
+	logrus.Infof("%d plugin(s) affected since %s: %s", len(result), g.Affected, strings.Join(result, ", "))
+	return result, nil
+}
+
+// Option is implemented by every subcommand.
+type Option interface {
+	// Complete fills the option from the arguments of the command.
+	Complete(args []string) error
+	// Validate checks the option.
+	Validate() error
+	// Execute runs the command.
+	Execute() error
+}
+
+// Run is the RunE of every subcommand.
+func Run(o Option, c *cobra.Command, args []string) error {
+	if c.Context() != nil {
+		ctx = c.Context()
+	}
+	if err := GlobalOptions.Validate(); err != nil {
+		return err
+	}
+	if err := o.Complete(args); err != nil {
+		return err
+	}
+	if err := o.Validate(); err != nil {
+		return err
+	}
+	return o.Execute()
+}
+
+// ForEachPlugin runs the task on every plugin with the runner configured by the global flags, and returns the results
+// in the order of the plugins. taskTimeout is the default timeout to process a plugin.
+func ForEachPlugin(taskTimeout time.Duration, workspaces []string, task func(ctx context.Context, workspace string, log io.Writer) error) []runner.Result {
+	tasks := make([]runner.Task, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		tasks = append(tasks, runner.Task{
+			Name: workspace,
+			Run: func(ctx context.Context, log io.Writer) error {
+				return task(ctx, workspace, log)
+			},
+		})
+	}
+	return runner.Run(ctx, GlobalOptions.RunnerOptions(taskTimeout), tasks)
+}
+
+// Report writes the results in the format set by --output and returns an error when a plugin failed.
+// The output of each plugin is written in --log-dir when set, and printed when the plugin failed with the text output.
+func Report(w io.Writer, command string, results []runner.Result) error {
+	if len(GlobalOptions.LogDir) > 0 {
+		if err := writeLogs(command, results); err != nil {
+			return err
+		}
+	}
+	report := runner.Report{Command: command, DryRun: GlobalOptions.DryRun, Results: results}
+	switch GlobalOptions.Output {
+	case JSONOutput:
+		if err := report.WriteJSON(w); err != nil {
+			return err
+		}
+	case JUnitOutput:
+		if err := report.WriteJUnit(w); err != nil {
+			return err
+		}
+	default:
+		for _, r := range results {
+			if r.Success() {
+				logrus.Infof("%s: %s succeeded in %s", command, r.Name, r.Duration.Round(time.Millisecond))
+			} else if len(r.Log) > 0 {
+				logrus.Errorf("%s: output of %s:\n%s", command, r.Name, r.Log)
+			}
+		}
+	}
+	if failed := report.Failed(); len(failed) > 0 {
+		return fmt.Errorf("%s failed for the plugins: %s", command, strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func writeLogs(command string, results []runner.Result) error {
+	if err := os.MkdirAll(GlobalOptions.LogDir, 0o755); err != nil {
+		return fmt.Errorf("unable to create the folder %s: %w", GlobalOptions.LogDir, err)
+	}
+	for _, r := range results {
+		logPath := filepath.Join(GlobalOptions.LogDir, fmt.Sprintf("%s-%s.log", command, filepath.Base(r.Name)))
+		if err := os.WriteFile(logPath, []byte(r.Log), 0o644); err != nil {
+			return fmt.Errorf("unable to write the log %s: %w", logPath, err)
+		}
+	}
+	return nil
+}
+
+// Exec runs the command in the directory and writes its output to log, or only logs it with --dry-run.
+// The command is stopped when the context is done.
+func Exec(ctx context.Context, log io.Writer, dir string, name string, args ...string) error {
+	if GlobalOptions.DryRun {
+		logrus.Infof("[dry-run] %s: %s %s", dirOrRoot(dir), name, strings.Join(args, " "))
+		return nil
+	}
+	return runner.Command(ctx, log, dir, name, args...)
+}
+
+// RunCommand runs the command in the directory, or only logs it with --dry-run. The output of the command is part
+// of the error returned when it fails.
+func RunCommand(dir string, name string, args ...string) error {
+	var output bytes.Buffer
+	if err := Exec(ctx, &output, dir, name, args...); err != nil {
+		return fmt.Errorf("%w\n%s", err, output.String())
+	}
+	return nil
+}
+
+func dirOrRoot(dir string) string {
+	if len(dir) == 0 {
+		return "."
+	}
+	return dir
+}
@@ -0,0 +1,66 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/perses/plugins/scripts/plugins/cmd"
+	"github.com/spf13/cobra"
+)
+
+// taskTimeout is the default time given to lint a plugin.
+const taskTimeout = 3 * time.Minute
+
+type option struct {
+	workspaces []string
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("no args are supported by the command 'lint'")
+	}
+	workspaces, err := cmd.GlobalOptions.Workspaces()
+	if err != nil {
+		return err
+	}
+	o.workspaces = workspaces
+	return nil
+}
+
+func (o *option) Validate() error {
+	return nil
+}
+
+func (o *option) Execute() error {
+	results := cmd.ForEachPlugin(taskTimeout, o.workspaces, func(ctx context.Context, workspace string, log io.Writer) error {
+		return cmd.Exec(ctx, log, "", "percli", "plugin", "lint", fmt.Sprintf("--plugin.path=%s", workspace))
+	})
+	return cmd.Report(os.Stdout, "lint", results)
+}
+
+func NewCMD() *cobra.Command {
+	o := &option{}
+	return &cobra.Command{
+		Use:   "lint",
+		Short: "Lint the plugins with percli",
+		RunE: func(c *cobra.Command, args []string) error {
+			return cmd.Run(o, c, args)
+		},
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package publish
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/perses/plugins/scripts/manifest"
+	"github.com/perses/plugins/scripts/plugins/cmd"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type archiveOption struct {
+	tagOption
+}
+
+func (o *archiveOption) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("no args are supported by the command 'publish archive'")
+	}
+	return o.complete()
+}
+
+func (o *archiveOption) Validate() error {
+	return nil
+}
+
+// isUploaded tells whether the GitHub release already has the archive.
+func (o *archiveOption) isUploaded(archiveName string) bool {
+	release := o.tag.String()
+	output, execErr := exec.Command("gh", "release", "view", release, "--json", "assets").CombinedOutput()
+	if execErr != nil {
+		logrus.Infof("release %s not found or gh command failed, proceeding with upload attempt. Error: %v", release, execErr)
+		return false
+	}
+	var releaseInfo struct {
+		Assets []struct {
+			Name string `json:"name"`
+		} `json:"assets"`
+	}
+	if jsonErr := json.Unmarshal(output, &releaseInfo); jsonErr != nil {
+		logrus.WithError(jsonErr).Warnf("failed to parse gh release view output for tag %s, proceeding with upload attempt", release)
+		return false
+	}
+	for _, asset := range releaseInfo.Assets {
+		if asset.Name == archiveName {
+			return true
+		}
+	}
+	logrus.Infof("release %s found, but archive %s is missing. Proceeding with upload.", release, archiveName)
+	return false
+}
+
+func (o *archiveOption) Execute() error {
+	// The manifest is hopefully uploaded by a previous task in the CI
+	// It should be available in the plugin folder
+	manif, err := manifest.Read(o.tag.Plugin)
+	if err != nil {
+		return err
+	}
+	archiveName := fmt.Sprintf("%s-%s.tar.gz", manif.Name, o.tag.Version)
+	if o.isUploaded(archiveName) {
+		logrus.Warnf("archive %s already exists in release %s, skipping upload", archiveName, o.tag)
+		return nil
+	}
+	if execErr := cmd.RunCommand("", "gh", "release", "upload", o.tag.String(), filepath.Join(o.tag.Plugin, archiveName)); execErr != nil {
+		return fmt.Errorf("unable to upload the archive %s: %w", archiveName, execErr)
+	}
+	return nil
+}
+
+func newArchiveCMD() *cobra.Command {
+	o := &archiveOption{}
+	c := &cobra.Command{
+		Use:   "archive",
+		Short: "Upload the archive of the plugin to its GitHub release",
+		RunE: func(c *cobra.Command, args []string) error {
+			return cmd.Run(o, c, args)
+		},
+	}
+	o.addFlag(c)
+	return c
+}
@@ -0,0 +1,125 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package publish
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os/exec"
+	"time"
+
+	"github.com/perses/plugins/scripts/graph"
+	"github.com/perses/plugins/scripts/plugins/cmd"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const modulePrefix = "github.com/perses/plugins"
+
+type cueOption struct {
+	tagOption
+	token string
+}
+
+func (o *cueOption) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("no args are supported by the command 'publish cue'")
+	}
+	return o.complete()
+}
+
+func (o *cueOption) Validate() error {
+	if len(o.token) == 0 {
+		return errors.New("--token is required")
+	}
+	return nil
+}
+
+func (o *cueOption) Execute() error {
+	pluginName, version := o.tag.Plugin, "v"+o.tag.Version.String()
+	module := fmt.Sprintf("%s/%s@%s", modulePrefix, pluginName, version)
+	logrus.Infof("Module to be released: %s", module)
+
+	// The CUE modules this one imports must be published first. Their releases are created before this one (see the release command),
+	// so their tag must exist; their publication may still be in progress in another job, which is handled by the retry on `cue mod tidy`.
+	if err := exec.Command("git", "fetch", "--tags").Run(); err != nil {
+		return fmt.Errorf("unable to fetch the tags: %w", err)
+	}
+	g, err := graph.Build(".", []string{pluginName})
+	if err != nil {
+		return fmt.Errorf("unable to read the dependencies of the module: %w", err)
+	}
+	for _, dep := range g.Unpublished(".", pluginName, nil) {
+		if dep.Kind == graph.CUE {
+			return fmt.Errorf("the module depends on %s/%s@%s which is not released yet", modulePrefix, dep.Module, dep.Version)
+		}
+	}
+
+	logrus.Info("Logging into the CUE Central Registry...") // still required to push new modules
+	if err := cmd.RunCommand(pluginName, "cue", "login", "--token="+o.token); err != nil {
+		return fmt.Errorf("unable to log into the CUE Central Registry: %w", err)
+	}
+
+	logrus.Info("Ensuring the module is tidy...")
+	// `cue mod tidy` fails as long as a dependency is not available in the registry.
+	for attempt := 1; ; attempt++ {
+		err := cmd.RunCommand(pluginName, "cue", "mod", "tidy")
+		if err == nil {
+			break
+		}
+		if attempt == 5 {
+			return fmt.Errorf("unable to ensure the module is tidy: %w", err)
+		}
+		logrus.WithError(err).Warnf("Attempt %d/5: Error ensuring the module is tidy, a dependency may not be published yet, retrying...", attempt)
+		time.Sleep(time.Duration(attempt) * 30 * time.Second)
+	}
+
+	logrus.Info("Publishing module...")
+	// When we are releasing multiple modules in a short time span, the CUE Central Registry may block us because they consider we are spamming them.
+	// To mitigate this, we implement a retry mechanism.
+	retryMaxAttempts := 10
+	// Initial sleep duration between retries: random value between 1s and 10s
+	sleepBetweenRetries := (1 + time.Duration(rand.Int64N(9))) * time.Second
+	for attempt := 1; attempt <= retryMaxAttempts; attempt++ {
+		// Wait for a few seconds immediately before the first attempt to reduce collision risk with other jobs running in parallel.
+		time.Sleep(sleepBetweenRetries)
+		err := cmd.RunCommand(pluginName, "cue", "mod", "publish", version)
+		if err == nil {
+			break
+		}
+		if attempt == retryMaxAttempts {
+			return errors.New("max retry attempts reached, publish process failed")
+		}
+		logrus.WithError(err).Warnf("Attempt %d/%d: Error publishing the module, retrying...", attempt, retryMaxAttempts)
+		// Increase the sleep duration for the next attempt with a random value to reduce collision risk with other jobs running in parallel.
+		sleepBetweenRetries = sleepBetweenRetries + (1+time.Duration(rand.Int64N(19)))*time.Second
+	}
+	logrus.Infof("CUE module %s published successfully", module)
+	return nil
+}
+
+func newCUECMD() *cobra.Command {
+	o := &cueOption{}
+	c := &cobra.Command{
+		Use:   "cue",
+		Short: "Publish the CUE module of the plugin to the CUE Central Registry",
+		RunE: func(c *cobra.Command, args []string) error {
+			return cmd.Run(o, c, args)
+		},
+	}
+	o.addFlag(c)
+	c.Flags().StringVar(&o.token, "token", "", "Authentication token for CUE Central Registry login")
+	return c
+}
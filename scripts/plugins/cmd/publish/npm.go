@@ -0,0 +1,67 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package publish
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/perses/plugins/scripts/manifest"
+	"github.com/perses/plugins/scripts/plugins/cmd"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type npmOption struct {
+	tagOption
+}
+
+func (o *npmOption) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("no args are supported by the command 'publish npm'")
+	}
+	return o.complete()
+}
+
+func (o *npmOption) Validate() error {
+	return nil
+}
+
+func (o *npmOption) Execute() error {
+	// The manifest is hopefully uploaded by a previous task in the CI
+	// It should be available in the plugin folder
+	manif, err := manifest.Read(o.tag.Plugin)
+	if err != nil {
+		return err
+	}
+	pluginName := manif.Metadata.BuildInfo.Name
+	if err := cmd.RunCommand(filepath.Join(o.tag.Plugin, "dist"), "npm", "publish", "--access", "public"); err != nil {
+		return fmt.Errorf("unable to publish the plugin %s to npm: %w", pluginName, err)
+	}
+	logrus.Infof("Plugin %s@%s published to npm", pluginName, o.tag.Version)
+	return nil
+}
+
+func newNPMCMD() *cobra.Command {
+	o := &npmOption{}
+	c := &cobra.Command{
+		Use:   "npm",
+		Short: "Publish the npm package of the plugin",
+		RunE: func(c *cobra.Command, args []string) error {
+			return cmd.Run(o, c, args)
+		},
+	}
+	o.addFlag(c)
+	return c
+}
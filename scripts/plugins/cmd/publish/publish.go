@@ -0,0 +1,59 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package publish
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/perses/plugins/scripts/gomodule"
+	"github.com/perses/plugins/scripts/tag"
+	"github.com/spf13/cobra"
+)
+
+// tagOption is the release tag shared by the publish subcommands.
+type tagOption struct {
+	rawTag string
+	tag    tag.Tag
+}
+
+func (o *tagOption) complete() error {
+	if len(o.rawTag) == 0 {
+		return errors.New("--tag is required")
+	}
+	t, err := tag.Parse(o.rawTag)
+	if err != nil {
+		return err
+	}
+	if gomodule.IsModule(t.Plugin) {
+		return fmt.Errorf("%s is a Go module, it is published by its git tag only", t.Plugin)
+	}
+	o.tag = t
+	return nil
+}
+
+func (o *tagOption) addFlag(c *cobra.Command) {
+	c.Flags().StringVar(&o.rawTag, "tag", "", "the release tag of the plugin to publish, e.g. prometheus/v0.58.0")
+}
+
+func NewCMD() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "publish",
+		Short: "Publish a released plugin: its CUE module, its npm package or its archive",
+	}
+	c.AddCommand(newCUECMD())
+	c.AddCommand(newNPMCMD())
+	c.AddCommand(newArchiveCMD())
+	return c
+}
@@ -0,0 +1,150 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/perses/perses/scripts/pkg/changelog"
+	"github.com/sirupsen/logrus"
+)
+
+// commitSeparator starts each commit in the output of git log, it cannot appear in a commit subject or a file name.
+const commitSeparator = "\x1e"
+
+// conventionalScopeRegexp matches the scope of a conventional commit subject, e.g. "fix(prometheus,tempo)!: ...".
+var conventionalScopeRegexp = regexp.MustCompile(`^(?:\[\w+]\s*)?\w+\(([^)]+)\)!?:`)
+
+type commit struct {
+	// entry is the commit as expected by the changelog package: "<hash> <subject>"
+	entry string
+	files []string
+}
+
+// scopes returns the scopes of the commit when its subject follows the conventional commit format.
+func (c commit) scopes() []string {
+	_, subject, _ := strings.Cut(c.entry, " ")
+	match := conventionalScopeRegexp.FindStringSubmatch(subject)
+	if match == nil {
+		return nil
+	}
+	var result []string
+	for _, scope := range strings.Split(match[1], ",") {
+		result = append(result, strings.ToLower(strings.TrimSpace(scope)))
+	}
+	return result
+}
+
+// belongsTo tells whether the commit must appear in the changelog of the plugin.
+// A conventional commit scope naming one or more plugins overrides the files changed: the commit goes only to the
+// changelogs of these plugins. Otherwise, the commit goes to the changelog of every plugin it changed a file of.
+func (c commit) belongsTo(pluginName string, plugins []string) bool {
+	var pluginScopes []string
+	for _, scope := range c.scopes() {
+		if slices.Contains(plugins, scope) {
+			pluginScopes = append(pluginScopes, scope)
+		}
+	}
+	if len(pluginScopes) > 0 {
+		return slices.Contains(pluginScopes, pluginName)
+	}
+	for _, file := range c.files {
+		if strings.HasPrefix(file, pluginName+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func getPreviousTag(dir string, pluginName string) (string, error) {
+	pluginName = strings.ToLower(pluginName)
+	cmd := exec.Command("git", "describe", "--tags", "--abbrev=0", "--match", fmt.Sprintf("%s/v*", pluginName))
+	cmd.Dir = dir
+	data, err := cmd.Output()
+	if err != nil {
+		if exitError, ok := err.(*exec.ExitError); ok {
+			if exitError.ExitCode() == 128 {
+				return "", nil
+			}
+		}
+		return "", err
+	}
+	return string(bytes.ReplaceAll(data, []byte("\n"), []byte(""))), nil
+}
+
+// getCommits returns the commits made since the given tag, with the files they changed.
+// The files of a merge commit are the ones it changed compared to its first parent, as git log lists none by default.
+func getCommits(dir string, previousTag string) ([]commit, error) {
+	cmd := exec.Command("git", "log", fmt.Sprintf("%s..HEAD", previousTag), "--name-only", "--diff-merges=first-parent", "--no-decorate", fmt.Sprintf("--pretty=format:%s%%H %%s", commitSeparator))
+	cmd.Dir = dir
+	data, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the git logs since %s: %w", previousTag, err)
+	}
+	var commits []commit
+	for _, block := range strings.Split(string(data), commitSeparator) {
+		lines := strings.Split(strings.TrimSpace(block), "\n")
+		if len(lines[0]) == 0 {
+			continue
+		}
+		c := commit{entry: lines[0]}
+		for _, file := range lines[1:] {
+			if file = strings.TrimSpace(file); len(file) > 0 {
+				c.files = append(c.files, file)
+			}
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
+
+// selectEntries returns the changelog entries of the plugin since its previous tag.
+// hasPreviousTag is false when the plugin has never been released.
+func selectEntries(dir string, pluginName string, plugins []string) (entries []string, hasPreviousTag bool, err error) {
+	previousTag, err := getPreviousTag(dir, pluginName)
+	if err != nil {
+		return nil, false, err
+	}
+	if previousTag == "" {
+		return nil, false, nil
+	}
+	logrus.Infof("previous tag for plugin %s is %s", pluginName, previousTag)
+	commits, err := getCommits(dir, previousTag)
+	if err != nil {
+		return nil, true, err
+	}
+	for _, c := range commits {
+		if c.belongsTo(pluginName, plugins) {
+			entries = append(entries, c.entry)
+		}
+	}
+	return entries, true, nil
+}
+
+func generateChangelog(dir string, pluginName string, plugins []string) (string, error) {
+	entries, hasPreviousTag, err := selectEntries(dir, pluginName, plugins)
+	if err != nil {
+		return "", fmt.Errorf("unable to select the commits of the plugin %s: %w", pluginName, err)
+	}
+	if !hasPreviousTag {
+		logrus.Infof("no previous tag found for plugin %s, skipping changelog generation", pluginName)
+		return "First release", nil
+	}
+	return changelog.New(entries).GenerateChangelog(), nil
+}
@@ -0,0 +1,168 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testPlugins = []string{"prometheus", "table", "timeseriestable", "logstable"}
+
+type testRepository struct {
+	t   *testing.T
+	dir string
+}
+
+func newTestRepository(t *testing.T) *testRepository {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	r := &testRepository{t: t, dir: t.TempDir()}
+	r.git("init", "--quiet")
+	return r
+}
+
+func (r *testRepository) git(args ...string) {
+	r.t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@perses.dev", "-c", "commit.gpgsign=false", "-c", "tag.gpgsign=false"}, args...)...)
+	cmd.Dir = r.dir
+	output, err := cmd.CombinedOutput()
+	require.NoError(r.t, err, string(output))
+}
+
+// commit changes the files (their content is the message) and commits them.
+func (r *testRepository) commit(message string, files ...string) {
+	r.t.Helper()
+	for _, file := range files {
+		p := filepath.Join(r.dir, file)
+		require.NoError(r.t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(r.t, os.WriteFile(p, []byte(message), 0o600))
+	}
+	r.git("add", "-A")
+	r.git("commit", "--quiet", "--allow-empty", "-m", message)
+}
+
+// subjects removes the commit hash of the entries.
+func subjects(entries []string) []string {
+	var result []string
+	for _, entry := range entries {
+		_, subject, _ := strings.Cut(entry, " ")
+		result = append(result, subject)
+	}
+	return result
+}
+
+func TestSelectEntries(t *testing.T) {
+	r := newTestRepository(t)
+	r.commit("init", "prometheus/package.json", "table/package.json", "timeseriestable/package.json", "logstable/package.json")
+	r.git("tag", "prometheus/v0.1.0")
+	r.git("tag", "table/v0.1.0")
+	r.git("tag", "timeseriestable/v0.1.0")
+
+	r.commit("[FEATURE] add the flavor of the datasource", "prometheus/src/datasource.ts")
+	r.commit("[BUGFIX] fix the table cell alignment", "table/src/cell.tsx")
+	r.commit("[ENHANCEMENT] share the legend across plugins", "prometheus/src/legend.ts", "timeseriestable/src/legend.ts")
+	r.commit("[DOC] fix(table): document the columns", "docs/table.md", "timeseriestable/README.md")
+	r.commit("[IGNORE] update the CI", ".github/workflows/ci.yml")
+
+	testSuites := []struct {
+		plugin   string
+		expected []string
+		released bool
+	}{
+		{
+			plugin: "prometheus",
+			expected: []string{
+				"[ENHANCEMENT] share the legend across plugins",
+				"[FEATURE] add the flavor of the datasource",
+			},
+			released: true,
+		},
+		{
+			plugin: "table",
+			expected: []string{
+				"[DOC] fix(table): document the columns",
+				"[BUGFIX] fix the table cell alignment",
+			},
+			released: true,
+		},
+		{
+			plugin: "timeseriestable",
+			expected: []string{
+				"[ENHANCEMENT] share the legend across plugins",
+			},
+			released: true,
+		},
+		{
+			plugin:   "logstable",
+			released: false,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.plugin, func(t *testing.T) {
+			entries, released, err := selectEntries(r.dir, test.plugin, testPlugins)
+			require.NoError(t, err)
+			assert.Equal(t, test.released, released)
+			assert.Equal(t, test.expected, subjects(entries))
+		})
+	}
+}
+
+func TestGetCommits_MergeCommit(t *testing.T) {
+	r := newTestRepository(t)
+	r.commit("init", "prometheus/package.json", "table/package.json")
+	r.git("tag", "prometheus/v0.1.0")
+	r.git("checkout", "--quiet", "-b", "feature")
+	r.commit("[FEATURE] add the flavor of the datasource", "prometheus/src/datasource.ts")
+	r.git("checkout", "--quiet", "-")
+	r.commit("[BUGFIX] fix the table cell alignment", "table/src/cell.tsx")
+	r.git("merge", "--quiet", "--no-ff", "-m", "Merge branch 'feature'", "feature")
+
+	commits, err := getCommits(r.dir, "prometheus/v0.1.0")
+	require.NoError(t, err)
+	files := make(map[string][]string)
+	for _, c := range commits {
+		files[subjects([]string{c.entry})[0]] = c.files
+	}
+	assert.Equal(t, map[string][]string{
+		"Merge branch 'feature'":                     {"prometheus/src/datasource.ts"},
+		"[BUGFIX] fix the table cell alignment":      {"table/src/cell.tsx"},
+		"[FEATURE] add the flavor of the datasource": {"prometheus/src/datasource.ts"},
+	}, files)
+}
+
+func TestCommitScopes(t *testing.T) {
+	testSuites := []struct {
+		entry    string
+		expected []string
+	}{
+		{entry: "abc feat(prometheus): add the flavor", expected: []string{"prometheus"}},
+		{entry: "abc fix(Table, LogsTable)!: fix the columns", expected: []string{"table", "logstable"}},
+		{entry: "abc [BUGFIX] fix(table): fix the columns", expected: []string{"table"}},
+		{entry: "abc [BUGFIX] fix the table columns", expected: nil},
+		{entry: "abc chore: update the CI", expected: nil},
+	}
+	for _, test := range testSuites {
+		t.Run(test.entry, func(t *testing.T) {
+			assert.Equal(t, test.expected, commit{entry: test.entry}.scopes())
+		})
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"time"
+
+	"github.com/perses/plugins/scripts/gomodule"
+	"github.com/perses/plugins/scripts/graph"
+	"github.com/perses/plugins/scripts/npm"
+	"github.com/perses/plugins/scripts/plugins/cmd"
+	"github.com/perses/plugins/scripts/runner"
+	"github.com/perses/plugins/scripts/tag"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// taskTimeout is the default time given to release a plugin.
+const taskTimeout = 2 * time.Minute
+
+type option struct {
+	all bool
+	// allPlugins are all the plugins and Go modules of the repository, used to know which commits belong to which one
+	allPlugins []string
+	// plugins are the plugins and Go modules to release, in the order of their dependencies
+	plugins []string
+	graph   graph.Graph
+	// released are the tags released during this run
+	released map[string]bool
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("no args are supported by the command 'release'")
+	}
+	// get all tags locally
+	if err := exec.Command("git", "fetch", "--tags").Run(); err != nil {
+		return fmt.Errorf("unable to fetch the tags: %w", err)
+	}
+	workspaces, err := cmd.GlobalOptions.Workspaces()
+	if err != nil {
+		return err
+	}
+	o.allPlugins, err = npm.GetWorkspaces(".")
+	if err != nil {
+		return err
+	}
+	o.allPlugins = append(o.allPlugins, gomodule.Modules...)
+	o.graph, err = graph.Build(".", o.allPlugins)
+	if err != nil {
+		return fmt.Errorf("unable to build the dependency graph of the plugins: %w", err)
+	}
+	// The Go modules that are not plugins (e.g. sdk) are released along with the plugins requiring them.
+	var modules []string
+	for _, module := range gomodule.Modules {
+		if o.all || slices.ContainsFunc(workspaces, func(w string) bool {
+			return slices.Contains(o.graph.DependenciesOf(w, graph.Go), module)
+		}) {
+			modules = append(modules, module)
+		}
+	}
+	// The plugins are released after the plugins they depend on, so the dependencies are published first.
+	o.plugins, err = o.graph.Sort(append(modules, workspaces...))
+	if err != nil {
+		return fmt.Errorf("unable to order the plugins to release: %w", err)
+	}
+	o.released = make(map[string]bool)
+	return nil
+}
+
+func (o *option) Validate() error {
+	if !o.all && len(cmd.GlobalOptions.Plugins) == 0 && len(cmd.GlobalOptions.Affected) == 0 {
+		return errors.New("select the plugins to release with --plugins or --affected, or use --all")
+	}
+	return nil
+}
+
+// release creates the GitHub release of the plugin, or the git tag of a Go module that is not a plugin.
+// It is refused when a dependency of the plugin is not released yet.
+func (o *option) release(ctx context.Context, log io.Writer, pluginName string) error {
+	getVersion := npm.GetVersion
+	if gomodule.IsModule(pluginName) {
+		getVersion = gomodule.GetVersion
+	}
+	version, err := getVersion(pluginName)
+	if err != nil {
+		return err
+	}
+	// To be compliant with Golang, the tag must be in the format `folder/vX.Y.Z`
+	releaseName := tag.Tag{Plugin: pluginName, Version: version}.String()
+	// ensure the tag does not already exist
+	if graph.IsTagged(".", releaseName) {
+		logrus.Infof("release %s already exists", releaseName)
+		return nil
+	}
+
+	unpublished := o.graph.Unpublished(".", pluginName, func(dep graph.Dependency) bool {
+		return o.released[dep.Tag()]
+	})
+	if len(unpublished) > 0 {
+		var errs []error
+		for _, dep := range unpublished {
+			errs = append(errs, fmt.Errorf("the plugin requires the %s module %s which is not released yet", dep.Kind, dep.Tag()))
+		}
+		return errors.Join(errs...)
+	}
+
+	changelog, err := generateChangelog(".", pluginName, o.allPlugins)
+	if err != nil {
+		return err
+	}
+	if gomodule.IsModule(pluginName) {
+		// A Go module is published by its tag only. A GitHub release would trigger the CI jobs publishing a plugin.
+		if execErr := cmd.Exec(ctx, log, "", "git", "tag", "-a", releaseName, "-m", changelog); execErr != nil {
+			return fmt.Errorf("unable to create the tag %s: %w", releaseName, execErr)
+		}
+		if execErr := cmd.Exec(ctx, log, "", "git", "push", "origin", releaseName); execErr != nil {
+			return fmt.Errorf("unable to push the tag %s: %w", releaseName, execErr)
+		}
+		o.released[releaseName] = true
+		return nil
+	}
+	// create the GitHub release
+	if execErr := cmd.Exec(ctx, log, "", "gh", "release", "create", releaseName, "-t", releaseName, "-n", changelog); execErr != nil {
+		return fmt.Errorf("unable to create the release %s: %w", releaseName, execErr)
+	}
+	o.released[releaseName] = true
+	return nil
+}
+
+func (o *option) Execute() error {
+	tasks := make([]runner.Task, 0, len(o.plugins))
+	for _, plugin := range o.plugins {
+		tasks = append(tasks, runner.Task{
+			Name: plugin,
+			Run: func(ctx context.Context, log io.Writer) error {
+				logrus.Infof("releasing %s", plugin)
+				return o.release(ctx, log, plugin)
+			},
+		})
+	}
+	// The releases are created one after the other, in the order of the dependencies.
+	opts := cmd.GlobalOptions.RunnerOptions(taskTimeout)
+	opts.Concurrency = 1
+	return cmd.Report(os.Stdout, "release", runner.Run(cmd.Context(), opts, tasks))
+}
+
+func NewCMD() *cobra.Command {
+	o := &option{}
+	c := &cobra.Command{
+		Use:   "release",
+		Short: "Create the GitHub release of the plugins not released yet",
+		Long: `Create the GitHub release of the plugins whose version (in package.json) is not released yet.
+
+The plugins are released in the order of their dependencies (cue.mod/module.cue and go.mod).
+A plugin requiring a version of another module that is not released yet (no tag) is not released.
+The Go modules that are not plugins (sdk) are released first, with the version of their VERSION file, when a plugin
+to release requires them. They are released with an annotated git tag pushed to origin, not with a GitHub release.
+
+Prerequisites:
+- Install the GitHub CLI (gh): https://github.com/cli/cli#installation
+- Use it to log in to GitHub: ` + "`gh auth login`" + `
+
+NB: this command doesn't handle the plugin archive creation, a CI task achieves this.`,
+		Example: `
+# Release every plugin not yet released
+$ plugins release --all
+
+# Release only the tempo plugin (the folder name, not the plugin name)
+$ plugins release --plugins=tempo`,
+		RunE: func(c *cobra.Command, args []string) error {
+			return cmd.Run(o, c, args)
+		},
+	}
+	c.Flags().BoolVar(&o.all, "all", false, "release all the plugins")
+	return c
+}
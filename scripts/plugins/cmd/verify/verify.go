@@ -0,0 +1,106 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	pluginArchive "github.com/perses/plugins/scripts/archive"
+	"github.com/perses/plugins/scripts/plugins/cmd"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type option struct {
+	dir      string
+	archives []string
+	sums     map[string]string
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("no args are supported by the command 'verify'")
+	}
+	archives, err := filepath.Glob(filepath.Join(o.dir, "*.tar.gz"))
+	if err != nil {
+		return fmt.Errorf("unable to list the archives of %s: %w", o.dir, err)
+	}
+	slices.Sort(archives)
+	o.archives = archives
+
+	checksumsPath := filepath.Join(o.dir, pluginArchive.ChecksumsFile)
+	if _, statErr := os.Stat(checksumsPath); statErr != nil {
+		logrus.Warnf("no %s found in %s, checksums are not verified", pluginArchive.ChecksumsFile, o.dir)
+		return nil
+	}
+	o.sums, err = pluginArchive.ReadChecksums(checksumsPath)
+	return err
+}
+
+func (o *option) Validate() error {
+	if len(o.archives) == 0 {
+		return fmt.Errorf("no archive found in %s", o.dir)
+	}
+	return nil
+}
+
+// verifyChecksum checks the archive against the SHA256SUMS file of its folder, when there is one.
+func (o *option) verifyChecksum(archivePath string) error {
+	if o.sums == nil {
+		return nil
+	}
+	expected, ok := o.sums[filepath.Base(archivePath)]
+	if !ok {
+		return errors.New("archive not listed in " + pluginArchive.ChecksumsFile)
+	}
+	sum, err := pluginArchive.Checksum(archivePath)
+	if err != nil {
+		return err
+	}
+	if sum != expected {
+		return errors.New("checksum doesn't match " + pluginArchive.ChecksumsFile)
+	}
+	return nil
+}
+
+func (o *option) Execute() error {
+	results := cmd.ForEachPlugin(0, o.archives, func(_ context.Context, archivePath string, _ io.Writer) error {
+		return errors.Join(o.verifyChecksum(archivePath), pluginArchive.Verify(archivePath))
+	})
+	return cmd.Report(os.Stdout, "verify", results)
+}
+
+func NewCMD() *cobra.Command {
+	o := &option{}
+	c := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify the plugin archives of a local folder, without network access",
+		Long: `Verify the plugin archives (*.tar.gz) of a local folder, by checking:
+- the archive contains all the files of a plugin (dist/, schemas/, cue.mod/, package.json, README.md, LICENSE)
+- dist/mf-manifest.json is valid and its id and buildVersion match the archive name and package.json
+- every CUE schema declares a kind listed in package.json
+- the archive matches the SHA256SUMS of the folder, when there is one`,
+		RunE: func(c *cobra.Command, args []string) error {
+			return cmd.Run(o, c, args)
+		},
+	}
+	c.Flags().StringVar(&o.dir, "dir", "plugins-archive", "folder containing the archives to verify")
+	return c
+}
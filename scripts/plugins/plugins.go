@@ -0,0 +1,72 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// plugins is the CLI gathering the commands used to develop, check and release the plugins of this repository.
+//
+// Usage:
+//
+//	go run ./scripts/plugins --help
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/perses/plugins/scripts/plugins/cmd"
+	"github.com/perses/plugins/scripts/plugins/cmd/archive"
+	"github.com/perses/plugins/scripts/plugins/cmd/build"
+	"github.com/perses/plugins/scripts/plugins/cmd/bump"
+	"github.com/perses/plugins/scripts/plugins/cmd/lint"
+	"github.com/perses/plugins/scripts/plugins/cmd/publish"
+	"github.com/perses/plugins/scripts/plugins/cmd/release"
+	"github.com/perses/plugins/scripts/plugins/cmd/testschemas"
+	"github.com/perses/plugins/scripts/plugins/cmd/tidy"
+	"github.com/perses/plugins/scripts/plugins/cmd/verify"
+	"github.com/spf13/cobra"
+)
+
+func newRootCMD() *cobra.Command {
+	c := &cobra.Command{
+		Use:          "plugins",
+		Short:        "Develop, check and release the plugins of this repository",
+		SilenceUsage: true,
+	}
+	cmd.GlobalOptions.AddFlags(c)
+	c.AddCommand(build.NewCMD())
+	c.AddCommand(lint.NewCMD())
+	c.AddCommand(testschemas.NewCMD())
+	c.AddCommand(archive.NewCMD())
+	c.AddCommand(verify.NewCMD())
+	c.AddCommand(release.NewCMD())
+	c.AddCommand(bump.NewCMD())
+	c.AddCommand(tidy.NewCMD())
+	c.AddCommand(publish.NewCMD())
+	return c
+}
+
+func main() {
+	// On SIGINT, the context of the command is cancelled: the commands it runs are interrupted and the plugins not
+	// processed yet are skipped. A second SIGINT stops the CLI immediately.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	err := newRootCMD().ExecuteContext(ctx)
+	stop()
+	if err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// GracePeriod is the time given to a command to stop after being interrupted, before it is killed.
+var GracePeriod = 10 * time.Second
+
+// Command runs the command in the directory and writes its output to log. When the context is done, the command and
+// the processes it started are stopped: interrupted (SIGINT) when the context is cancelled, so they can clean up, and
+// killed when its deadline is exceeded.
+func Command(ctx context.Context, log io.Writer, dir string, name string, args ...string) error {
+	c := exec.CommandContext(ctx, name, args...)
+	c.Dir = dir
+	c.Stdout = log
+	c.Stderr = log
+	c.WaitDelay = GracePeriod
+	stopProcessGroup(ctx, c)
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("%s %s failed: %w", name, strings.Join(args, " "), err)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"syscall"
+)
+
+// stopProcessGroup starts the command in its own process group, so that it's possible to stop the command along with
+// its children (percli starts npm, which starts node, ...).
+func stopProcessGroup(ctx context.Context, c *exec.Cmd) {
+	c.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	c.Cancel = func() error {
+		sig := syscall.SIGINT
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			sig = syscall.SIGKILL
+		}
+		return syscall.Kill(-c.Process.Pid, sig)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Report is the final report of a run, written in JSON or JUnit XML so the CI can display it.
+type Report struct {
+	Command string
+	DryRun  bool
+	Results []Result
+}
+
+// Failed returns the names of the tasks that didn't succeed.
+func (r Report) Failed() []string {
+	var failed []string
+	for _, result := range r.Results {
+		if !result.Success() {
+			failed = append(failed, result.Name)
+		}
+	}
+	return failed
+}
+
+type jsonResult struct {
+	Plugin   string `json:"plugin"`
+	Status   Status `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+	Log      string `json:"log,omitempty"`
+}
+
+func (r Report) WriteJSON(w io.Writer) error {
+	results := make([]jsonResult, 0, len(r.Results))
+	for _, result := range r.Results {
+		results = append(results, jsonResult{
+			Plugin:   result.Name,
+			Status:   result.Status,
+			Error:    result.Error,
+			Duration: result.Duration.Round(time.Millisecond).String(),
+			Log:      result.Log,
+		})
+	}
+	data, err := json.MarshalIndent(struct {
+		Command string       `json:"command"`
+		DryRun  bool         `json:"dryRun"`
+		Results []jsonResult `json:"results"`
+	}{Command: r.Command, DryRun: r.DryRun, Results: results}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+func junitTime(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// WriteJUnit writes the report as a JUnit XML file: a test suite for the command and a test case for each plugin.
+// A failed task is a failure, a task stopped by a timeout or a cancellation is an error.
+func (r Report) WriteJUnit(w io.Writer) error {
+	suite := junitTestSuite{Name: r.Command, Tests: len(r.Results)}
+	var total time.Duration
+	for _, result := range r.Results {
+		total += result.Duration
+		testCase := junitTestCase{
+			Name:      result.Name,
+			ClassName: r.Command,
+			Time:      junitTime(result.Duration),
+			SystemOut: result.Log,
+		}
+		message := &junitMessage{Message: result.Error, Type: string(result.Status), Body: result.Error}
+		switch result.Status {
+		case StatusSuccess:
+		case StatusFailure:
+			suite.Failures++
+			testCase.Failure = message
+		default:
+			suite.Errors++
+			testCase.Error = message
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	suite.Time = junitTime(total)
+	data, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s%s\n", xml.Header, data)
+	return err
+}
@@ -0,0 +1,160 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package runner runs a task for each plugin with a bounded concurrency. Every task has its own timeout and all of
+// them share the deadline and the cancellation of a parent context, so an interrupted or hung task doesn't keep the
+// others, or the child processes it started, running.
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type Status string
+
+const (
+	StatusSuccess Status = "success"
+	StatusFailure Status = "failure"
+	// StatusTimeout is the status of a task stopped by its timeout or by the global deadline.
+	StatusTimeout Status = "timeout"
+	// StatusCancelled is the status of a task interrupted, or not started, because the run has been cancelled.
+	StatusCancelled Status = "cancelled"
+)
+
+// Task is the work done for a plugin.
+type Task struct {
+	Name string
+	// Run does the work. The output of the task (and of the commands it runs) is written to log.
+	Run func(ctx context.Context, log io.Writer) error
+}
+
+// Options configures a run.
+type Options struct {
+	// Concurrency is the maximum number of tasks running at the same time. By default, the number of CPUs.
+	Concurrency int
+	// Timeout is the deadline of the whole run. No deadline when zero.
+	Timeout time.Duration
+	// TaskTimeout is the deadline of each task. No deadline when zero.
+	TaskTimeout time.Duration
+}
+
+// Result is the outcome of a task.
+type Result struct {
+	Name     string
+	Status   Status
+	Error    string
+	Duration time.Duration
+	// Log is the output captured while running the task.
+	Log string
+}
+
+func (r Result) Success() bool {
+	return r.Status == StatusSuccess
+}
+
+// syncBuffer is a buffer that can be written by several goroutines, like the stdout and stderr of a command.
+type syncBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buffer.String()
+}
+
+// Run runs the tasks and returns their results in the order of the tasks. The tasks not started when the context is
+// done are not run and are reported as cancelled (or timed out when the global deadline is exceeded).
+func Run(ctx context.Context, opts Options, tasks []Task) []Result {
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+	results := make([]Result, len(tasks))
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for range min(concurrency, len(tasks)) {
+		wg.Go(func() {
+			for i := range queue {
+				results[i] = runTask(ctx, opts.TaskTimeout, tasks[i])
+			}
+		})
+	}
+	for i := range tasks {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+	return results
+}
+
+func runTask(ctx context.Context, timeout time.Duration, task Task) Result {
+	result := Result{Name: task.Name}
+	if ctx.Err() != nil {
+		result.Status = statusOf(ctx.Err())
+		result.Error = fmt.Sprintf("not started: %s", ctx.Err())
+		return result
+	}
+	taskCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		taskCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	log := &syncBuffer{}
+	start := time.Now()
+	err := task.Run(taskCtx, log)
+	result.Duration = time.Since(start)
+	result.Log = log.String()
+	if err == nil {
+		result.Status = StatusSuccess
+		return result
+	}
+	result.Status = StatusFailure
+	if taskCtx.Err() != nil {
+		// The task has likely failed because it has been stopped, that's what is worth reporting.
+		result.Status = statusOf(taskCtx.Err())
+		err = fmt.Errorf("%w: %w", taskCtx.Err(), err)
+	}
+	result.Error = err.Error()
+	logrus.WithError(err).Errorf("%s: %s", task.Name, result.Status)
+	return result
+}
+
+func statusOf(err error) Status {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return StatusTimeout
+	}
+	return StatusCancelled
+}
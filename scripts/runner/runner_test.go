@@ -0,0 +1,147 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunConcurrency(t *testing.T) {
+	var running, maxRunning atomic.Int32
+	tasks := make([]Task, 0, 8)
+	for i := range 8 {
+		tasks = append(tasks, Task{
+			Name: fmt.Sprintf("plugin-%d", i),
+			Run: func(_ context.Context, log io.Writer) error {
+				n := running.Add(1)
+				defer running.Add(-1)
+				for {
+					current := maxRunning.Load()
+					if n <= current || maxRunning.CompareAndSwap(current, n) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				fmt.Fprintf(log, "done %d", i)
+				if i == 3 {
+					return errors.New("broken")
+				}
+				return nil
+			},
+		})
+	}
+	results := Run(context.Background(), Options{Concurrency: 2}, tasks)
+	assert.LessOrEqual(t, maxRunning.Load(), int32(2))
+	require.Len(t, results, 8)
+	for i, result := range results {
+		assert.Equal(t, fmt.Sprintf("plugin-%d", i), result.Name)
+		assert.Equal(t, fmt.Sprintf("done %d", i), result.Log)
+		if i == 3 {
+			assert.Equal(t, StatusFailure, result.Status)
+			assert.Equal(t, "broken", result.Error)
+		} else {
+			assert.Equal(t, StatusSuccess, result.Status)
+		}
+	}
+}
+
+func TestRunTaskTimeoutKillsCommand(t *testing.T) {
+	tasks := []Task{
+		{Name: "hung", Run: func(ctx context.Context, log io.Writer) error {
+			// the child of the shell must be killed as well, otherwise the command waits for it
+			return Command(ctx, log, "", "sh", "-c", "echo started; sleep 30; echo finished")
+		}},
+		{Name: "fast", Run: func(ctx context.Context, log io.Writer) error {
+			return Command(ctx, log, "", "sh", "-c", "echo ok")
+		}},
+	}
+	start := time.Now()
+	results := Run(context.Background(), Options{TaskTimeout: 200 * time.Millisecond}, tasks)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, StatusTimeout, results[0].Status)
+	assert.Equal(t, "started\n", results[0].Log)
+	assert.Equal(t, StatusSuccess, results[1].Status)
+	assert.Equal(t, "ok\n", results[1].Log)
+}
+
+func TestRunCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	tasks := []Task{
+		{Name: "interrupted", Run: func(ctx context.Context, log io.Writer) error {
+			cancel()
+			return Command(ctx, log, "", "sleep", "30")
+		}},
+		{Name: "not-started", Run: func(context.Context, io.Writer) error {
+			return errors.New("must not run")
+		}},
+	}
+	results := Run(ctx, Options{Concurrency: 1}, tasks)
+	assert.Equal(t, StatusCancelled, results[0].Status)
+	assert.Equal(t, StatusCancelled, results[1].Status)
+	assert.Contains(t, results[1].Error, "not started")
+}
+
+func TestRunGlobalTimeout(t *testing.T) {
+	tasks := []Task{
+		{Name: "slow", Run: func(ctx context.Context, _ io.Writer) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+		{Name: "not-started", Run: func(context.Context, io.Writer) error {
+			return nil
+		}},
+	}
+	results := Run(context.Background(), Options{Concurrency: 1, Timeout: 50 * time.Millisecond}, tasks)
+	assert.Equal(t, StatusTimeout, results[0].Status)
+	assert.Equal(t, StatusTimeout, results[1].Status)
+}
+
+func TestReportJUnit(t *testing.T) {
+	report := Report{
+		Command: "lint",
+		Results: []Result{
+			{Name: "prometheus", Status: StatusSuccess, Duration: 1500 * time.Millisecond, Log: "linted"},
+			{Name: "tempo", Status: StatusFailure, Error: "invalid schema", Duration: time.Second},
+			{Name: "loki", Status: StatusTimeout, Error: "context deadline exceeded"},
+		},
+	}
+	assert.Equal(t, []string{"tempo", "loki"}, report.Failed())
+	var buffer bytes.Buffer
+	require.NoError(t, report.WriteJUnit(&buffer))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="lint" tests="3" failures="1" errors="1" time="2.500">
+    <testcase name="prometheus" classname="lint" time="1.500">
+      <system-out>linted</system-out>
+    </testcase>
+    <testcase name="tempo" classname="lint" time="1.000">
+      <failure message="invalid schema" type="failure">invalid schema</failure>
+    </testcase>
+    <testcase name="loki" classname="lint" time="0.000">
+      <error message="context deadline exceeded" type="timeout">context deadline exceeded</error>
+    </testcase>
+  </testsuite>
+</testsuites>
+`, buffer.String())
+}
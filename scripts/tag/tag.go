@@ -14,24 +14,133 @@
 package tag
 
 import (
-	"flag"
+	"cmp"
+	"fmt"
 	"regexp"
-
-	"github.com/sirupsen/logrus"
+	"strconv"
+	"strings"
 )
 
-var tagNamePattern = regexp.MustCompile(`(?m)(.+)/v(\d+\.\d+\.\d+(?:-[\w\d.]+)?)`)
+// versionPattern is the semantic version 2.0.0 pattern (https://semver.org), without the leading "v".
+var versionPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+// Version is a semantic version.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+	// Prerelease are the dot-separated identifiers after the "-", e.g. ["beta", "1"] for 1.0.0-beta.1
+	Prerelease []string
+	// Build is the build metadata after the "+". It is ignored when comparing versions.
+	Build string
+}
 
-func Flag() *string {
-	return flag.String("tag", "", "Name of the tag")
+// ParseVersion parses a semantic version, with or without a leading "v".
+func ParseVersion(s string) (Version, error) {
+	match := versionPattern.FindStringSubmatch(strings.TrimPrefix(s, "v"))
+	if match == nil {
+		return Version{}, fmt.Errorf("invalid semantic version %q", s)
+	}
+	v := Version{Build: match[5]}
+	var err error
+	if v.Major, err = strconv.Atoi(match[1]); err != nil {
+		return Version{}, fmt.Errorf("invalid major version in %q: %w", s, err)
+	}
+	if v.Minor, err = strconv.Atoi(match[2]); err != nil {
+		return Version{}, fmt.Errorf("invalid minor version in %q: %w", s, err)
+	}
+	if v.Patch, err = strconv.Atoi(match[3]); err != nil {
+		return Version{}, fmt.Errorf("invalid patch version in %q: %w", s, err)
+	}
+	if len(match[4]) > 0 {
+		v.Prerelease = strings.Split(match[4], ".")
+	}
+	return v, nil
 }
 
-func Parse(tag *string) (string, string) {
-	tagSplit := tagNamePattern.FindStringSubmatch(*tag)
-	if len(tagSplit) != 3 {
-		logrus.Fatalf("Invalid tag name: %s", *tag)
+// String returns the version without the leading "v".
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	if len(v.Build) > 0 {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// IsPrerelease tells whether the version is a prerelease (e.g. 1.0.0-rc.1).
+func (v Version) IsPrerelease() bool {
+	return len(v.Prerelease) > 0
+}
+
+// Compare returns -1, 0 or +1 depending on whether v is lower, equal or greater than other, following the semver precedence:
+// a prerelease is lower than the release, and the build metadata is ignored.
+func (v Version) Compare(other Version) int {
+	if c := cmp.Compare(v.Major, other.Major); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(v.Minor, other.Minor); c != 0 {
+		return c
 	}
-	pluginFolderName := tagSplit[1]
-	version := tagSplit[2]
-	return pluginFolderName, version
+	if c := cmp.Compare(v.Patch, other.Patch); c != 0 {
+		return c
+	}
+	switch {
+	case len(v.Prerelease) == 0 && len(other.Prerelease) == 0:
+		return 0
+	case len(v.Prerelease) == 0:
+		return 1
+	case len(other.Prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.Prerelease) && i < len(other.Prerelease); i++ {
+		if c := comparePrereleaseIdentifier(v.Prerelease[i], other.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(v.Prerelease), len(other.Prerelease))
+}
+
+// comparePrereleaseIdentifier compares numerically the numeric identifiers, lexically the others,
+// and a numeric identifier is always lower than an alphanumeric one.
+func comparePrereleaseIdentifier(a, b string) int {
+	aNum, aErr := strconv.ParseUint(a, 10, 64)
+	bNum, bErr := strconv.ParseUint(b, 10, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		return cmp.Compare(aNum, bNum)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// Tag is the git tag of a plugin release, in the format `<plugin folder>/v<version>` to be compliant with Go modules.
+type Tag struct {
+	Plugin  string
+	Version Version
+}
+
+// Parse parses a tag like "prometheus/v0.58.0-beta.0".
+func Parse(name string) (Tag, error) {
+	i := strings.LastIndex(name, "/v")
+	if i <= 0 {
+		return Tag{}, fmt.Errorf("invalid tag name %q, expected <plugin>/v<version>", name)
+	}
+	v, err := ParseVersion(name[i+2:])
+	if err != nil {
+		return Tag{}, fmt.Errorf("invalid tag name %q: %w", name, err)
+	}
+	return Tag{Plugin: name[:i], Version: v}, nil
+}
+
+func (t Tag) String() string {
+	return fmt.Sprintf("%s/v%s", t.Plugin, t.Version)
 }
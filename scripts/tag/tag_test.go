@@ -0,0 +1,107 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	testSuites := []struct {
+		name     string
+		expected Tag
+		err      string
+	}{
+		{
+			name:     "prometheus/v0.58.0",
+			expected: Tag{Plugin: "prometheus", Version: Version{Major: 0, Minor: 58, Patch: 0}},
+		},
+		{
+			name:     "victorialogs/v0.3.1-beta.0",
+			expected: Tag{Plugin: "victorialogs", Version: Version{Major: 0, Minor: 3, Patch: 1, Prerelease: []string{"beta", "0"}}},
+		},
+		{
+			name:     "sdk/v1.2.3-rc.1+build.42",
+			expected: Tag{Plugin: "sdk", Version: Version{Major: 1, Minor: 2, Patch: 3, Prerelease: []string{"rc", "1"}, Build: "build.42"}},
+		},
+		{
+			name: "v0.58.0",
+			err:  `invalid tag name "v0.58.0", expected <plugin>/v<version>`,
+		},
+		{
+			name: "prometheus/0.58.0",
+			err:  `invalid tag name "prometheus/0.58.0", expected <plugin>/v<version>`,
+		},
+		{
+			name: "prometheus/v0.58",
+			err:  `invalid tag name "prometheus/v0.58": invalid semantic version "0.58"`,
+		},
+		{
+			name: "prometheus/v01.0.0",
+			err:  `invalid tag name "prometheus/v01.0.0": invalid semantic version "01.0.0"`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := Parse(test.name)
+			if len(test.err) > 0 {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, result)
+			assert.Equal(t, test.name, result.String())
+		})
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	testSuites := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{a: "1.0.0", b: "1.0.0", expected: 0},
+		{a: "1.0.0", b: "2.0.0", expected: -1},
+		{a: "1.10.0", b: "1.9.0", expected: 1},
+		{a: "1.0.1", b: "1.0.0", expected: 1},
+		{a: "1.0.0-alpha", b: "1.0.0", expected: -1},
+		{a: "1.0.0-alpha", b: "1.0.0-alpha.1", expected: -1},
+		{a: "1.0.0-alpha.1", b: "1.0.0-alpha.beta", expected: -1},
+		{a: "1.0.0-beta.2", b: "1.0.0-beta.11", expected: -1},
+		{a: "1.0.0-rc.1", b: "1.0.0-beta.11", expected: 1},
+		{a: "1.0.0+build.1", b: "1.0.0+build.2", expected: 0},
+	}
+	for _, test := range testSuites {
+		t.Run(test.a+" vs "+test.b, func(t *testing.T) {
+			a, err := ParseVersion(test.a)
+			require.NoError(t, err)
+			b, err := ParseVersion(test.b)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, a.Compare(b))
+			assert.Equal(t, -test.expected, b.Compare(a))
+		})
+	}
+}
+
+func TestVersionIsPrerelease(t *testing.T) {
+	for version, expected := range map[string]bool{"0.58.0": false, "0.58.0-beta.0": true, "0.58.0+build": false} {
+		v, err := ParseVersion(version)
+		require.NoError(t, err)
+		assert.Equal(t, expected, v.IsPrerelease(), version)
+	}
+}
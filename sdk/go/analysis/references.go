@@ -0,0 +1,146 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/perses/perses/go-sdk/datasource"
+	"github.com/perses/perses/pkg/model/api/v1/common"
+)
+
+// variableReferenceRegexp matches $name, ${name} and ${name:format}.
+// A name starting with a digit is not a reference: PromQL and LogQL use $1, $2, ... as placeholders (e.g. in label_replace),
+// and a text can contain amounts like $5.
+var variableReferenceRegexp = regexp.MustCompile(`\$(?:([a-zA-Z_]\w*)|\{([a-zA-Z_]\w*)(?:[:.][^}]*)?})`)
+
+// Reference is a variable used somewhere in a dashboard.
+type Reference struct {
+	// Name is the name of the variable, without the $.
+	Name string
+	// Location is the path of the field using the variable, e.g. "panels.cpu.spec.queries[0].spec.plugin.spec.query".
+	Location string
+	// Text tells the reference comes from a free text field, see Field.
+	Text bool
+}
+
+// ExtractReferences returns the names of the variables used in the text, in order of appearance and with duplicates.
+// It works for every query language supported by the plugins (PromQL, LogQL, TraceQL, SQL, SPL, LogsQL),
+// since Perses interpolates the variables the same way in all of them.
+func ExtractReferences(text string) []string {
+	var names []string
+	for _, match := range variableReferenceRegexp.FindAllStringSubmatch(text, -1) {
+		if len(match[1]) > 0 {
+			names = append(names, match[1])
+		} else {
+			names = append(names, match[2])
+		}
+	}
+	return names
+}
+
+// Field is a field of a plugin spec that can use variables.
+type Field struct {
+	// Path is the path of the field in the plugin spec, e.g. "query" or "matchers[0]".
+	Path  string
+	Value string
+	// Text tells the field is a free text, like a Markdown text, where a $ is not always a variable (e.g. $USD).
+	// Its references mark the variables as used, but they are never reported as undefined.
+	Text bool
+}
+
+// Decoder returns the fields of a plugin spec, given in JSON, that can use variables.
+type Decoder func(spec []byte) ([]Field, error)
+
+var (
+	mutex    sync.RWMutex
+	decoders = map[string]Decoder{}
+)
+
+// Register declares the decoder of a plugin kind.
+// The plugins of this repository call it when their Go SDK package is initialized.
+func Register(pluginKind string, decoder Decoder) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	decoders[pluginKind] = decoder
+}
+
+func getDecoder(pluginKind string) Decoder {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return decoders[pluginKind]
+}
+
+// Decode returns a Decoder unmarshalling the spec in a T and listing its fields with the given function.
+func Decode[T any](fields func(spec T) []Field) Decoder {
+	return func(data []byte) ([]Field, error) {
+		var spec T
+		if err := json.Unmarshal(data, &spec); err != nil {
+			return nil, err
+		}
+		return fields(spec), nil
+	}
+}
+
+// Datasource returns the field holding the name of the datasource selector, which can be a variable (e.g. "$ds").
+func Datasource(selector *datasource.Selector) Field {
+	if selector == nil {
+		return Field{Path: "datasource.name"}
+	}
+	return Field{Path: "datasource.name", Value: selector.Name}
+}
+
+// List returns the fields of a list of strings, e.g. the matchers of a variable.
+func List(path string, values []string) []Field {
+	fields := make([]Field, 0, len(values))
+	for i, value := range values {
+		fields = append(fields, Field{Path: fmt.Sprintf("%s[%d]", path, i), Value: value})
+	}
+	return fields
+}
+
+// extractFromText returns the variables used in a text field of the dashboard.
+func extractFromText(location string, value string, text bool) []Reference {
+	var refs []Reference
+	for _, name := range ExtractReferences(value) {
+		refs = append(refs, Reference{Name: name, Location: location, Text: text})
+	}
+	return refs
+}
+
+// extractFromPlugin decodes the plugin spec (a typed one coming from a builder, or a generic one coming
+// from a decoded dashboard) with the decoder registered for its kind, and returns the variables used in its fields.
+// A plugin without decoder is not looked at.
+func extractFromPlugin(location string, plugin common.Plugin) ([]Reference, error) {
+	decoder := getDecoder(plugin.Kind)
+	if decoder == nil || plugin.Spec == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(plugin.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode %s: %w", location, err)
+	}
+	fields, err := decoder(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode %s: %w", location, err)
+	}
+	var refs []Reference
+	for _, field := range fields {
+		refs = append(refs, extractFromText(location+"."+field.Path, field.Value, field.Text)...)
+	}
+	return refs, nil
+}
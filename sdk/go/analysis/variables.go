@@ -0,0 +1,238 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package analysis provides static checks on dashboards using the plugins of this repository.
+package analysis
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/dashboard"
+)
+
+type DiagnosticKind string
+
+const (
+	// UndefinedVariable is reported when a variable is used but not defined.
+	UndefinedVariable DiagnosticKind = "undefined"
+	// UnusedVariable is reported when a variable is defined but never used.
+	UnusedVariable DiagnosticKind = "unused"
+	// VariableOrder is reported when a variable uses another one defined after it.
+	VariableOrder DiagnosticKind = "order"
+	// CyclicVariable is reported when variables depend on each other.
+	CyclicVariable DiagnosticKind = "cycle"
+)
+
+// Diagnostic is an issue found by the analysis.
+type Diagnostic struct {
+	Kind DiagnosticKind
+	// Variable is the name of the variable concerned.
+	Variable string
+	// Location is the path of the field where the issue is found.
+	Location string
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s", d.Location, d.Message)
+}
+
+type Options struct {
+	// ExternalVariables are the names of the variables defined outside the dashboard (project and global variables).
+	// They are never reported as undefined.
+	ExternalVariables []string
+}
+
+type variableInfo struct {
+	index    int
+	name     string
+	location string
+	refs     []Reference
+}
+
+// CheckVariables reports the undefined, unused and cyclic variables of the dashboard, as well as the variables
+// using another variable defined after them.
+// The plugins are looked at through the decoder registered for their kind (see Register), along with the display and
+// the links of the panels, the links of the dashboard and the value of the text variables.
+// The references found in a free text (e.g. $USD in a Markdown text or a panel title) mark the variables as used but
+// are not reported as undefined. The builtin variables (starting with "__") are ignored.
+func CheckVariables(dash *v1.Dashboard, opts Options) ([]Diagnostic, error) {
+	var diagnostics []Diagnostic
+	var variables []*variableInfo
+	defined := make(map[string]*variableInfo)
+	for i, variable := range dash.Spec.Variables {
+		name := variable.Spec.GetName()
+		info := &variableInfo{index: i, name: name, location: fmt.Sprintf("variables[%d]", i)}
+		switch spec := variable.Spec.(type) {
+		case *dashboard.ListVariableSpec:
+			refs, err := extractFromPlugin(info.location+".spec.plugin.spec", spec.Plugin)
+			if err != nil {
+				return nil, err
+			}
+			info.refs = refs
+		case *dashboard.TextVariableSpec:
+			info.refs = extractFromText(info.location+".spec.value", spec.Value, true)
+		}
+		variables = append(variables, info)
+		defined[name] = info
+	}
+
+	var refs []Reference
+	for _, variable := range variables {
+		refs = append(refs, variable.refs...)
+	}
+	panelRefs, err := extractFromPanels(dash)
+	if err != nil {
+		return nil, err
+	}
+	refs = append(refs, panelRefs...)
+	refs = append(refs, extractFromLinks("links", dash.Spec.Links)...)
+
+	used := make(map[string]bool)
+	for _, ref := range refs {
+		used[ref.Name] = true
+		if _, ok := defined[ref.Name]; ok || ref.Text || v1.IsBuiltinVariable(ref.Name) || slices.Contains(opts.ExternalVariables, ref.Name) {
+			continue
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Kind:     UndefinedVariable,
+			Variable: ref.Name,
+			Location: ref.Location,
+			Message:  fmt.Sprintf("variable %q is used but not defined", ref.Name),
+		})
+	}
+
+	for _, variable := range variables {
+		if !used[variable.name] {
+			diagnostics = append(diagnostics, Diagnostic{
+				Kind:     UnusedVariable,
+				Variable: variable.name,
+				Location: variable.location,
+				Message:  fmt.Sprintf("variable %q is defined but never used", variable.name),
+			})
+		}
+		for _, ref := range variable.refs {
+			dep, ok := defined[ref.Name]
+			if ok && dep.index > variable.index {
+				diagnostics = append(diagnostics, Diagnostic{
+					Kind:     VariableOrder,
+					Variable: variable.name,
+					Location: ref.Location,
+					Message:  fmt.Sprintf("variable %q uses the variable %q that is defined after it", variable.name, ref.Name),
+				})
+			}
+		}
+	}
+
+	diagnostics = append(diagnostics, findCycles(variables, defined)...)
+	return diagnostics, nil
+}
+
+// extractFromPanels returns the variables used in the panels: their display, their plugin, their queries and their links.
+func extractFromPanels(dash *v1.Dashboard) ([]Reference, error) {
+	var refs []Reference
+	panelKeys := make([]string, 0, len(dash.Spec.Panels))
+	for key := range dash.Spec.Panels {
+		panelKeys = append(panelKeys, key)
+	}
+	sort.Strings(panelKeys)
+	for _, key := range panelKeys {
+		panel := dash.Spec.Panels[key]
+		if panel == nil {
+			continue
+		}
+		location := fmt.Sprintf("panels.%s.spec", key)
+		if display := panel.Spec.Display; display != nil {
+			refs = append(refs, extractFromText(location+".display.name", display.Name, true)...)
+			refs = append(refs, extractFromText(location+".display.description", display.Description, true)...)
+		}
+		r, err := extractFromPlugin(location+".plugin.spec", panel.Spec.Plugin)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, r...)
+		for i, query := range panel.Spec.Queries {
+			r, err = extractFromPlugin(fmt.Sprintf("%s.queries[%d].spec.plugin.spec", location, i), query.Spec.Plugin)
+			if err != nil {
+				return nil, err
+			}
+			refs = append(refs, r...)
+		}
+		refs = append(refs, extractFromLinks(location+".links", panel.Spec.Links)...)
+	}
+	return refs, nil
+}
+
+// extractFromLinks returns the variables used in the links rendering them.
+// The name and the tooltip of a link are free texts, its url is not.
+func extractFromLinks(location string, links []v1.Link) []Reference {
+	var refs []Reference
+	for i, link := range links {
+		if !link.RenderVariables {
+			continue
+		}
+		linkLocation := fmt.Sprintf("%s[%d]", location, i)
+		refs = append(refs, extractFromText(linkLocation+".name", link.Name, true)...)
+		refs = append(refs, extractFromText(linkLocation+".url", link.URL, false)...)
+		refs = append(refs, extractFromText(linkLocation+".tooltip", link.Tooltip, true)...)
+	}
+	return refs
+}
+
+// findCycles reports each cycle of the variable dependency graph once.
+func findCycles(variables []*variableInfo, defined map[string]*variableInfo) []Diagnostic {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int)
+	var diagnostics []Diagnostic
+	var stack []string
+	var visit func(v *variableInfo)
+	visit = func(v *variableInfo) {
+		state[v.name] = visiting
+		stack = append(stack, v.name)
+		for _, ref := range v.refs {
+			dep, ok := defined[ref.Name]
+			if !ok {
+				continue
+			}
+			switch state[dep.name] {
+			case unvisited:
+				visit(dep)
+			case visiting:
+				start := slices.Index(stack, dep.name)
+				cycle := append(slices.Clone(stack[start:]), dep.name)
+				diagnostics = append(diagnostics, Diagnostic{
+					Kind:     CyclicVariable,
+					Variable: dep.name,
+					Location: ref.Location,
+					Message:  fmt.Sprintf("variables form a cycle: %s", strings.Join(cycle, " -> ")),
+				})
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[v.name] = done
+	}
+	for _, v := range variables {
+		if state[v.name] == unvisited {
+			visit(v)
+		}
+	}
+	return diagnostics
+}
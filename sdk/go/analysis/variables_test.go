@@ -0,0 +1,253 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/perses/perses/go-sdk/datasource"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+// The decoders of the plugins used by the tests, as the plugins register them.
+func init() {
+	type querySpec struct {
+		Datasource *datasource.Selector `json:"datasource,omitempty"`
+		Query      string               `json:"query"`
+	}
+	queryDecoder := Decode(func(spec querySpec) []Field {
+		return []Field{Datasource(spec.Datasource), {Path: "query", Value: spec.Query}}
+	})
+	Register("PrometheusTimeSeriesQuery", queryDecoder)
+	Register("ClickHouseTimeSeriesQuery", queryDecoder)
+	Register("PrometheusLabelValuesVariable", Decode(func(spec struct {
+		LabelName string   `json:"labelName"`
+		Matchers  []string `json:"matchers"`
+	}) []Field {
+		return append([]Field{{Path: "labelName", Value: spec.LabelName}}, List("matchers", spec.Matchers)...)
+	}))
+	Register("PrometheusPromQLVariable", Decode(func(spec struct {
+		Expr      string `json:"expr"`
+		LabelName string `json:"labelName"`
+	}) []Field {
+		return []Field{{Path: "expr", Value: spec.Expr}, {Path: "labelName", Value: spec.LabelName}}
+	}))
+	Register("Markdown", Decode(func(spec struct {
+		Text string `json:"text"`
+	}) []Field {
+		return []Field{{Path: "text", Value: spec.Text, Text: true}}
+	}))
+}
+
+func TestExtractReferences(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		text     string
+		expected []string
+	}{
+		{
+			title:    "promql",
+			text:     `label_replace(rate(http_requests_total{job="$job", env=~"${env:regex}"}[$__rate_interval]), "svc", "$1", "pod", "(.*)")`,
+			expected: []string{"job", "env", "__rate_interval"},
+		},
+		{
+			title:    "logql",
+			text:     `{namespace="$namespace"} |= "${search}" | json`,
+			expected: []string{"namespace", "search"},
+		},
+		{
+			title:    "traceql",
+			text:     `{ resource.service.name = "$service" && duration > ${min_duration} }`,
+			expected: []string{"service", "min_duration"},
+		},
+		{
+			title:    "sql",
+			text:     `SELECT * FROM logs WHERE host IN (${hosts:singlequote}) AND level = '$level'`,
+			expected: []string{"hosts", "level"},
+		},
+		{
+			title:    "spl",
+			text:     `index=$index sourcetype=${sourcetype} | stats count by host`,
+			expected: []string{"index", "sourcetype"},
+		},
+		{
+			title:    "logsql",
+			text:     `_stream:{app="$app"} AND error | limit ${limit}`,
+			expected: []string{"app", "limit"},
+		},
+		{
+			title:    "markdown",
+			text:     "# Service $service\nIt costs $5.00 per month. See ${link.url}.",
+			expected: []string{"service", "link"},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			result := ExtractReferences(test.text)
+			if !slices.Equal(result, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
+
+const testDashboard = `{
+  "kind": "Dashboard",
+  "metadata": {"name": "test", "project": "test"},
+  "spec": {
+    "duration": "1h",
+    "variables": [
+      {"kind": "ListVariable", "spec": {"name": "ds", "allowAllValue": false, "allowMultiple": false, "plugin": {"kind": "DatasourceVariable", "spec": {"datasourcePluginKind": "PrometheusDatasource"}}}},
+      {"kind": "ListVariable", "spec": {"name": "job", "allowAllValue": false, "allowMultiple": false, "plugin": {"kind": "PrometheusLabelValuesVariable", "spec": {"labelName": "job", "matchers": ["up{env=\"$env\"}"]}}}},
+      {"kind": "ListVariable", "spec": {"name": "env", "allowAllValue": false, "allowMultiple": false, "plugin": {"kind": "StaticListVariable", "spec": {"values": ["dev", "prod"]}}}},
+      {"kind": "TextVariable", "spec": {"name": "unused", "value": "foo"}},
+      {"kind": "ListVariable", "spec": {"name": "a", "allowAllValue": false, "allowMultiple": false, "plugin": {"kind": "PrometheusPromQLVariable", "spec": {"expr": "up{b=\"$b\"}", "labelName": "a"}}}},
+      {"kind": "ListVariable", "spec": {"name": "b", "allowAllValue": false, "allowMultiple": false, "plugin": {"kind": "PrometheusPromQLVariable", "spec": {"expr": "up{a=\"$a\"}", "labelName": "b"}}}}
+    ],
+    "panels": {
+      "md": {"kind": "Panel", "spec": {"display": {"name": "Job $job"}, "plugin": {"kind": "Markdown", "spec": {"text": "It costs $5 on ${__dashboard}"}}}},
+      "cpu": {"kind": "Panel", "spec": {"plugin": {"kind": "TimeSeriesChart", "spec": {}}, "queries": [
+        {"kind": "TimeSeriesQuery", "spec": {"plugin": {"kind": "PrometheusTimeSeriesQuery", "spec": {"query": "label_replace(up{job=\"$job\"}, \"x\", \"$1\", \"y\", \"(.*)\")", "datasource": {"kind": "PrometheusDatasource", "name": "$ds"}}}}},
+        {"kind": "TimeSeriesQuery", "spec": {"plugin": {"kind": "ClickHouseTimeSeriesQuery", "spec": {"query": "SELECT * FROM t WHERE c IN (${missing:csv}) AND p = '$project_var'"}}}}
+      ]}}
+    },
+    "layouts": []
+  }
+}`
+
+func TestCheckVariables(t *testing.T) {
+	var dash v1.Dashboard
+	if err := json.Unmarshal([]byte(testDashboard), &dash); err != nil {
+		t.Fatal(err)
+	}
+	diagnostics, err := CheckVariables(&dash, Options{ExternalVariables: []string{"project_var"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Diagnostic{
+		{Kind: UndefinedVariable, Variable: "missing", Location: "panels.cpu.spec.queries[1].spec.plugin.spec.query"},
+		{Kind: VariableOrder, Variable: "job", Location: "variables[1].spec.plugin.spec.matchers[0]"},
+		{Kind: UnusedVariable, Variable: "unused", Location: "variables[3]"},
+		{Kind: VariableOrder, Variable: "a", Location: "variables[4].spec.plugin.spec.expr"},
+		{Kind: CyclicVariable, Variable: "a", Location: "variables[5].spec.plugin.spec.expr"},
+	}
+	if len(diagnostics) != len(expected) {
+		t.Fatalf("expected %d diagnostics, got %d: %v", len(expected), len(diagnostics), diagnostics)
+	}
+	for i, e := range expected {
+		d := diagnostics[i]
+		if d.Kind != e.Kind || d.Variable != e.Variable || d.Location != e.Location {
+			t.Errorf("diagnostic %d: expected %s %q at %q, got %s %q at %q", i, e.Kind, e.Variable, e.Location, d.Kind, d.Variable, d.Location)
+		}
+	}
+	if diagnostics[4].Message != "variables form a cycle: a -> b -> a" {
+		t.Errorf("unexpected cycle message: %s", diagnostics[4].Message)
+	}
+}
+
+func TestCheckVariables_FreeText(t *testing.T) {
+	var dash v1.Dashboard
+	if err := json.Unmarshal([]byte(`{
+  "kind": "Dashboard",
+  "metadata": {"name": "test", "project": "test"},
+  "spec": {
+    "duration": "1h",
+    "variables": [
+      {"kind": "TextVariable", "spec": {"name": "currency", "value": "$USD"}}
+    ],
+    "panels": {
+      "md": {"kind": "Panel", "spec": {"display": {"name": "Cost in $EUR"}, "plugin": {"kind": "Markdown", "spec": {"text": "All the amounts are in $USD"}}}}
+    },
+    "layouts": []
+  }
+}`), &dash); err != nil {
+		t.Fatal(err)
+	}
+	diagnostics, err := CheckVariables(&dash, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diagnostics) != 1 || diagnostics[0].Kind != UnusedVariable || diagnostics[0].Variable != "currency" {
+		t.Errorf("expected only the unused variable %q, got %v", "currency", diagnostics)
+	}
+}
+
+func TestCheckVariables_UsedOnlyOutsideOfTheQueries(t *testing.T) {
+	testSuites := []struct {
+		title string
+		panel string
+	}{
+		{
+			title: "markdown panel",
+			panel: `{"kind": "Panel", "spec": {"plugin": {"kind": "Markdown", "spec": {"text": "Logs of ${service}"}}}}`,
+		},
+		{
+			title: "panel title",
+			panel: `{"kind": "Panel", "spec": {"display": {"name": "Requests of $service"}, "plugin": {"kind": "TimeSeriesChart", "spec": {}}}}`,
+		},
+		{
+			title: "panel link",
+			panel: `{"kind": "Panel", "spec": {"plugin": {"kind": "TimeSeriesChart", "spec": {}}, "links": [{"url": "/explore?service=$service", "renderVariables": true}]}}`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			var dash v1.Dashboard
+			if err := json.Unmarshal([]byte(`{
+  "kind": "Dashboard",
+  "metadata": {"name": "test", "project": "test"},
+  "spec": {
+    "duration": "1h",
+    "variables": [{"kind": "TextVariable", "spec": {"name": "service", "value": "api"}}],
+    "panels": {"p": `+test.panel+`},
+    "layouts": []
+  }
+}`), &dash); err != nil {
+				t.Fatal(err)
+			}
+			diagnostics, err := CheckVariables(&dash, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(diagnostics) > 0 {
+				t.Errorf("expected no diagnostic, got %v", diagnostics)
+			}
+		})
+	}
+}
+
+func TestCheckVariables_LinkWithoutRenderVariables(t *testing.T) {
+	var dash v1.Dashboard
+	if err := json.Unmarshal([]byte(`{
+  "kind": "Dashboard",
+  "metadata": {"name": "test", "project": "test"},
+  "spec": {
+    "duration": "1h",
+    "panels": {},
+    "layouts": [],
+    "links": [{"url": "/explore?service=$service"}, {"url": "/explore?env=$env", "renderVariables": true}]
+  }
+}`), &dash); err != nil {
+		t.Fatal(err)
+	}
+	diagnostics, err := CheckVariables(&dash, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diagnostics) != 1 || diagnostics[0].Kind != UndefinedVariable || diagnostics[0].Location != "links[1].url" {
+		t.Errorf("expected only the undefined variable %q of the second link, got %v", "env", diagnostics)
+	}
+}
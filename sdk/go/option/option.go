@@ -0,0 +1,162 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package option provides the helpers shared by the builders of every plugin SDK to apply their options.
+//
+// A builder runs all its options, even when one of them fails, so that a misconfigured plugin reports every issue at once.
+// Each failure is wrapped in a BuildError that records the plugin kind and the name of the option that failed.
+// The errors are returned joined with errors.Join; use Errors to get them back individually.
+package option
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// BuildError is the error returned when an option of a plugin builder, or the validation of the built plugin, fails.
+type BuildError struct {
+	// PluginKind is the kind of the plugin being built (e.g. "PrometheusTimeSeriesQuery").
+	PluginKind string
+	// Option is the name of the option that failed (e.g. "MinStep").
+	// It is empty when the error comes from the validation of the built plugin.
+	Option string
+	Err    error
+}
+
+func (e *BuildError) Error() string {
+	if len(e.Option) == 0 {
+		return fmt.Sprintf("plugin %s: invalid spec: %s", e.PluginKind, e.Err)
+	}
+	return fmt.Sprintf("plugin %s: option %s: %s", e.PluginKind, e.Option, e.Err)
+}
+
+func (e *BuildError) Unwrap() error {
+	return e.Err
+}
+
+// Collector accumulates the errors of a plugin builder.
+type Collector struct {
+	pluginKind string
+	errs       []error
+}
+
+func NewCollector(pluginKind string) *Collector {
+	return &Collector{pluginKind: pluginKind}
+}
+
+// Add records the error returned by the given option. A nil error is ignored.
+func (c *Collector) Add(option string, err error) {
+	if err == nil {
+		return
+	}
+	c.errs = append(c.errs, &BuildError{PluginKind: c.pluginKind, Option: option, Err: err})
+}
+
+// AddValidation records an error coming from the validation of the built plugin. A nil error is ignored.
+func (c *Collector) AddValidation(err error) {
+	c.Add("", err)
+}
+
+// Err returns every recorded error joined, or nil if there is none.
+func (c *Collector) Err() error {
+	return errors.Join(c.errs...)
+}
+
+// Apply runs every option on the builder and returns all the errors they produced, joined.
+func Apply[B any, O ~func(*B) error](pluginKind string, builder *B, options []O) error {
+	return ApplyAndValidate(pluginKind, builder, options, nil)
+}
+
+// ApplyAndValidate runs every option on the builder, then the validate function if it is not nil,
+// and returns all the errors they produced, joined.
+func ApplyAndValidate[B any, O ~func(*B) error](pluginKind string, builder *B, options []O, validate func() error) error {
+	collector := NewCollector(pluginKind)
+	for _, opt := range options {
+		if opt == nil {
+			continue
+		}
+		collector.Add(Name(opt), opt(builder))
+	}
+	if validate != nil {
+		collector.AddValidation(validate())
+	}
+	return collector.Err()
+}
+
+// Errors returns every BuildError contained in err, walking through joined and wrapped errors.
+func Errors(err error) []*BuildError {
+	var result []*BuildError
+	var walk func(error)
+	walk = func(e error) {
+		if e == nil {
+			return
+		}
+		if buildErr, ok := e.(*BuildError); ok {
+			result = append(result, buildErr)
+		}
+		switch u := e.(type) {
+		case interface{ Unwrap() []error }:
+			for _, inner := range u.Unwrap() {
+				walk(inner)
+			}
+		case interface{ Unwrap() error }:
+			walk(u.Unwrap())
+		}
+	}
+	walk(err)
+	return result
+}
+
+// Name returns the name of the function that created the given option.
+// For example, the option returned by `query.MinStep(...)` is named "MinStep".
+func Name(opt any) string {
+	value := reflect.ValueOf(opt)
+	if value.Kind() != reflect.Func || value.IsNil() {
+		return ""
+	}
+	fn := runtime.FuncForPC(value.Pointer())
+	if fn == nil {
+		return ""
+	}
+	// the name looks like "github.com/perses/plugins/prometheus/sdk/go/query.MinStep.func1"
+	name := strings.TrimSuffix(fn.Name(), "-fm")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	parts := strings.Split(name, ".")
+	if len(parts) < 2 {
+		return name
+	}
+	// drop the package name and the closure suffixes (func1, func1.2, ...)
+	parts = parts[1:]
+	for len(parts) > 1 && isClosureSuffix(parts[len(parts)-1]) {
+		parts = parts[:len(parts)-1]
+	}
+	return strings.Join(parts, ".")
+}
+
+func isClosureSuffix(s string) bool {
+	s = strings.TrimPrefix(s, "func")
+	if len(s) == 0 {
+		return true
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package option
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type testBuilder struct {
+	values []string
+}
+
+type testOption func(builder *testBuilder) error
+
+func Value(value string) testOption {
+	return func(builder *testBuilder) error {
+		builder.values = append(builder.values, value)
+		return nil
+	}
+}
+
+func Invalid(reason string) testOption {
+	return func(builder *testBuilder) error {
+		return errors.New(reason)
+	}
+}
+
+func TestApplyRunsEveryOption(t *testing.T) {
+	builder := &testBuilder{}
+	err := Apply("TestPlugin", builder, []testOption{Invalid("first"), Value("a"), Invalid("second"), Value("b")})
+	if len(builder.values) != 2 {
+		t.Fatalf("expected every valid option to be applied, got %v", builder.values)
+	}
+	buildErrs := Errors(err)
+	if len(buildErrs) != 2 {
+		t.Fatalf("expected 2 build errors, got %d: %v", len(buildErrs), err)
+	}
+	for i, expected := range []string{"first", "second"} {
+		if buildErrs[i].PluginKind != "TestPlugin" {
+			t.Errorf("expected plugin kind TestPlugin, got %q", buildErrs[i].PluginKind)
+		}
+		if buildErrs[i].Option != "Invalid" {
+			t.Errorf("expected option Invalid, got %q", buildErrs[i].Option)
+		}
+		if buildErrs[i].Err.Error() != expected {
+			t.Errorf("expected error %q, got %q", expected, buildErrs[i].Err)
+		}
+	}
+}
+
+func TestApplyAndValidate(t *testing.T) {
+	builder := &testBuilder{}
+	err := ApplyAndValidate("TestPlugin", builder, []testOption{Value("a")}, func() error {
+		return fmt.Errorf("%d value is not enough", len(builder.values))
+	})
+	var buildErr *BuildError
+	if !errors.As(err, &buildErr) {
+		t.Fatalf("expected a BuildError, got %v", err)
+	}
+	if buildErr.Option != "" {
+		t.Errorf("expected no option for a validation error, got %q", buildErr.Option)
+	}
+	if err.Error() != "plugin TestPlugin: invalid spec: 1 value is not enough" {
+		t.Errorf("unexpected error message: %q", err)
+	}
+}
+
+func TestApplyWithoutError(t *testing.T) {
+	if err := Apply("TestPlugin", &testBuilder{}, []testOption{Value("a"), nil}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/common"
+	"github.com/perses/perses/pkg/model/api/v1/dashboard"
+)
+
+// Issue is an inconsistency between a plugin and the datasource it selects.
+type Issue struct {
+	// Location is the path of the plugin in the dashboard, e.g. "panels.cpu.spec.queries[0]".
+	Location string
+	// PluginKind is the kind of the plugin holding the selector.
+	PluginKind string
+	Err        error
+}
+
+func (i *Issue) Error() string {
+	return fmt.Sprintf("%s (%s): %s", i.Location, i.PluginKind, i.Err)
+}
+
+func (i *Issue) Unwrap() error {
+	return i.Err
+}
+
+// CheckDashboard checks every query and variable of the dashboard:
+//   - the kind of its datasource selector must be compatible with the plugin,
+//   - a selector naming a datasource defined in the dashboard must have the kind of this datasource,
+//   - a selector using a DatasourceVariable (e.g. name: "$ds") must have the kind set in the datasourcePluginKind of the variable.
+//
+// It also checks that every DatasourceVariable references a datasource kind.
+// Only the plugins registered with Register are checked: import the Go SDK of the plugins used by the dashboard.
+// It returns all the issues found, joined. Each of them is an *Issue.
+func CheckDashboard(dash *v1.Dashboard) error {
+	var issues []error
+	datasourceVariables := make(map[string]string)
+	for i, variable := range dash.Spec.Variables {
+		spec := listVariableSpec(variable)
+		if spec == nil || spec.Plugin.Kind != datasourceVariableKind {
+			continue
+		}
+		location := fmt.Sprintf("variables[%d]", i)
+		kind, err := datasourcePluginKind(spec.Plugin)
+		if err == nil {
+			datasourceVariables[spec.Name] = kind
+			err = CheckDatasourcePluginKind(kind)
+		}
+		if err != nil {
+			issues = append(issues, &Issue{Location: location, PluginKind: spec.Plugin.Kind, Err: err})
+		}
+	}
+
+	check := func(location string, plugin common.Plugin) {
+		if err := checkPlugin(dash, datasourceVariables, plugin); err != nil {
+			issues = append(issues, &Issue{Location: location, PluginKind: plugin.Kind, Err: err})
+		}
+	}
+	for i, variable := range dash.Spec.Variables {
+		if spec := listVariableSpec(variable); spec != nil && spec.Plugin.Kind != datasourceVariableKind {
+			check(fmt.Sprintf("variables[%d]", i), spec.Plugin)
+		}
+	}
+	panelKeys := make([]string, 0, len(dash.Spec.Panels))
+	for key := range dash.Spec.Panels {
+		panelKeys = append(panelKeys, key)
+	}
+	sort.Strings(panelKeys)
+	for _, key := range panelKeys {
+		panel := dash.Spec.Panels[key]
+		if panel == nil {
+			continue
+		}
+		for i, query := range panel.Spec.Queries {
+			check(fmt.Sprintf("panels.%s.spec.queries[%d]", key, i), query.Spec.Plugin)
+		}
+	}
+	return errors.Join(issues...)
+}
+
+func checkPlugin(dash *v1.Dashboard, datasourceVariables map[string]string, plugin common.Plugin) error {
+	sel, err := FromSpec(plugin.Spec)
+	if err != nil {
+		return fmt.Errorf("unable to read the datasource selector: %w", err)
+	}
+	if sel == nil {
+		return nil
+	}
+	if checkErr := Check(plugin.Kind, sel); checkErr != nil {
+		return checkErr
+	}
+	if varName := VariableName(sel); len(varName) > 0 {
+		if kind, ok := datasourceVariables[varName]; ok && kind != sel.Kind {
+			return fmt.Errorf("the datasource selector has the kind %q but the DatasourceVariable %q lists datasources of kind %q", sel.Kind, varName, kind)
+		}
+		return nil
+	}
+	if ds, ok := dash.Spec.Datasources[sel.Name]; ok && ds != nil && ds.Plugin.Kind != sel.Kind {
+		return fmt.Errorf("the datasource selector has the kind %q but the dashboard datasource %q is a %s", sel.Kind, sel.Name, ds.Plugin.Kind)
+	}
+	return nil
+}
+
+// CheckDatasourcePluginKind returns an error if the kind cannot be the datasourcePluginKind of a DatasourceVariable,
+// i.e. when it's empty or when it's the kind of a registered query or variable plugin.
+// Any other kind is accepted, as the datasource plugins are not registered.
+func CheckDatasourcePluginKind(kind string) error {
+	if len(kind) == 0 {
+		return fmt.Errorf("datasourcePluginKind cannot be empty")
+	}
+	if len(ExpectedDatasourceKinds(kind)) > 0 {
+		return fmt.Errorf("datasourcePluginKind %q is not a datasource but a plugin querying one", kind)
+	}
+	return nil
+}
+
+func datasourcePluginKind(plugin common.Plugin) (string, error) {
+	data, err := json.Marshal(plugin.Spec)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the DatasourceVariable spec: %w", err)
+	}
+	var tmp struct {
+		DatasourcePluginKind string `json:"datasourcePluginKind"`
+	}
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return "", fmt.Errorf("unable to read the DatasourceVariable spec: %w", err)
+	}
+	return tmp.DatasourcePluginKind, nil
+}
+
+func listVariableSpec(variable dashboard.Variable) *dashboard.ListVariableSpec {
+	spec, _ := variable.Spec.(*dashboard.ListVariableSpec)
+	return spec
+}
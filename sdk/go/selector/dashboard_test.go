@@ -0,0 +1,141 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/perses/perses/go-sdk/datasource"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+const testDashboard = `{
+  "kind": "Dashboard",
+  "metadata": {"name": "test", "project": "test"},
+  "spec": {
+    "duration": "1h",
+    "datasources": {
+      "logs": {"default": false, "plugin": {"kind": "LokiDatasource", "spec": {"directUrl": "http://loki"}}}
+    },
+    "variables": [
+      {"kind": "ListVariable", "spec": {"name": "ds", "allowAllValue": false, "allowMultiple": false, "plugin": {"kind": "DatasourceVariable", "spec": {"datasourcePluginKind": "PrometheusDatasource"}}}},
+      {"kind": "ListVariable", "spec": {"name": "job", "allowAllValue": false, "allowMultiple": false, "plugin": {"kind": "PrometheusLabelValuesVariable", "spec": {"labelName": "job", "datasource": {"kind": "PrometheusDatasource", "name": "$ds"}}}}},
+      {"kind": "ListVariable", "spec": {"name": "typo", "allowAllValue": false, "allowMultiple": false, "plugin": {"kind": "DatasourceVariable", "spec": {"datasourcePluginKind": "PrometheusTimeSeriesQuery"}}}}
+    ],
+    "panels": {
+      "ok": {"kind": "Panel", "spec": {"plugin": {"kind": "TimeSeriesChart", "spec": {}}, "queries": [
+        {"kind": "TimeSeriesQuery", "spec": {"plugin": {"kind": "PrometheusTimeSeriesQuery", "spec": {"query": "up", "datasource": {"kind": "PrometheusDatasource", "name": "${ds}"}}}}},
+        {"kind": "TimeSeriesQuery", "spec": {"plugin": {"kind": "PrometheusTimeSeriesQuery", "spec": {"query": "up"}}}}
+      ]}},
+      "wrongKind": {"kind": "Panel", "spec": {"plugin": {"kind": "TimeSeriesChart", "spec": {}}, "queries": [
+        {"kind": "TimeSeriesQuery", "spec": {"plugin": {"kind": "PrometheusTimeSeriesQuery", "spec": {"query": "up", "datasource": {"kind": "LokiDatasource"}}}}}
+      ]}},
+      "wrongVariable": {"kind": "Panel", "spec": {"plugin": {"kind": "LogsTable", "spec": {}}, "queries": [
+        {"kind": "LogQuery", "spec": {"plugin": {"kind": "LokiLogQuery", "spec": {"query": "{job=\"a\"}", "datasource": {"kind": "LokiDatasource", "name": "$ds"}}}}}
+      ]}},
+      "wrongLocal": {"kind": "Panel", "spec": {"plugin": {"kind": "TimeSeriesChart", "spec": {}}, "queries": [
+        {"kind": "TimeSeriesQuery", "spec": {"plugin": {"kind": "PrometheusTimeSeriesQuery", "spec": {"query": "up", "datasource": {"kind": "PrometheusDatasource", "name": "logs"}}}}}
+      ]}}
+    },
+    "layouts": []
+  }
+}`
+
+func init() {
+	Register("PrometheusTimeSeriesQuery", "PrometheusDatasource")
+	Register("PrometheusLabelValuesVariable", "PrometheusDatasource")
+	Register("LokiLogQuery", "LokiDatasource")
+}
+
+func TestCheckDashboard(t *testing.T) {
+	var dash v1.Dashboard
+	if err := json.Unmarshal([]byte(testDashboard), &dash); err != nil {
+		t.Fatal(err)
+	}
+	err := CheckDashboard(&dash)
+	expected := []string{
+		"variables[2]",
+		"panels.wrongKind.spec.queries[0]",
+		"panels.wrongLocal.spec.queries[0]",
+		"panels.wrongVariable.spec.queries[0]",
+	}
+	var issues []*Issue
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var issue *Issue
+		if !errors.As(e, &issue) {
+			t.Fatalf("expected an Issue, got %v", e)
+		}
+		issues = append(issues, issue)
+	}
+	if len(issues) != len(expected) {
+		t.Fatalf("expected %d issues, got %d: %v", len(expected), len(issues), err)
+	}
+	for i, location := range expected {
+		if issues[i].Location != location {
+			t.Errorf("expected issue %d at %q, got %q", i, location, issues[i].Location)
+		}
+	}
+}
+
+func TestCheckDatasourcePluginKind(t *testing.T) {
+	testSuites := []struct {
+		kind   string
+		errMsg string
+	}{
+		{kind: "PrometheusDatasource"},
+		{kind: "ExternalDatasource"},
+		{kind: "", errMsg: "datasourcePluginKind cannot be empty"},
+		{kind: "PrometheusTimeSeriesQuery", errMsg: "is not a datasource but a plugin querying one"},
+	}
+	for _, test := range testSuites {
+		t.Run(test.kind, func(t *testing.T) {
+			err := CheckDatasourcePluginKind(test.kind)
+			if len(test.errMsg) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.errMsg) {
+				t.Errorf("expected an error containing %q, got %v", test.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestVariableName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"$ds":       "ds",
+		"${ds}":     "ds",
+		"${ds:raw}": "ds",
+		"prom":      "",
+	} {
+		if got := VariableName(&datasource.Selector{Name: name}); got != expected {
+			t.Errorf("VariableName(%q) = %q, expected %q", name, got, expected)
+		}
+	}
+}
+
+func TestCheckUnregisteredPlugin(t *testing.T) {
+	sel := &datasource.Selector{Kind: "AnyDatasource", Name: "any"}
+	if err := Check("UnregisteredQuery", sel); err != nil {
+		t.Errorf("unexpected error for a plugin that is not registered: %v", err)
+	}
+	if err := Check("PrometheusTimeSeriesQuery", sel); err == nil {
+		t.Error("expected an error for a registered plugin selecting another kind of datasource")
+	}
+}
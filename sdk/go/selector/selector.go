@@ -0,0 +1,113 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package selector checks that the datasource selectors used by the query and variable plugins
+// point to a datasource of a kind the plugin can query.
+package selector
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/perses/perses/go-sdk/datasource"
+)
+
+const datasourceVariableKind = "DatasourceVariable"
+
+var (
+	mutex sync.RWMutex
+	// expectedKinds maps the kind of the query and variable plugins to the kinds of datasource they can query.
+	// It is filled by the plugins themselves, see Register.
+	expectedKinds = map[string][]string{}
+)
+
+// Register declares the kinds of datasource a query or variable plugin can query.
+// The plugins of this repository call it when their Go SDK package is initialized; calling it again for the same
+// plugin kind adds kinds.
+func Register(pluginKind string, datasourceKinds ...string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	for _, kind := range datasourceKinds {
+		if !slices.Contains(expectedKinds[pluginKind], kind) {
+			expectedKinds[pluginKind] = append(expectedKinds[pluginKind], kind)
+		}
+	}
+}
+
+// ExpectedDatasourceKinds returns the kinds of datasource the given plugin can query.
+// It returns nil when the plugin kind is not registered.
+func ExpectedDatasourceKinds(pluginKind string) []string {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return slices.Clone(expectedKinds[pluginKind])
+}
+
+// Check returns an error if the selector points to a kind of datasource the plugin cannot query.
+// A nil selector (meaning the default datasource) and a plugin kind that is not registered are accepted.
+func Check(pluginKind string, selector *datasource.Selector) error {
+	if selector == nil {
+		return nil
+	}
+	expected := ExpectedDatasourceKinds(pluginKind)
+	if len(expected) == 0 {
+		return nil
+	}
+	if len(selector.Kind) == 0 {
+		return fmt.Errorf("datasource selector kind cannot be empty, expected %s", strings.Join(expected, " or "))
+	}
+	if !slices.Contains(expected, selector.Kind) {
+		return fmt.Errorf("%s cannot query a datasource of kind %q, expected %s", pluginKind, selector.Kind, strings.Join(expected, " or "))
+	}
+	return nil
+}
+
+// FromSpec extracts the datasource selector of a plugin spec, whether it's a typed spec coming from a builder
+// or a generic map coming from a decoded dashboard. It returns nil when the spec has no datasource.
+func FromSpec(spec any) (*datasource.Selector, error) {
+	if spec == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var tmp struct {
+		Datasource *datasource.Selector `json:"datasource,omitempty"`
+	}
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return nil, err
+	}
+	return tmp.Datasource, nil
+}
+
+// VariableName returns the name of the variable used as the datasource name of the selector (e.g. "$ds" or "${ds}").
+// It returns an empty string if the name is not a variable reference.
+func VariableName(selector *datasource.Selector) string {
+	if selector == nil {
+		return ""
+	}
+	name := selector.Name
+	if !strings.HasPrefix(name, "$") {
+		return ""
+	}
+	name = strings.TrimPrefix(name, "$")
+	if strings.HasPrefix(name, "{") && strings.HasSuffix(name, "}") {
+		name = strings.TrimSuffix(strings.TrimPrefix(name, "{"), "}")
+		// drop a possible format, e.g. ${ds:raw}
+		name, _, _ = strings.Cut(name, ":")
+	}
+	return name
+}
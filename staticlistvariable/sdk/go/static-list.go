@@ -15,8 +15,11 @@ package staticlist
 
 import (
 	listvariable "github.com/perses/perses/go-sdk/variable/list-variable"
+	"github.com/perses/plugins/sdk/go/option"
 )
 
+const PluginKind = "StaticListVariable"
+
 type PluginSpec struct {
 	Values []string `json:"values" yaml:"values"`
 }
@@ -34,10 +37,8 @@ func create(options ...Option) (Builder, error) {
 
 	var defaults []Option
 
-	for _, opt := range append(defaults, options...) {
-		if err := opt(builder); err != nil {
-			return *builder, err
-		}
+	if err := option.Apply(PluginKind, builder, append(defaults, options...)); err != nil {
+		return *builder, err
 	}
 
 	return *builder, nil
@@ -49,7 +50,7 @@ func StaticList(options ...Option) listvariable.Option {
 		if err != nil {
 			return err
 		}
-		builder.ListVariableSpec.Plugin.Kind = "StaticListVariable"
+		builder.ListVariableSpec.Plugin.Kind = PluginKind
 		builder.ListVariableSpec.Plugin.Spec = t
 		return nil
 	}
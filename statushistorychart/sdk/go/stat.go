@@ -15,6 +15,7 @@ package statushistory
 
 import (
 	"github.com/perses/perses/go-sdk/panel"
+	"github.com/perses/plugins/sdk/go/option"
 )
 
 const PluginKind = "StatusHistoryChart"
@@ -62,10 +63,8 @@ func create(options ...Option) (Builder, error) {
 		PluginSpec: PluginSpec{},
 	}
 
-	for _, opt := range options {
-		if err := opt(builder); err != nil {
-			return *builder, err
-		}
+	if err := option.Apply(PluginKind, builder, options); err != nil {
+		return *builder, err
 	}
 
 	return *builder, nil
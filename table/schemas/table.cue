@@ -29,7 +29,7 @@ spec: close({
 	enableFiltering?:     bool
 	columnSettings?: [...#columnSettings]
 	cellSettings?: [...#cellSettings]
-	transforms?: [...common.#transform]
+	transforms?: [...(common.#transform | #filterRows | #groupBy | #computedColumn)]
 	selection?: common.#selection
 	actions?:   common.#actions
 })
@@ -95,3 +95,41 @@ spec: close({
 	textColor?:       =~"^#(?:[0-9a-fA-F]{3}){1,2}$"
 	backgroundColor?: =~"^#(?:[0-9a-fA-F]{3}){1,2}$"
 }
+
+// transforms specific to the table, applied with the ones of common.#transform in the order of the list
+
+#filterRows: {
+	kind: "FilterRows"
+	spec: {
+		column:    strings.MinRunes(1)
+		condition: #condition
+		exclude?:  bool
+		disabled?: bool
+	}
+}
+
+#groupBy: {
+	kind: "GroupBy"
+	spec: {
+		columns: [strings.MinRunes(1), ...strings.MinRunes(1)]
+		aggregations?: [...{
+			column:      strings.MinRunes(1)
+			aggregation: "sum" | "mean" | "min" | "max" | "count" | "first" | "last"
+			name?:       strings.MinRunes(1)
+		}]
+		disabled?: bool
+	}
+}
+
+#operand: {column: strings.MinRunes(1)} | {value: number}
+
+#computedColumn: {
+	kind: "ComputedColumn"
+	spec: {
+		name:      strings.MinRunes(1)
+		left:      #operand
+		operator:  "+" | "-" | "*" | "/"
+		right:     #operand
+		disabled?: bool
+	}
+}
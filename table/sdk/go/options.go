@@ -14,7 +14,11 @@
 package table
 
 import (
+	"fmt"
+	"slices"
+
 	"github.com/perses/perses/go-sdk/common"
+	persesCommon "github.com/perses/perses/pkg/model/api/v1/common"
 )
 
 func WithDensity(density Density) Option {
@@ -24,14 +28,26 @@ func WithDensity(density Density) Option {
 	}
 }
 
+// Deprecated: use WithDefaultColumnWidth.
 func WithDefaultColumWidth(width int) Option {
+	return WithDefaultColumnWidth(PixelSize(float64(width)))
+}
+
+// WithDefaultColumnWidth sets the default width of the columns, PixelSize or AutoSize.
+func WithDefaultColumnWidth(width *Size) Option {
 	return func(builder *Builder) error {
 		builder.DefaultColumnWidth = width
 		return nil
 	}
 }
 
+// Deprecated: use WithDefaultColumnHeight.
 func WithDefaultColumHeight(height int) Option {
+	return WithDefaultColumnHeight(PixelSize(float64(height)))
+}
+
+// WithDefaultColumnHeight sets the default height of the columns, PixelSize or AutoSize.
+func WithDefaultColumnHeight(height *Size) Option {
 	return func(builder *Builder) error {
 		builder.DefaultColumnHeight = height
 		return nil
@@ -79,3 +95,217 @@ func Transform(transforms []common.Transform) Option {
 		return nil
 	}
 }
+
+// AddTransform appends a transform built with JoinByColumnValue, MergeColumns, MergeIndexedColumns, MergeSeries,
+// FilterRows, ExcludeRows, GroupBy or ComputedColumn.
+func AddTransform(transform common.Transform) Option {
+	return func(builder *Builder) error {
+		builder.Transforms = append(builder.Transforms, transform)
+		return nil
+	}
+}
+
+// JoinByColumnValue joins the rows of the queries having the same values in the columns.
+func JoinByColumnValue(columns ...string) common.Transform {
+	return common.Transform{
+		Kind: common.JoinByColumValueKind,
+		Spec: &common.JoinByColumnValueSpec{Columns: columns},
+	}
+}
+
+// MergeColumns merges the columns into a single column called name.
+func MergeColumns(name string, columns ...string) common.Transform {
+	return common.Transform{
+		Kind: common.MergeByColumnsKind,
+		Spec: &common.MergeColumnsSpec{Columns: columns, Name: name},
+	}
+}
+
+// MergeIndexedColumns merges the columns indexed by query, e.g. "value #1", "value #2"..., into the column.
+func MergeIndexedColumns(column string) common.Transform {
+	return common.Transform{
+		Kind: common.MergeIndexedColumnsKind,
+		Spec: &common.MergeIndexedColumnsSpec{Column: column},
+	}
+}
+
+// MergeSeries merges the series of all the queries into the same rows.
+func MergeSeries() common.Transform {
+	return common.Transform{
+		Kind: common.MergeSeriesKind,
+		Spec: &common.MergeSeriesSpec{},
+	}
+}
+
+// FilterRows keeps the rows whose value in the column matches the condition.
+func FilterRows(column string, condition Condition) common.Transform {
+	return common.Transform{
+		Kind: FilterRowsKind,
+		Spec: &FilterRowsSpec{Column: column, Condition: condition},
+	}
+}
+
+// ExcludeRows removes the rows whose value in the column matches the condition.
+func ExcludeRows(column string, condition Condition) common.Transform {
+	return common.Transform{
+		Kind: FilterRowsKind,
+		Spec: &FilterRowsSpec{Column: column, Condition: condition, Exclude: true},
+	}
+}
+
+// GroupBy merges the rows having the same values in the columns, keeping these columns and the aggregations.
+func GroupBy(columns []string, aggregations ...GroupByAggregation) common.Transform {
+	return common.Transform{
+		Kind: GroupByKind,
+		Spec: &GroupBySpec{Columns: columns, Aggregations: aggregations},
+	}
+}
+
+// Aggregate aggregates the values of the column in a column of the same name.
+func Aggregate(column string, aggregation Aggregation) GroupByAggregation {
+	return GroupByAggregation{Column: column, Aggregation: aggregation}
+}
+
+// AggregateAs aggregates the values of the column in the column name.
+func AggregateAs(name string, column string, aggregation Aggregation) GroupByAggregation {
+	return GroupByAggregation{Column: column, Aggregation: aggregation, Name: name}
+}
+
+// ComputedColumn adds the column name holding the result of the operation.
+func ComputedColumn(name string, left Operand, operator Operator, right Operand) common.Transform {
+	return common.Transform{
+		Kind: ComputedColumnKind,
+		Spec: &ComputedColumnSpec{Name: name, Left: left, Operator: operator, Right: right},
+	}
+}
+
+func ColumnOperand(column string) Operand {
+	return Operand{Column: column}
+}
+
+func ValueOperand(value float64) Operand {
+	return Operand{Value: &value}
+}
+
+// DisableTransform returns the transform disabled, kept in the panel but not applied.
+func DisableTransform(transform common.Transform) common.Transform {
+	switch spec := transformSpec(transform.Spec).(type) {
+	case *common.JoinByColumnValueSpec:
+		disabled := *spec
+		disabled.Disabled = true
+		transform.Spec = &disabled
+	case *common.MergeColumnsSpec:
+		disabled := *spec
+		disabled.Disabled = true
+		transform.Spec = &disabled
+	case *common.MergeIndexedColumnsSpec:
+		disabled := *spec
+		disabled.Disabled = true
+		transform.Spec = &disabled
+	case *common.MergeSeriesSpec:
+		transform.Spec = &common.MergeSeriesSpec{Disabled: true}
+	case *FilterRowsSpec:
+		disabled := *spec
+		disabled.Disabled = true
+		transform.Spec = &disabled
+	case *GroupBySpec:
+		disabled := *spec
+		disabled.Disabled = true
+		transform.Spec = &disabled
+	case *ComputedColumnSpec:
+		disabled := *spec
+		disabled.Disabled = true
+		transform.Spec = &disabled
+	}
+	return transform
+}
+
+// RenameColumn sets the header of the column, adding its settings if it has none.
+func RenameColumn(name string, header string) Option {
+	return func(builder *Builder) error {
+		for i := range builder.ColumnSettings {
+			if builder.ColumnSettings[i].Name == name {
+				builder.ColumnSettings[i].Header = header
+				return nil
+			}
+		}
+		builder.ColumnSettings = append(builder.ColumnSettings, ColumnSettings{Name: name, Header: header})
+		return nil
+	}
+}
+
+// OrderColumns displays the columns first, in this order, followed by the other configured columns. The columns
+// without settings get one.
+func OrderColumns(names ...string) Option {
+	return func(builder *Builder) error {
+		ordered := make([]ColumnSettings, 0, len(builder.ColumnSettings)+len(names))
+		for _, name := range names {
+			if slices.ContainsFunc(ordered, func(settings ColumnSettings) bool { return settings.Name == name }) {
+				return fmt.Errorf("column %q is ordered more than once", name)
+			}
+			index := slices.IndexFunc(builder.ColumnSettings, func(settings ColumnSettings) bool { return settings.Name == name })
+			if index < 0 {
+				ordered = append(ordered, ColumnSettings{Name: name})
+				continue
+			}
+			ordered = append(ordered, builder.ColumnSettings[index])
+		}
+		for _, settings := range builder.ColumnSettings {
+			if !slices.Contains(names, settings.Name) {
+				ordered = append(ordered, settings)
+			}
+		}
+		builder.ColumnSettings = ordered
+		return nil
+	}
+}
+
+// GaugeCellSpec is the spec of the GaugeChart rendering the cells of a column.
+type GaugeCellSpec struct {
+	Calculation common.Calculation `json:"calculation" yaml:"calculation"`
+	Format      *common.Format     `json:"format,omitempty" yaml:"format,omitempty"`
+	Thresholds  *common.Thresholds `json:"thresholds,omitempty" yaml:"thresholds,omitempty"`
+	Max         float64            `json:"max,omitempty" yaml:"max,omitempty"`
+}
+
+type Sparkline struct {
+	Color string  `json:"color,omitempty" yaml:"color,omitempty"`
+	Width float64 `json:"width,omitempty" yaml:"width,omitempty"`
+}
+
+// StatCellSpec is the spec of the StatChart rendering the cells of a column.
+type StatCellSpec struct {
+	Calculation   common.Calculation `json:"calculation" yaml:"calculation"`
+	Format        *common.Format     `json:"format,omitempty" yaml:"format,omitempty"`
+	Thresholds    *common.Thresholds `json:"thresholds,omitempty" yaml:"thresholds,omitempty"`
+	Sparkline     *Sparkline         `json:"sparkline,omitempty" yaml:"sparkline,omitempty"`
+	ValueFontSize int                `json:"valueFontSize,omitempty" yaml:"valueFontSize,omitempty"`
+}
+
+// CellPlugin renders the cells of a column with any panel plugin, the value of a cell being its data.
+func CellPlugin(kind string, spec any) *persesCommon.Plugin {
+	return &persesCommon.Plugin{Kind: kind, Spec: spec}
+}
+
+// GaugeCell renders the cells of a column as gauges, the range being shared by the cells of the column. The last
+// value is displayed when no calculation is set.
+func GaugeCell(spec GaugeCellSpec) *persesCommon.Plugin {
+	if len(spec.Calculation) == 0 {
+		spec.Calculation = common.LastCalculation
+	}
+	return CellPlugin("GaugeChart", spec)
+}
+
+// StatCell renders the cells of a column as stats. The last value is displayed when no calculation is set.
+func StatCell(spec StatCellSpec) *persesCommon.Plugin {
+	if len(spec.Calculation) == 0 {
+		spec.Calculation = common.LastCalculation
+	}
+	return CellPlugin("StatChart", spec)
+}
+
+// SparklineCell renders the cells of a column as stats with a sparkline of the values of the cell over the time range.
+func SparklineCell(spec StatCellSpec, sparkline Sparkline) *persesCommon.Plugin {
+	spec.Sparkline = &sparkline
+	return StatCell(spec)
+}
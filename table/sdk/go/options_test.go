@@ -0,0 +1,428 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/perses/perses/go-sdk/common"
+	"gopkg.in/yaml.v3"
+)
+
+func TestAddTransform(t *testing.T) {
+	builder, err := create(
+		WithColumnSettings([]ColumnSettings{{Name: "instance"}, {Name: "value"}}),
+		AddTransform(JoinByColumnValue("instance")),
+		AddTransform(DisableTransform(MergeIndexedColumns("value"))),
+		AddTransform(MergeSeries()),
+	)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+
+	jsonBytes, err := json.Marshal(builder.Transforms)
+	if err != nil {
+		t.Fatalf("Failed to marshal transforms: %v", err)
+	}
+	expected := `[{"kind":"JoinByColumnValue","spec":{"columns":["instance"]}},` +
+		`{"kind":"MergeIndexedColumns","spec":{"column":"value","disabled":true}},` +
+		`{"kind":"MergeSeries","spec":{}}]`
+	if string(jsonBytes) != expected {
+		t.Errorf("Expected %s, got %s", expected, jsonBytes)
+	}
+
+	var transforms []common.Transform
+	if err := json.Unmarshal(jsonBytes, &transforms); err != nil {
+		t.Fatalf("Failed to unmarshal transforms: %v", err)
+	}
+	if _, err := create(WithColumnSettings(builder.ColumnSettings), Transform(transforms)); err != nil {
+		t.Errorf("the unmarshalled transforms must be valid: %v", err)
+	}
+}
+
+func TestAddTransform_TableTransforms(t *testing.T) {
+	builder, err := create(
+		WithColumnSettings([]ColumnSettings{{Name: "job"}, {Name: "value #1"}, {Name: "value #2"}, {Name: "ratio"}, {Name: "instances"}}),
+		AddTransform(ComputedColumn("ratio", ColumnOperand("value #1"), DivideOperator, ColumnOperand("value #2"))),
+		AddTransform(ExcludeRows("job", Condition{Kind: ValueConditionKind, Spec: &ValueConditionSpec{Value: "test"}})),
+		AddTransform(DisableTransform(FilterRows("ratio", Condition{Kind: MiscConditionKind, Spec: &MiscConditionSpec{Value: NaNValue}}))),
+		AddTransform(GroupBy([]string{"job"}, Aggregate("ratio", MaxAggregation), AggregateAs("instances", "value #1", CountAggregation))),
+		AddTransform(ComputedColumn("ratio", ColumnOperand("ratio"), MultiplyOperator, ValueOperand(100))),
+	)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+
+	jsonBytes, err := json.Marshal(builder.Transforms)
+	if err != nil {
+		t.Fatalf("Failed to marshal transforms: %v", err)
+	}
+	expected := `[{"kind":"ComputedColumn","spec":{"name":"ratio","left":{"column":"value #1"},"operator":"/","right":{"column":"value #2"}}},` +
+		`{"kind":"FilterRows","spec":{"column":"job","condition":{"kind":"Value","spec":{"value":"test"}},"exclude":true}},` +
+		`{"kind":"FilterRows","spec":{"column":"ratio","condition":{"kind":"Misc","spec":{"value":"NaN"}},"disabled":true}},` +
+		`{"kind":"GroupBy","spec":{"columns":["job"],"aggregations":[{"column":"ratio","aggregation":"max"},{"column":"value #1","aggregation":"count","name":"instances"}]}},` +
+		`{"kind":"ComputedColumn","spec":{"name":"ratio","left":{"column":"ratio"},"operator":"*","right":{"value":100}}}]`
+	if string(jsonBytes) != expected {
+		t.Errorf("Expected %s, got %s", expected, jsonBytes)
+	}
+
+	var transforms Transforms
+	if err := json.Unmarshal(jsonBytes, &transforms); err != nil {
+		t.Fatalf("Failed to unmarshal transforms: %v", err)
+	}
+	if _, err := create(WithColumnSettings(builder.ColumnSettings), Transform(transforms)); err != nil {
+		t.Errorf("the unmarshalled transforms must be valid: %v", err)
+	}
+
+	yamlBytes, err := yaml.Marshal(builder.PluginSpec)
+	if err != nil {
+		t.Fatalf("Failed to marshal the spec: %v", err)
+	}
+	var spec PluginSpec
+	if err := yaml.Unmarshal(yamlBytes, &spec); err != nil {
+		t.Fatalf("Failed to unmarshal the spec: %v", err)
+	}
+	if len(spec.Transforms) != len(builder.Transforms) {
+		t.Fatalf("Expected %d transforms, got %d", len(builder.Transforms), len(spec.Transforms))
+	}
+	if groupBy, ok := spec.Transforms[3].Spec.(*GroupBySpec); !ok || groupBy.Aggregations[1].Name != "instances" {
+		t.Errorf("Expected the GroupBy transform to be unmarshalled, got %#v", spec.Transforms[3].Spec)
+	}
+	if err := spec.validateTransforms(); err != nil {
+		t.Errorf("the unmarshalled transforms must be valid: %v", err)
+	}
+
+	if err := json.Unmarshal([]byte(`[{"kind":"SortRows","spec":{}}]`), &transforms); err == nil {
+		t.Error("Expected an error for an unknown transform, got nil")
+	}
+}
+
+func TestAddTransform_Invalid(t *testing.T) {
+	testSuites := []struct {
+		title   string
+		options []Option
+		err     string
+	}{
+		{
+			title:   "join without columns",
+			options: []Option{AddTransform(JoinByColumnValue())},
+			err:     "at least one column",
+		},
+		{
+			title:   "merge a single column",
+			options: []Option{AddTransform(MergeColumns("address", "ip"))},
+			err:     "at least two columns",
+		},
+		{
+			title:   "empty column",
+			options: []Option{AddTransform(MergeIndexedColumns(""))},
+			err:     "cannot be empty",
+		},
+		{
+			title:   "spec of another kind",
+			options: []Option{AddTransform(common.Transform{Kind: common.MergeSeriesKind, Spec: common.JoinByColumnValueSpec{Columns: []string{"instance"}}})},
+			err:     `cannot be used with the kind "MergeSeries"`,
+		},
+		{
+			title:   "typo in a joined column without column settings",
+			options: []Option{AddTransform(JoinByColumnValue("instnace"))},
+			err:     `transforms[0]: column "instnace" is not defined in columnSettings`,
+		},
+		{
+			title:   "filter with an invalid condition",
+			options: []Option{AddTransform(FilterRows("instance", Condition{Kind: RegexConditionKind, Spec: &RegexConditionSpec{Expr: "("}}))},
+			err:     "not a valid JavaScript regex",
+		},
+		{
+			title:   "group by without columns",
+			options: []Option{AddTransform(GroupBy(nil, Aggregate("value", SumAggregation)))},
+			err:     "at least one column",
+		},
+		{
+			title:   "unknown aggregation",
+			options: []Option{AddTransform(GroupBy([]string{"job"}, Aggregate("value", "median")))},
+			err:     `unknown aggregation "median" of the column "value"`,
+		},
+		{
+			title:   "column aggregated twice in the same column",
+			options: []Option{AddTransform(GroupBy([]string{"job"}, Aggregate("value", SumAggregation), Aggregate("value", MaxAggregation)))},
+			err:     `the column "value" is created more than once`,
+		},
+		{
+			title:   "unknown operator",
+			options: []Option{AddTransform(ComputedColumn("ratio", ColumnOperand("value #1"), "%", ColumnOperand("value #2")))},
+			err:     `unknown operator "%"`,
+		},
+		{
+			title:   "operand without column nor value",
+			options: []Option{AddTransform(ComputedColumn("ratio", ColumnOperand("value #1"), DivideOperator, Operand{}))},
+			err:     "an operand must be either a column or a value",
+		},
+		{
+			title: "typo in an aggregated column",
+			options: []Option{
+				WithColumnSettings([]ColumnSettings{{Name: "job"}, {Name: "value"}}),
+				AddTransform(GroupBy([]string{"job"}, AggregateAs("value", "valeu", SumAggregation))),
+			},
+			err: `transforms[0]: column "valeu" is not defined in columnSettings`,
+		},
+		{
+			title: "typo in a joined column",
+			options: []Option{
+				WithColumnSettings([]ColumnSettings{{Name: "instance"}, {Name: "value"}}),
+				AddTransform(JoinByColumnValue("instnace")),
+			},
+			err: `transforms[0]: column "instnace" is not defined in columnSettings`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			_, err := create(test.options...)
+			if err == nil {
+				t.Fatal("Expected an error, got nil")
+			}
+			if !strings.Contains(err.Error(), test.err) {
+				t.Errorf("Expected an error containing %q, got %q", test.err, err)
+			}
+		})
+	}
+}
+
+func TestRenameAndOrderColumns(t *testing.T) {
+	builder, err := create(
+		WithColumnSettings([]ColumnSettings{{Name: "job"}, {Name: "value", Align: RightAlign}}),
+		RenameColumn("value", "CPU"),
+		RenameColumn("instance", "Instance"),
+		OrderColumns("instance", "value"),
+	)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	expected := []ColumnSettings{
+		{Name: "instance", Header: "Instance"},
+		{Name: "value", Header: "CPU", Align: RightAlign},
+		{Name: "job"},
+	}
+	if len(builder.ColumnSettings) != len(expected) {
+		t.Fatalf("Expected %d columns, got %d", len(expected), len(builder.ColumnSettings))
+	}
+	for i := range expected {
+		got := builder.ColumnSettings[i]
+		if got.Name != expected[i].Name || got.Header != expected[i].Header || got.Align != expected[i].Align {
+			t.Errorf("Expected column %d to be %+v, got %+v", i, expected[i], got)
+		}
+	}
+
+	if _, err := create(OrderColumns("value", "value")); err == nil {
+		t.Error("Expected an error for a column ordered twice, got nil")
+	}
+}
+
+func TestSize(t *testing.T) {
+	builder, err := create(
+		WithDefaultColumnWidth(AutoSize()),
+		WithDefaultColumHeight(40),
+		WithColumnSettings([]ColumnSettings{{Name: "value", Width: PixelSize(120.5)}}),
+	)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	jsonBytes, err := json.Marshal(builder)
+	if err != nil {
+		t.Fatalf("Failed to marshal builder: %v", err)
+	}
+	expected := `{"defaultColumnWidth":"auto","defaultColumnHeight":40,"columnSettings":[{"name":"value","width":120.5}]}`
+	if string(jsonBytes) != expected {
+		t.Errorf("Expected %s, got %s", expected, jsonBytes)
+	}
+
+	var spec PluginSpec
+	if err := json.Unmarshal(jsonBytes, &spec); err != nil {
+		t.Fatalf("Failed to unmarshal spec: %v", err)
+	}
+	if !spec.DefaultColumnWidth.IsAuto() || spec.DefaultColumnHeight.Pixels() != 40 || spec.ColumnSettings[0].Width.Pixels() != 120.5 {
+		t.Errorf("Unexpected sizes after a JSON round trip: %s", jsonBytes)
+	}
+
+	var yamlSpec PluginSpec
+	if err := yaml.Unmarshal([]byte("defaultColumnWidth: 200\ndefaultColumnHeight: auto\n"), &yamlSpec); err != nil {
+		t.Fatalf("Failed to unmarshal YAML spec: %v", err)
+	}
+	if yamlSpec.DefaultColumnWidth.Pixels() != 200 || !yamlSpec.DefaultColumnHeight.IsAuto() {
+		t.Errorf("Unexpected sizes after unmarshalling YAML: %+v, %+v", yamlSpec.DefaultColumnWidth, yamlSpec.DefaultColumnHeight)
+	}
+	yamlBytes, err := yaml.Marshal(yamlSpec)
+	if err != nil {
+		t.Fatalf("Failed to marshal YAML spec: %v", err)
+	}
+	if string(yamlBytes) != "defaultColumnWidth: 200\ndefaultColumnHeight: auto\n" {
+		t.Errorf("Unexpected YAML: %s", yamlBytes)
+	}
+
+	if err := json.Unmarshal([]byte(`{"defaultColumnWidth":"large"}`), &spec); err == nil {
+		t.Error("Expected an error for an invalid size, got nil")
+	}
+}
+
+func TestColumnPlugin(t *testing.T) {
+	builder, err := create(WithColumnSettings([]ColumnSettings{
+		{Name: "usage", Plugin: GaugeCell(GaugeCellSpec{Max: 100})},
+		{Name: "trend", Plugin: SparklineCell(StatCellSpec{Calculation: common.MeanCalculation}, Sparkline{Width: 1})},
+	}))
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	jsonBytes, err := json.Marshal(builder.ColumnSettings)
+	if err != nil {
+		t.Fatalf("Failed to marshal column settings: %v", err)
+	}
+	expected := `[{"name":"usage","plugin":{"kind":"GaugeChart","spec":{"calculation":"last","max":100}}},` +
+		`{"name":"trend","plugin":{"kind":"StatChart","spec":{"calculation":"mean","sparkline":{"width":1}}}}]`
+	if string(jsonBytes) != expected {
+		t.Errorf("Expected %s, got %s", expected, jsonBytes)
+	}
+}
+
+func TestCellSettings_Invalid(t *testing.T) {
+	testSuites := []struct {
+		title   string
+		options []Option
+		err     string
+	}{
+		{
+			title: "invalid text color",
+			options: []Option{WithCellSettings([]CellSettings{{
+				Condition: Condition{Kind: ValueConditionKind, Spec: &ValueConditionSpec{Value: "up"}},
+				TextColor: "green",
+			}})},
+			err: `cellSettings[0]: textColor "green" is not a hexadecimal color code`,
+		},
+		{
+			title: "max lower than min",
+			options: []Option{WithColumnSettings([]ColumnSettings{{
+				Name: "value",
+				CellSettings: []CellSettings{{
+					Condition:       Condition{Kind: RangeConditionKind, Spec: &RangeConditionSpec{Min: 10, Max: 5}},
+					BackgroundColor: "#ff0000",
+				}},
+			}})},
+			err: `columnSettings[0] ("value"): cellSettings[0]: Range condition: max (5) must be greater than or equal to min (10)`,
+		},
+		{
+			title:   "negative width",
+			options: []Option{WithColumnSettings([]ColumnSettings{{Name: "value", Width: PixelSize(-1)}})},
+			err:     `columnSettings[0] ("value"): width: size cannot be negative`,
+		},
+		{
+			title: "invalid regex",
+			options: []Option{WithColumnSettings([]ColumnSettings{{
+				Name: "instance",
+				CellSettings: []CellSettings{
+					{Condition: Condition{Kind: RegexConditionKind, Spec: RegexConditionSpec{Expr: "^node-\\d+$"}}},
+					{Condition: Condition{Kind: RegexConditionKind, Spec: &RegexConditionSpec{Expr: "node-(\\d+"}}},
+				},
+			}})},
+			err: `columnSettings[0] ("instance"): cellSettings[1]: Regex condition: expr "node-(\\d+" is not a valid JavaScript regex`,
+		},
+		{
+			title: "unknown misc value",
+			options: []Option{WithCellSettings([]CellSettings{
+				{Condition: Condition{Kind: MiscConditionKind, Spec: &MiscConditionSpec{Value: "undefined"}}},
+			})},
+			err: `cellSettings[0]: Misc condition: unknown value "undefined"`,
+		},
+		{
+			title: "spec of another kind",
+			options: []Option{WithCellSettings([]CellSettings{
+				{Condition: Condition{Kind: RegexConditionKind, Spec: &ValueConditionSpec{Value: "up"}}},
+			})},
+			err: `cellSettings[0]: the spec of a Value condition cannot be used with the kind "Regex"`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			_, err := create(test.options...)
+			if err == nil {
+				t.Fatal("Expected an error, got nil")
+			}
+			if !strings.Contains(err.Error(), test.err) {
+				t.Errorf("Expected an error containing %q, got %q", test.err, err)
+			}
+		})
+	}
+}
+
+func TestCondition_JavaScriptRegex(t *testing.T) {
+	// lookarounds are not supported by RE2 but are by JavaScript, which evaluates the regexes in the frontend
+	_, err := create(WithCellSettings([]CellSettings{
+		{Condition: Condition{Kind: RegexConditionKind, Spec: &RegexConditionSpec{Expr: "^api(?!-canary)"}}},
+	}))
+	if err != nil {
+		t.Errorf("Expected a JavaScript regex to be valid, got %v", err)
+	}
+}
+
+func TestPluginSpec_Unmarshal(t *testing.T) {
+	testSuites := []struct {
+		title string
+		json  string
+		err   string
+	}{
+		{
+			title: "valid spec",
+			json:  `{"columnSettings":[{"name":"status","cellSettings":[{"condition":{"kind":"Value","spec":{"value":"up"}},"backgroundColor":"#00ff00"}]}]}`,
+		},
+		{
+			title: "invalid regex in a column",
+			json:  `{"columnSettings":[{"name":"env"},{"name":"instance","cellSettings":[{"condition":{"kind":"Regex","spec":{"expr":"[a-"}}}]}]}`,
+			err:   `columnSettings[1] ("instance"): cellSettings[0]: Regex condition: expr "[a-" is not a valid JavaScript regex`,
+		},
+		{
+			title: "invalid color",
+			json:  `{"cellSettings":[{"condition":{"kind":"Misc","spec":{"value":"null"}},"textColor":"red"}]}`,
+			err:   `cellSettings[0]: textColor "red" is not a hexadecimal color code`,
+		},
+		{
+			title: "unknown condition",
+			json:  `{"cellSettings":[{"condition":{"kind":"Prefix","spec":{"value":"a"}}}]}`,
+			err:   `unknown condition.kind "Prefix" used`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			var spec PluginSpec
+			err := json.Unmarshal([]byte(test.json), &spec)
+			if len(test.err) == 0 {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Expected an error, got nil")
+			}
+			if !strings.Contains(err.Error(), test.err) {
+				t.Errorf("Expected an error containing %q, got %q", test.err, err)
+			}
+		})
+	}
+
+	var spec PluginSpec
+	err := yaml.Unmarshal([]byte("cellSettings:\n- condition:\n    kind: Range\n    spec:\n      min: 3\n      max: 1\n"), &spec)
+	if err == nil || !strings.Contains(err.Error(), "cellSettings[0]: Range condition: max (1) must be greater than or equal to min (3)") {
+		t.Errorf("Expected the YAML spec to be validated, got %v", err)
+	}
+}
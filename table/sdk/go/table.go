@@ -15,10 +15,16 @@ package table
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
+	"slices"
 
+	"github.com/dlclark/regexp2"
 	"github.com/perses/perses/go-sdk/common"
 	"github.com/perses/perses/go-sdk/panel"
+	persesCommon "github.com/perses/perses/pkg/model/api/v1/common"
+	"github.com/perses/plugins/sdk/go/option"
 	"gopkg.in/yaml.v3"
 )
 
@@ -47,40 +53,167 @@ const (
 	DescSort Sort = "desc"
 )
 
+const autoSize = "auto"
+
+// Size is a width or a height, either a number of pixels or "auto" to let the table adjust it to fill the space.
+// It is built with PixelSize or AutoSize.
+type Size struct {
+	auto   bool
+	pixels float64
+}
+
+func PixelSize(pixels float64) *Size {
+	return &Size{pixels: pixels}
+}
+
+func AutoSize() *Size {
+	return &Size{auto: true}
+}
+
+func (s *Size) IsAuto() bool {
+	return s.auto
+}
+
+func (s *Size) Pixels() float64 {
+	return s.pixels
+}
+
+func (s *Size) value() any {
+	if s.auto {
+		return autoSize
+	}
+	return s.pixels
+}
+
+func (s *Size) validate() error {
+	if !s.auto && s.pixels < 0 {
+		return fmt.Errorf("size cannot be negative, got %g", s.pixels)
+	}
+	return nil
+}
+
+func (s *Size) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.value())
+}
+
+func (s *Size) MarshalYAML() (interface{}, error) {
+	return s.value(), nil
+}
+
+func (s *Size) UnmarshalJSON(data []byte) error {
+	var tmp interface{}
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return err
+	}
+	return s.unmarshal(tmp)
+}
+
+func (s *Size) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var tmp interface{}
+	if err := unmarshal(&tmp); err != nil {
+		return err
+	}
+	return s.unmarshal(tmp)
+}
+
+func (s *Size) unmarshal(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		if v != autoSize {
+			return fmt.Errorf("invalid size %q, it must be a number or %q", v, autoSize)
+		}
+		*s = Size{auto: true}
+	case float64:
+		*s = Size{pixels: v}
+	case int:
+		*s = Size{pixels: float64(v)}
+	default:
+		return fmt.Errorf("invalid size %v, it must be a number or %q", value, autoSize)
+	}
+	return s.validate()
+}
+
 type DataLink struct {
 	URL        string `json:"url" yaml:"url"`
 	Title      string `json:"title,omitempty" yaml:"title,omitempty"`
 	OpenNewTab bool   `json:"openNewTab" yaml:"openNewTab"`
 }
 
+// ColumnSettings configures a column. Plugin is the panel plugin rendering the cells, built with GaugeCell, StatCell,
+// SparklineCell or CellPlugin, the cells being rendered as text by default.
 type ColumnSettings struct {
-	Name              string         `json:"name" yaml:"name"`
-	Header            string         `json:"header,omitempty" yaml:"header,omitempty"`
-	HeaderDescription string         `json:"headerDescription,omitempty" yaml:"headerDescription,omitempty"`
-	CellDescription   string         `json:"cellDescription,omitempty" yaml:"cellDescription,omitempty"`
-	Format            *common.Format `json:"format,omitempty" yaml:"format,omitempty"`
-	Align             Align          `json:"align,omitempty" yaml:"align,omitempty"`
-	EnableSorting     bool           `json:"enableSorting,omitempty" yaml:"enableSorting,omitempty"`
-	Sort              Sort           `json:"sort,omitempty" yaml:"sort,omitempty"`
-	Width             float64        `json:"width,omitempty" yaml:"width,omitempty"`
-	Hide              bool           `json:"hide,omitempty" yaml:"hide,omitempty"`
-	CellSettings      []CellSettings `json:"cellSettings,omitempty" yaml:"cellSettings,omitempty"`
-	DataLink          *DataLink      `json:"dataLink,omitempty" yaml:"dataLink,omitempty"`
+	Name              string               `json:"name" yaml:"name"`
+	Header            string               `json:"header,omitempty" yaml:"header,omitempty"`
+	HeaderDescription string               `json:"headerDescription,omitempty" yaml:"headerDescription,omitempty"`
+	CellDescription   string               `json:"cellDescription,omitempty" yaml:"cellDescription,omitempty"`
+	Plugin            *persesCommon.Plugin `json:"plugin,omitempty" yaml:"plugin,omitempty"`
+	Format            *common.Format       `json:"format,omitempty" yaml:"format,omitempty"`
+	Align             Align                `json:"align,omitempty" yaml:"align,omitempty"`
+	EnableSorting     bool                 `json:"enableSorting,omitempty" yaml:"enableSorting,omitempty"`
+	Sort              Sort                 `json:"sort,omitempty" yaml:"sort,omitempty"`
+	Width             *Size                `json:"width,omitempty" yaml:"width,omitempty"`
+	Hide              bool                 `json:"hide,omitempty" yaml:"hide,omitempty"`
+	CellSettings      []CellSettings       `json:"cellSettings,omitempty" yaml:"cellSettings,omitempty"`
+	DataLink          *DataLink            `json:"dataLink,omitempty" yaml:"dataLink,omitempty"`
+}
+
+func (c *ColumnSettings) validate() error {
+	var errs []error
+	if len(c.Name) == 0 {
+		errs = append(errs, fmt.Errorf("name cannot be empty"))
+	}
+	if c.Plugin != nil && len(c.Plugin.Kind) == 0 {
+		errs = append(errs, fmt.Errorf("the kind of the plugin rendering the cells cannot be empty"))
+	}
+	if c.Width != nil {
+		if err := c.Width.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("width: %w", err))
+		}
+	}
+	errs = append(errs, validateCellSettings(c.CellSettings))
+	return errors.Join(errs...)
 }
 
 type ValueConditionSpec struct {
 	Value string `json:"value" yaml:"value"`
 }
 
+func (v *ValueConditionSpec) validate() error {
+	if len(v.Value) == 0 {
+		return fmt.Errorf("value cannot be empty")
+	}
+	return nil
+}
+
+// RangeConditionSpec matches the values between Min and Max. A zero Min or Max is not set, the range being open.
 type RangeConditionSpec struct {
 	Min float64 `json:"min,omitempty" yaml:"min,omitempty"`
 	Max float64 `json:"max,omitempty" yaml:"max,omitempty"`
 }
 
+func (r *RangeConditionSpec) validate() error {
+	if r.Max != 0 && r.Max < r.Min {
+		return fmt.Errorf("max (%g) must be greater than or equal to min (%g)", r.Max, r.Min)
+	}
+	return nil
+}
+
+// RegexConditionSpec matches the values matching Expr. The frontend evaluates it as a JavaScript regex, so Expr is
+// validated with the ECMAScript semantics rather than the RE2 ones of the regexp package.
 type RegexConditionSpec struct {
 	Expr string `json:"expr" yaml:"expr"`
 }
 
+func (r *RegexConditionSpec) validate() error {
+	if len(r.Expr) == 0 {
+		return fmt.Errorf("expr cannot be empty")
+	}
+	if _, err := regexp2.Compile(r.Expr, regexp2.ECMAScript); err != nil {
+		return fmt.Errorf("expr %q is not a valid JavaScript regex: %w", r.Expr, err)
+	}
+	return nil
+}
+
 type MiscValue string
 
 var (
@@ -95,6 +228,15 @@ type MiscConditionSpec struct {
 	Value MiscValue `json:"value" yaml:"value"`
 }
 
+func (m *MiscConditionSpec) validate() error {
+	switch m.Value {
+	case EmptyValue, NullValue, NaNValue, TrueValue, FalseValue:
+		return nil
+	default:
+		return fmt.Errorf("unknown value %q, it must be one of %q, %q, %q, %q or %q", m.Value, EmptyValue, NullValue, NaNValue, TrueValue, FalseValue)
+	}
+}
+
 type ConditionKind string
 
 const (
@@ -141,7 +283,7 @@ func (c *Condition) unmarshal(unmarshal func(interface{}) error, staticMarshal f
 	case MiscConditionKind:
 		spec = &MiscConditionSpec{}
 	default:
-		return fmt.Errorf("unknown transform.kind %q used", tmp.Kind)
+		return fmt.Errorf("unknown condition.kind %q used", tmp.Kind)
 	}
 	if unMarshalErr := staticUnmarshal(rawSpec, spec); unMarshalErr != nil {
 		return unMarshalErr
@@ -151,6 +293,40 @@ func (c *Condition) unmarshal(unmarshal func(interface{}) error, staticMarshal f
 	return nil
 }
 
+// validate checks that the spec matches the kind. The specs are pointers when unmarshalled but can be values when built
+// by hand.
+func (c *Condition) validate() error {
+	var kind ConditionKind
+	var err error
+	switch spec := c.Spec.(type) {
+	case *ValueConditionSpec:
+		kind, err = ValueConditionKind, spec.validate()
+	case ValueConditionSpec:
+		kind, err = ValueConditionKind, spec.validate()
+	case *RangeConditionSpec:
+		kind, err = RangeConditionKind, spec.validate()
+	case RangeConditionSpec:
+		kind, err = RangeConditionKind, spec.validate()
+	case *RegexConditionSpec:
+		kind, err = RegexConditionKind, spec.validate()
+	case RegexConditionSpec:
+		kind, err = RegexConditionKind, spec.validate()
+	case *MiscConditionSpec:
+		kind, err = MiscConditionKind, spec.validate()
+	case MiscConditionSpec:
+		kind, err = MiscConditionKind, spec.validate()
+	default:
+		return fmt.Errorf("unknown spec %T for the condition %q", c.Spec, c.Kind)
+	}
+	if c.Kind != kind {
+		return fmt.Errorf("the spec of a %s condition cannot be used with the kind %q", kind, c.Kind)
+	}
+	if err != nil {
+		return fmt.Errorf("%s condition: %w", kind, err)
+	}
+	return nil
+}
+
 type CellSettings struct {
 	Condition       Condition `json:"condition" yaml:"condition"`
 	Text            string    `json:"text,omitempty" yaml:"text,omitempty"`
@@ -160,16 +336,352 @@ type CellSettings struct {
 	BackgroundColor string    `json:"backgroundColor,omitempty" yaml:"backgroundColor,omitempty"`
 }
 
+var colorRegexp = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}){1,2}$`)
+
+func (c *CellSettings) validate() error {
+	if len(c.TextColor) > 0 && !colorRegexp.MatchString(c.TextColor) {
+		return fmt.Errorf("textColor %q is not a hexadecimal color code", c.TextColor)
+	}
+	if len(c.BackgroundColor) > 0 && !colorRegexp.MatchString(c.BackgroundColor) {
+		return fmt.Errorf("backgroundColor %q is not a hexadecimal color code", c.BackgroundColor)
+	}
+	return c.Condition.validate()
+}
+
+func validateCellSettings(cellSettings []CellSettings) error {
+	var errs []error
+	for i := range cellSettings {
+		if err := cellSettings[i].validate(); err != nil {
+			errs = append(errs, fmt.Errorf("cellSettings[%d]: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 type PluginSpec struct {
-	Density             Density            `json:"density,omitempty" yaml:"density,omitempty"`
-	DefaultColumnWidth  int                `json:"defaultColumnWidth,omitempty" yaml:"defaultColumnWidth,omitempty"`
-	DefaultColumnHeight int                `json:"defaultColumnHeight,omitempty" yaml:"defaultColumnHeight,omitempty"`
-	DefaultColumnHidden bool               `json:"defaultColumnHidden,omitempty" yaml:"defaultColumnHidden,omitempty"`
-	Pagination          bool               `json:"pagination,omitempty" yaml:"pagination,omitempty"`
-	EnableFiltering     bool               `json:"enableFiltering,omitempty" yaml:"enableFiltering,omitempty"`
-	ColumnSettings      []ColumnSettings   `json:"columnSettings,omitempty" yaml:"columnSettings,omitempty"`
-	CellSettings        []CellSettings     `json:"cellSettings,omitempty" yaml:"cellSettings,omitempty"`
-	Transforms          []common.Transform `json:"transforms,omitempty" yaml:"transforms,omitempty"`
+	Density             Density          `json:"density,omitempty" yaml:"density,omitempty"`
+	DefaultColumnWidth  *Size            `json:"defaultColumnWidth,omitempty" yaml:"defaultColumnWidth,omitempty"`
+	DefaultColumnHeight *Size            `json:"defaultColumnHeight,omitempty" yaml:"defaultColumnHeight,omitempty"`
+	DefaultColumnHidden bool             `json:"defaultColumnHidden,omitempty" yaml:"defaultColumnHidden,omitempty"`
+	Pagination          bool             `json:"pagination,omitempty" yaml:"pagination,omitempty"`
+	EnableFiltering     bool             `json:"enableFiltering,omitempty" yaml:"enableFiltering,omitempty"`
+	ColumnSettings      []ColumnSettings `json:"columnSettings,omitempty" yaml:"columnSettings,omitempty"`
+	CellSettings        []CellSettings   `json:"cellSettings,omitempty" yaml:"cellSettings,omitempty"`
+	Transforms          Transforms       `json:"transforms,omitempty" yaml:"transforms,omitempty"`
+}
+
+// The transforms specific to the table, applied by the panel after or between the Perses ones.
+const (
+	FilterRowsKind     common.TransformKind = "FilterRows"
+	GroupByKind        common.TransformKind = "GroupBy"
+	ComputedColumnKind common.TransformKind = "ComputedColumn"
+)
+
+// FilterRowsSpec keeps the rows whose value in Column matches the condition, or removes them when Exclude is set.
+type FilterRowsSpec struct {
+	Column    string    `json:"column" yaml:"column"`
+	Condition Condition `json:"condition" yaml:"condition"`
+	Exclude   bool      `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+	Disabled  bool      `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+}
+
+type Aggregation string
+
+const (
+	SumAggregation   Aggregation = "sum"
+	MeanAggregation  Aggregation = "mean"
+	MinAggregation   Aggregation = "min"
+	MaxAggregation   Aggregation = "max"
+	CountAggregation Aggregation = "count"
+	FirstAggregation Aggregation = "first"
+	LastAggregation  Aggregation = "last"
+)
+
+// GroupByAggregation aggregates the values of Column in the column Name, the name of the aggregated column by default.
+type GroupByAggregation struct {
+	Column      string      `json:"column" yaml:"column"`
+	Aggregation Aggregation `json:"aggregation" yaml:"aggregation"`
+	Name        string      `json:"name,omitempty" yaml:"name,omitempty"`
+}
+
+func (g *GroupByAggregation) name() string {
+	if len(g.Name) > 0 {
+		return g.Name
+	}
+	return g.Column
+}
+
+// GroupBySpec merges the rows having the same values in Columns into a row made of these columns and of the
+// aggregations, the other columns being dropped.
+type GroupBySpec struct {
+	Columns      []string             `json:"columns" yaml:"columns"`
+	Aggregations []GroupByAggregation `json:"aggregations,omitempty" yaml:"aggregations,omitempty"`
+	Disabled     bool                 `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+}
+
+type Operator string
+
+const (
+	AddOperator      Operator = "+"
+	SubtractOperator Operator = "-"
+	MultiplyOperator Operator = "*"
+	DivideOperator   Operator = "/"
+)
+
+// Operand is either the value of a column or a number, built with ColumnOperand or ValueOperand.
+type Operand struct {
+	Column string   `json:"column,omitempty" yaml:"column,omitempty"`
+	Value  *float64 `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+func (o *Operand) validate() error {
+	if (len(o.Column) == 0) == (o.Value == nil) {
+		return fmt.Errorf("an operand must be either a column or a value")
+	}
+	return nil
+}
+
+// ComputedColumnSpec adds the column Name holding the result of the operation, the rows missing an operand having no
+// value.
+type ComputedColumnSpec struct {
+	Name     string   `json:"name" yaml:"name"`
+	Left     Operand  `json:"left" yaml:"left"`
+	Operator Operator `json:"operator" yaml:"operator"`
+	Right    Operand  `json:"right" yaml:"right"`
+	Disabled bool     `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+}
+
+// Transforms are the Perses transforms and the ones specific to the table, that common.Transform cannot unmarshal.
+type Transforms []common.Transform
+
+func (t *Transforms) UnmarshalJSON(data []byte) error {
+	jsonUnmarshalFunc := func(variable interface{}) error {
+		return json.Unmarshal(data, variable)
+	}
+	return t.unmarshal(jsonUnmarshalFunc, json.Marshal, json.Unmarshal)
+}
+
+func (t *Transforms) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return t.unmarshal(unmarshal, yaml.Marshal, yaml.Unmarshal)
+}
+
+func (t *Transforms) unmarshal(unmarshal func(interface{}) error, staticMarshal func(interface{}) ([]byte, error), staticUnmarshal func([]byte, interface{}) error) error {
+	var tmp []struct {
+		Kind common.TransformKind `json:"kind" yaml:"kind"`
+		Spec interface{}          `json:"spec" yaml:"spec"`
+	}
+	if err := unmarshal(&tmp); err != nil {
+		return err
+	}
+	var transforms Transforms
+	for _, raw := range tmp {
+		var spec interface{}
+		switch raw.Kind {
+		case FilterRowsKind:
+			spec = &FilterRowsSpec{}
+		case GroupByKind:
+			spec = &GroupBySpec{}
+		case ComputedColumnKind:
+			spec = &ComputedColumnSpec{}
+		default:
+			// a Perses transform
+			rawTransform, err := staticMarshal(raw)
+			if err != nil {
+				return err
+			}
+			var transform common.Transform
+			if unMarshalErr := staticUnmarshal(rawTransform, &transform); unMarshalErr != nil {
+				return unMarshalErr
+			}
+			transforms = append(transforms, transform)
+			continue
+		}
+		rawSpec, err := staticMarshal(raw.Spec)
+		if err != nil {
+			return err
+		}
+		if unMarshalErr := staticUnmarshal(rawSpec, spec); unMarshalErr != nil {
+			return unMarshalErr
+		}
+		transforms = append(transforms, common.Transform{Kind: raw.Kind, Spec: spec})
+	}
+	*t = transforms
+	return nil
+}
+
+// transformColumns returns the columns the transform refers to: the columns it reads and the columns it creates.
+func transformColumns(transform common.Transform) ([]string, error) {
+	var kind common.TransformKind
+	var columns []string
+	switch spec := transformSpec(transform.Spec).(type) {
+	case *common.JoinByColumnValueSpec:
+		kind, columns = common.JoinByColumValueKind, spec.Columns
+		if len(spec.Columns) == 0 {
+			return nil, fmt.Errorf("a %s transform needs at least one column", kind)
+		}
+	case *common.MergeColumnsSpec:
+		kind, columns = common.MergeByColumnsKind, append(slices.Clone(spec.Columns), spec.Name)
+		if len(spec.Name) == 0 {
+			return nil, fmt.Errorf("the name of the column created by a %s transform cannot be empty", kind)
+		}
+		if len(spec.Columns) < 2 {
+			return nil, fmt.Errorf("a %s transform needs at least two columns", kind)
+		}
+	case *common.MergeIndexedColumnsSpec:
+		kind, columns = common.MergeIndexedColumnsKind, []string{spec.Column}
+	case *common.MergeSeriesSpec:
+		kind = common.MergeSeriesKind
+	case *FilterRowsSpec:
+		kind, columns = FilterRowsKind, []string{spec.Column}
+		if err := spec.Condition.validate(); err != nil {
+			return nil, fmt.Errorf("%s transform: %w", kind, err)
+		}
+	case *GroupBySpec:
+		kind, columns = GroupByKind, slices.Clone(spec.Columns)
+		if len(spec.Columns) == 0 {
+			return nil, fmt.Errorf("a %s transform needs at least one column", kind)
+		}
+		var aggregated []string
+		for _, aggregation := range spec.Aggregations {
+			switch aggregation.Aggregation {
+			case SumAggregation, MeanAggregation, MinAggregation, MaxAggregation, CountAggregation, FirstAggregation, LastAggregation:
+			default:
+				return nil, fmt.Errorf("unknown aggregation %q of the column %q", aggregation.Aggregation, aggregation.Column)
+			}
+			if slices.Contains(columns, aggregation.name()) {
+				return nil, fmt.Errorf("the column %q is created more than once by a %s transform", aggregation.name(), kind)
+			}
+			columns = append(columns, aggregation.name())
+			if !slices.Contains(aggregated, aggregation.Column) {
+				aggregated = append(aggregated, aggregation.Column)
+			}
+		}
+		for _, column := range aggregated {
+			if !slices.Contains(columns, column) {
+				columns = append(columns, column)
+			}
+		}
+	case *ComputedColumnSpec:
+		kind, columns = ComputedColumnKind, []string{spec.Name}
+		if len(spec.Name) == 0 {
+			return nil, fmt.Errorf("the name of the column created by a %s transform cannot be empty", kind)
+		}
+		switch spec.Operator {
+		case AddOperator, SubtractOperator, MultiplyOperator, DivideOperator:
+		default:
+			return nil, fmt.Errorf("unknown operator %q, it must be one of %q, %q, %q or %q", spec.Operator, AddOperator, SubtractOperator, MultiplyOperator, DivideOperator)
+		}
+		for _, operand := range []Operand{spec.Left, spec.Right} {
+			if err := operand.validate(); err != nil {
+				return nil, fmt.Errorf("%s transform: %w", kind, err)
+			}
+			if len(operand.Column) > 0 {
+				columns = append(columns, operand.Column)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown spec %T for the transform %q", transform.Spec, transform.Kind)
+	}
+	if transform.Kind != kind {
+		return nil, fmt.Errorf("the spec of a %s transform cannot be used with the kind %q", kind, transform.Kind)
+	}
+	if slices.Contains(columns, "") {
+		return nil, fmt.Errorf("the column names of a %s transform cannot be empty", kind)
+	}
+	return columns, nil
+}
+
+// transformSpec returns a pointer to the spec, the specs being pointers when unmarshalled but possibly values when
+// built by hand.
+func transformSpec(spec any) any {
+	switch s := spec.(type) {
+	case common.JoinByColumnValueSpec:
+		return &s
+	case common.MergeColumnsSpec:
+		return &s
+	case common.MergeIndexedColumnsSpec:
+		return &s
+	case common.MergeSeriesSpec:
+		return &s
+	case FilterRowsSpec:
+		return &s
+	case GroupBySpec:
+		return &s
+	case ComputedColumnSpec:
+		return &s
+	default:
+		return spec
+	}
+}
+
+// validateTransforms checks the transforms and that the columns they refer to are configured, so that a typo in a
+// column name doesn't silently break the table.
+func (s *PluginSpec) validateTransforms() error {
+	var errs []error
+	for i, transform := range s.Transforms {
+		columns, err := transformColumns(transform)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("transforms[%d]: %w", i, err))
+			continue
+		}
+		for _, column := range columns {
+			if !slices.ContainsFunc(s.ColumnSettings, func(settings ColumnSettings) bool { return settings.Name == column }) {
+				errs = append(errs, fmt.Errorf("transforms[%d]: column %q is not defined in columnSettings", i, column))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *PluginSpec) UnmarshalJSON(data []byte) error {
+	jsonUnmarshalFunc := func(variable interface{}) error {
+		return json.Unmarshal(data, variable)
+	}
+	return s.unmarshal(jsonUnmarshalFunc)
+}
+
+func (s *PluginSpec) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return s.unmarshal(unmarshal)
+}
+
+// unmarshal validates the settings of the columns and of the cells. The columns the transforms refer to are not
+// checked, so that a spec built by hand or by another tool can still be read.
+func (s *PluginSpec) unmarshal(unmarshal func(interface{}) error) error {
+	var tmp PluginSpec
+	type plain PluginSpec
+	if err := unmarshal((*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := tmp.validateSettings(); err != nil {
+		return err
+	}
+	*s = tmp
+	return nil
+}
+
+func (s *PluginSpec) validate() error {
+	return errors.Join(s.validateSettings(), s.validateTransforms())
+}
+
+func (s *PluginSpec) validateSettings() error {
+	var errs []error
+	if s.DefaultColumnWidth != nil {
+		if err := s.DefaultColumnWidth.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("defaultColumnWidth: %w", err))
+		}
+	}
+	if s.DefaultColumnHeight != nil {
+		if err := s.DefaultColumnHeight.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("defaultColumnHeight: %w", err))
+		}
+	}
+	for i := range s.ColumnSettings {
+		if err := s.ColumnSettings[i].validate(); err != nil {
+			errs = append(errs, fmt.Errorf("columnSettings[%d] (%q): %w", i, s.ColumnSettings[i].Name, err))
+		}
+	}
+	errs = append(errs, validateCellSettings(s.CellSettings))
+	return errors.Join(errs...)
 }
 
 type Option func(plugin *Builder) error
@@ -179,10 +691,8 @@ func create(options ...Option) (Builder, error) {
 		PluginSpec: PluginSpec{},
 	}
 
-	for _, opt := range options {
-		if err := opt(builder); err != nil {
-			return *builder, err
-		}
+	if err := option.ApplyAndValidate(PluginKind, builder, options, builder.validate); err != nil {
+		return *builder, err
 	}
 
 	return *builder, nil
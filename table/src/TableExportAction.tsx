@@ -16,10 +16,10 @@ import { escapeCsvValue, PanelData, sanitizeFilename } from '@perses-dev/plugin-
 import { InfoTooltip } from '@perses-dev/components';
 import { IconButton } from '@mui/material';
 import DownloadIcon from 'mdi-material-ui/Download';
-import { TimeSeriesData, transformData } from '@perses-dev/core';
+import { TimeSeriesData } from '@perses-dev/core';
 import { TableProps } from './components';
 import type { TableOptions } from './models';
-import { buildRawTableData } from './table-data-utils';
+import { buildRawTableData, transformTableData } from './table-data-utils';
 
 export interface ExportColumn {
   key: string;
@@ -38,7 +38,7 @@ export function buildTableData(
   // Use shared utility with forExport=true to get raw scalar values
   const rawData = buildRawTableData(queryResults, spec, { forExport: true });
 
-  const transformed = transformData(rawData, spec.transforms ?? []);
+  const transformed = transformTableData(rawData, spec.transforms ?? []);
 
   const allKeys: string[] = [];
   for (const entry of transformed) {
@@ -13,7 +13,7 @@
 
 import { Box, Theme, Typography, useTheme } from '@mui/material';
 import { Table, TableCellConfigs, TableColumnConfig, useSelection } from '@perses-dev/components';
-import { CalculationsMap, formatValue, QueryDataType, TimeSeriesData, transformData } from '@perses-dev/core';
+import { CalculationsMap, formatValue, QueryDataType, TimeSeriesData } from '@perses-dev/core';
 import { useSelectionItemActions } from '@perses-dev/dashboards';
 import {
   ActionOptions,
@@ -26,7 +26,7 @@ import {
 import { ColumnFiltersState, PaginationState, RowSelectionState, SortingState } from '@tanstack/react-table';
 import { ReactElement, useCallback, useEffect, useMemo, useRef, useState } from 'react';
 import { CellSettings, ColumnSettings, evaluateConditionalFormatting, TableOptions } from '../models';
-import { buildRawTableData, getTablePanelQueryMode } from '../table-data-utils';
+import { buildRawTableData, getTablePanelQueryMode, transformTableData } from '../table-data-utils';
 import { EmbeddedPanel } from './EmbeddedPanel';
 
 function parseNumericCellValue(value: unknown): number | undefined {
@@ -428,7 +428,7 @@ export function TablePanel({ contentDimensions, spec, queryResults }: TableProps
   }, [queryResults, spec]);
 
   // Transform will be applied by their orders on the original data
-  const data = useMemo(() => transformData(rawData, spec.transforms ?? []), [rawData, spec.transforms]);
+  const data = useMemo(() => transformTableData(rawData, spec.transforms ?? []), [rawData, spec.transforms]);
 
   const keys: string[] = useMemo(() => {
     const result: string[] = [];
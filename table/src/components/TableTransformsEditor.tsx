@@ -14,12 +14,28 @@
 import { TransformsEditor } from '@perses-dev/components';
 import { Transform } from '@perses-dev/core';
 import { ReactElement } from 'react';
-import { TableSettingsEditorProps } from '../models';
+import { isTableSpecificTransform, TableSettingsEditorProps, TableTransform } from '../models';
 
 export function TableTransformsEditor({ value, onChange }: TableSettingsEditorProps): ReactElement {
-  function handleTransformsChange(transforms: Transform[]): void {
-    onChange({ ...value, transforms: transforms });
+  const transforms = value.transforms ?? [];
+  // The editor only knows the Perses transforms: the ones specific to the table are kept at their position.
+  const persesTransforms = transforms.filter((transform): transform is Transform => !isTableSpecificTransform(transform));
+
+  function handleTransformsChange(editedTransforms: Transform[]): void {
+    const remaining = [...editedTransforms];
+    const result: TableTransform[] = [];
+    for (const transform of transforms) {
+      if (isTableSpecificTransform(transform)) {
+        result.push(transform);
+        continue;
+      }
+      const edited = remaining.shift();
+      if (edited !== undefined) {
+        result.push(edited);
+      }
+    }
+    onChange({ ...value, transforms: [...result, ...remaining] });
   }
 
-  return <TransformsEditor value={value.transforms ?? []} onChange={handleTransformsChange} />;
+  return <TransformsEditor value={persesTransforms} onChange={handleTransformsChange} />;
 }
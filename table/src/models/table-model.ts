@@ -116,6 +116,58 @@ export interface CellSettings {
 /**
  * The schema for a Table panel.
  */
+/**
+ * Keeps the rows whose value in the column matches the condition, or removes them when `exclude` is set.
+ */
+export interface FilterRowsTransform {
+  kind: 'FilterRows';
+  spec: {
+    column: string;
+    condition: Condition;
+    exclude?: boolean;
+    disabled?: boolean;
+  };
+}
+
+export type Aggregation = 'sum' | 'mean' | 'min' | 'max' | 'count' | 'first' | 'last';
+
+/**
+ * Merges the rows having the same values in the columns into a row made of these columns and of the aggregations,
+ * an aggregation being stored in the column `name`, the aggregated column by default.
+ */
+export interface GroupByTransform {
+  kind: 'GroupBy';
+  spec: {
+    columns: string[];
+    aggregations?: Array<{ column: string; aggregation: Aggregation; name?: string }>;
+    disabled?: boolean;
+  };
+}
+
+export type Operand = { column: string } | { value: number };
+
+/**
+ * Adds the column `name` holding the result of the operation, the rows missing an operand having no value.
+ */
+export interface ComputedColumnTransform {
+  kind: 'ComputedColumn';
+  spec: {
+    name: string;
+    left: Operand;
+    operator: '+' | '-' | '*' | '/';
+    right: Operand;
+    disabled?: boolean;
+  };
+}
+
+export type TableTransform = Transform | FilterRowsTransform | GroupByTransform | ComputedColumnTransform;
+
+export function isTableSpecificTransform(
+  transform: TableTransform
+): transform is FilterRowsTransform | GroupByTransform | ComputedColumnTransform {
+  return transform.kind === 'FilterRows' || transform.kind === 'GroupBy' || transform.kind === 'ComputedColumn';
+}
+
 export interface TableDefinition extends Definition<TableOptions> {
   kind: 'Table';
 }
@@ -147,7 +199,7 @@ export interface TableOptions {
   // Customize cell display based on their value.
   cellSettings?: CellSettings[];
   // Apply transforms to the data before rendering the table.
-  transforms?: Transform[];
+  transforms?: TableTransform[];
 }
 
 /**
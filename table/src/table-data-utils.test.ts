@@ -0,0 +1,74 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+import { transformTableData } from './table-data-utils';
+
+describe('transformTableData', () => {
+  const data = [
+    { job: 'api', instance: 'a', 'value #1': 2, 'value #2': 4 },
+    { job: 'api', instance: 'b', 'value #1': 3, 'value #2': 6 },
+    { job: 'db', instance: 'c', 'value #1': 1, 'value #2': 0 },
+    { job: 'test', instance: 'd', 'value #1': 5 },
+  ];
+
+  it('should filter and exclude rows', () => {
+    expect(
+      transformTableData(data, [
+        { kind: 'FilterRows', spec: { column: 'value #1', condition: { kind: 'Range', spec: { min: 2 } } } },
+        { kind: 'FilterRows', spec: { column: 'job', condition: { kind: 'Value', spec: { value: 'test' } }, exclude: true } },
+      ]).map((row) => row.instance)
+    ).toEqual(['a', 'b']);
+  });
+
+  it('should compute a column, without value when an operand is missing', () => {
+    expect(
+      transformTableData(data, [
+        {
+          kind: 'ComputedColumn',
+          spec: { name: 'ratio', left: { column: 'value #1' }, operator: '/', right: { column: 'value #2' } },
+        },
+        { kind: 'ComputedColumn', spec: { name: 'ratio', left: { column: 'ratio' }, operator: '*', right: { value: 100 } } },
+      ]).map((row) => row.ratio)
+    ).toEqual([50, 50, Infinity, undefined]);
+  });
+
+  it('should group the rows with aggregations', () => {
+    expect(
+      transformTableData(data, [
+        {
+          kind: 'GroupBy',
+          spec: {
+            columns: ['job'],
+            aggregations: [
+              { column: 'value #1', aggregation: 'sum' },
+              { column: 'value #2', aggregation: 'max' },
+              { column: 'instance', aggregation: 'count', name: 'instances' },
+            ],
+          },
+        },
+      ])
+    ).toEqual([
+      { job: 'api', 'value #1': 5, 'value #2': 6, instances: 2 },
+      { job: 'db', 'value #1': 1, 'value #2': 0, instances: 1 },
+      { job: 'test', 'value #1': 5, 'value #2': undefined, instances: 1 },
+    ]);
+  });
+
+  it('should skip the disabled transforms', () => {
+    expect(
+      transformTableData(data, [
+        { kind: 'GroupBy', spec: { columns: ['job'], disabled: true } },
+        { kind: 'MergeSeries', spec: { disabled: true } },
+      ])
+    ).toEqual(data);
+  });
+});
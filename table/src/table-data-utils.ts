@@ -11,9 +11,19 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-import { Labels, TimeSeries, TimeSeriesData } from '@perses-dev/core';
+import { Labels, TimeSeries, TimeSeriesData, Transform, transformData } from '@perses-dev/core';
 import { PanelData } from '@perses-dev/plugin-system';
-import { TableOptions } from './models';
+import {
+  Aggregation,
+  ComputedColumnTransform,
+  evaluateCondition,
+  FilterRowsTransform,
+  GroupByTransform,
+  isTableSpecificTransform,
+  Operand,
+  TableOptions,
+  TableTransform,
+} from './models';
 
 /**
  * Options for building raw table data.
@@ -94,3 +104,128 @@ export function buildRawTableData(
       }
     });
 }
+
+type Row = Record<string, unknown>;
+
+function filterRows(data: Row[], { spec }: FilterRowsTransform): Row[] {
+  return data.filter((row) => evaluateCondition(spec.condition, row[spec.column]) !== (spec.exclude ?? false));
+}
+
+function numericValues(values: unknown[]): number[] {
+  return values
+    .filter((value) => value !== null && value !== undefined && value !== '')
+    .map(Number)
+    .filter((value) => !Number.isNaN(value));
+}
+
+function aggregate(values: unknown[], aggregation: Aggregation): unknown {
+  const defined = values.filter((value) => value !== null && value !== undefined);
+  switch (aggregation) {
+    case 'count':
+      return defined.length;
+    case 'first':
+      return defined[0];
+    case 'last':
+      return defined[defined.length - 1];
+  }
+  const numbers = numericValues(defined);
+  if (numbers.length === 0) {
+    return undefined;
+  }
+  switch (aggregation) {
+    case 'sum':
+      return numbers.reduce((sum, value) => sum + value, 0);
+    case 'mean':
+      return numbers.reduce((sum, value) => sum + value, 0) / numbers.length;
+    case 'min':
+      return Math.min(...numbers);
+    case 'max':
+      return Math.max(...numbers);
+  }
+}
+
+function groupBy(data: Row[], { spec }: GroupByTransform): Row[] {
+  const groups = new Map<string, Row[]>();
+  for (const row of data) {
+    const key = JSON.stringify(spec.columns.map((column) => row[column] ?? null));
+    groups.set(key, [...(groups.get(key) ?? []), row]);
+  }
+  return [...groups.values()].map((rows) => {
+    const result: Row = {};
+    for (const column of spec.columns) {
+      result[column] = rows[0]?.[column];
+    }
+    for (const { column, aggregation, name } of spec.aggregations ?? []) {
+      result[name ?? column] = aggregate(
+        rows.map((row) => row[column]),
+        aggregation
+      );
+    }
+    return result;
+  });
+}
+
+function operandValue(row: Row, operand: Operand): number | undefined {
+  if ('value' in operand) {
+    return operand.value;
+  }
+  const value = row[operand.column];
+  if (value === null || value === undefined || value === '' || Number.isNaN(Number(value))) {
+    return undefined;
+  }
+  return Number(value);
+}
+
+function computeColumn(data: Row[], { spec }: ComputedColumnTransform): Row[] {
+  return data.map((row) => {
+    const left = operandValue(row, spec.left);
+    const right = operandValue(row, spec.right);
+    if (left === undefined || right === undefined) {
+      const result = { ...row };
+      delete result[spec.name];
+      return result;
+    }
+    switch (spec.operator) {
+      case '+':
+        return { ...row, [spec.name]: left + right };
+      case '-':
+        return { ...row, [spec.name]: left - right };
+      case '*':
+        return { ...row, [spec.name]: left * right };
+      case '/':
+        return { ...row, [spec.name]: left / right };
+    }
+  });
+}
+
+/**
+ * Applies the transforms in their order: the Perses ones with transformData, and the ones specific to the table
+ * (FilterRows, GroupBy and ComputedColumn).
+ */
+export function transformTableData(data: Row[], transforms: TableTransform[]): Row[] {
+  let result = data;
+  let persesTransforms: Transform[] = [];
+  for (const transform of transforms) {
+    if (!isTableSpecificTransform(transform)) {
+      persesTransforms.push(transform);
+      continue;
+    }
+    result = transformData(result, persesTransforms);
+    persesTransforms = [];
+    if (transform.spec.disabled) {
+      continue;
+    }
+    switch (transform.kind) {
+      case 'FilterRows':
+        result = filterRows(result, transform);
+        break;
+      case 'GroupBy':
+        result = groupBy(result, transform);
+        break;
+      case 'ComputedColumn':
+        result = computeColumn(result, transform);
+        break;
+    }
+  }
+  return transformData(result, persesTransforms);
+}
@@ -15,6 +15,7 @@ package migrate
 
 import (
 	commonMigrate "github.com/perses/shared/cue/common/migrate"
+	"list"
 	"strings"
 	"strconv"
 )
@@ -68,15 +69,16 @@ spec: {
 		}
 	}
 
-	// yAxis
+	// yAxes
+	// NB: the Grafana axis of the panel is the left axis, the series placed on the right by the overrides go to the right axis
 	#unit: *commonMigrate.#mapping.unit[#panel.fieldConfig.defaults.unit] | null
 	if #unit != null {
-		yAxis: format: unit: #unit
+		yAxes: left: format: unit: #unit
 	}
 
 	#decimal: *#panel.fieldConfig.defaults.decimal | *#panel.fieldConfig.defaults.decimals | null
 	if #decimal != null {
-		yAxis: format: {
+		yAxes: left: format: {
 			decimalPlaces: #decimal
 			if #unit == null {
 				unit: "decimal"
@@ -94,7 +96,7 @@ spec: {
 		null,
 	][0]
 	if #min != null {
-		yAxis: min: #min
+		yAxes: left: min: #min
 	}
 
 	#max: [// switch
@@ -107,7 +109,7 @@ spec: {
 		null,
 	][0]
 	if #max != null {
-		yAxis: max: #max
+		yAxes: left: max: #max
 	}
 
 	#logBase: [// switch
@@ -117,12 +119,12 @@ spec: {
 		null,
 	][0]
 	if #logBase != null {
-		yAxis: logBase: #logBase
+		yAxes: left: logBase: #logBase
 	}
 
 	#yAxisLabel: *#panel.fieldConfig.defaults.custom.axisLabel | null
 	if #yAxisLabel != null if len(#yAxisLabel) > 0 {
-		yAxis: label: #yAxisLabel
+		yAxes: left: label: #yAxisLabel
 	}
 
 	// thresholds
@@ -186,7 +188,10 @@ spec: {
 
 	#stacking: *#panel.fieldConfig.defaults.custom.stacking.mode | "none"
 	if #stacking != "none" {
-		visual: stack: "all"
+		visual: stack: [// switch
+			if #stacking == "percent" {"percent"},
+			"all",
+		][0]
 	}
 
 	// migrate fixedColor overrides to querySettings when applicable
@@ -212,6 +217,9 @@ spec: {
 					][0]
 					areaOpacity: #queryFillOpacity / 100
 				}
+				if property.id == "custom.axisPlacement" if property.value == "right" {
+					yAxis: "right"
+				}
 				if property.id == "unit" {
 					#queryUnit: *commonMigrate.#mapping.unit[property.value] | null
 					if #queryUnit != null {
@@ -222,9 +230,110 @@ spec: {
 		},
 	]
 
+	// migrate the overrides placing series on the right axis to seriesOverrides, and their unit and label to the right axis
+	#rightAxisOverrides: [for override in (*#panel.fieldConfig.overrides | [])
+		if (override.matcher.id == "byName" || override.matcher.id == "byRegexp") && override.matcher.options != _|_
+		for property in override.properties
+		if property.id == "custom.axisPlacement" if property.value == "right" {override}]
+	if len(#rightAxisOverrides) != 0 {
+		seriesOverrides: [for override in #rightAxisOverrides {
+			matcher: [// switch
+				if override.matcher.id == "byName" {name: override.matcher.options},
+				// Grafana regexes match a part of the name while Perses ones match the whole name
+				{nameRegex: ".*(?:" + strings.Trim(override.matcher.options, "/") + ").*"},
+			][0]
+			yAxis: "right"
+		}]
+		#rightUnits: [for override in #rightAxisOverrides for property in override.properties
+			if property.id == "unit" if commonMigrate.#mapping.unit[property.value] != _|_ {
+				commonMigrate.#mapping.unit[property.value]
+			}]
+		if len(#rightUnits) != 0 {
+			yAxes: right: format: unit: #rightUnits[0]
+		}
+		#rightLabels: [for override in #rightAxisOverrides for property in override.properties
+			if property.id == "custom.axisLabel" if (property.value & string) != _|_ if len(property.value) > 0 {
+				property.value
+			}]
+		if len(#rightLabels) != 0 {
+			yAxes: right: label: #rightLabels[0]
+		}
+	}
+
 	// don't keep elements that just define the queryIndex
 	#querySettingsFiltered: [for qs in #querySettings if len(qs) > 1 {qs}]
 	if len(#querySettingsFiltered) != 0 {
 		querySettings: #querySettingsFiltered
 	}
+
+	// annotations
+	// NB: Grafana defines the annotations at the dashboard level (annotations.list), while the migration engine only gives
+	// the panel to this script: the annotations are migrated when they are copied beforehand in the panel, at the same path.
+	// The Prometheus and Loki annotations are migrated, as well as the filter on the panel ids. The builtin annotations
+	// (Grafana alerts and annotations) have no Perses equivalent.
+	#panelId: *#panel.id | -1
+	#annotations: [for annotation in (*#panel.annotations.list | [])
+		let expr = [// switch
+			if (*annotation.expr | null) != null {annotation.expr},
+			if (*annotation.target.expr | null) != null {annotation.target.expr},
+			null,
+		][0]
+		let datasourceType = *annotation.datasource.type | null
+		if (*annotation.builtIn | 0) != 1
+		if expr != null
+		if datasourceType == "prometheus" || datasourceType == "loki"
+		if (*annotation.filter | null) == null || list.Contains(*annotation.filter.ids | [], #panelId) != (*annotation.filter.exclude | false) {
+			name: annotation.name
+			if (*annotation.enable | true) == false {
+				enabled: false
+			}
+			let annotationColor = [// switch
+				if (*annotation.iconColor | null) == null {""},
+				if commonMigrate.#mapping.color[annotation.iconColor] != _|_ {commonMigrate.#mapping.color[annotation.iconColor]},
+				annotation.iconColor,
+			][0]
+			if annotationColor =~ "^#(?:[0-9a-fA-F]{3}){1,2}$" {
+				color: annotationColor
+			}
+			query: [// switch
+				if datasourceType == "prometheus" {
+					kind: "TimeSeriesQuery"
+					spec: plugin: {
+						kind: "PrometheusTimeSeriesQuery"
+						spec: {
+							if annotation.datasource.uid != _|_ {
+								datasource: {
+									kind: "PrometheusDatasource"
+									name: annotation.datasource.uid
+								}
+							}
+							query: expr
+						}
+					}
+				},
+				{
+					kind: "LogQuery"
+					spec: plugin: {
+						kind: "LokiLogQuery"
+						spec: {
+							if annotation.datasource.uid != _|_ {
+								datasource: {
+									kind: "LokiDatasource"
+									name: annotation.datasource.uid
+								}
+							}
+							query: expr
+						}
+					}
+				},
+			][0]
+			let annotationTags = [for tag in strings.Split(*annotation.tagKeys | "", ",") if strings.TrimSpace(tag) != "" {strings.TrimSpace(tag)}]
+			if len(annotationTags) != 0 {
+				mapping: tags: annotationTags
+			}
+		},
+	]
+	if len(#annotations) != 0 {
+		annotations: #annotations
+	}
 }
@@ -19,12 +19,15 @@ import (
 
 kind: "TimeSeriesChart"
 spec: close({
-	legend?:        common.#legendWithValues
-	tooltip?:       #tooltip
-	yAxis?:         #yAxis
-	thresholds?:    common.#thresholds
-	visual?:        #visual
-	querySettings?: #querySettings
+	legend?:          common.#legendWithValues
+	tooltip?:         #tooltip
+	yAxis?:           #yAxis // deprecated: use yAxes.left, yAxis is read as the left axis when yAxes.left is not set
+	yAxes?:           #yAxes
+	thresholds?:      common.#thresholds
+	visual?:          #visual
+	querySettings?:   #querySettings
+	seriesOverrides?: [...#seriesOverride]
+	annotations?:     [...#annotation]
 })
 
 #tooltip: {
@@ -43,7 +46,7 @@ spec: close({
 	showPoints?:   "auto" | "always"
 	palette?:      #palette
 	pointRadius?:  number & >=0 & <=6
-	stack?:        "all" | "percent" // TODO: percent option is disabled until support is added
+	stack?:        "all" | "percent" // percent normalises each timestamp across the stacked series
 	connectNulls?: bool
 }
 
@@ -59,6 +62,14 @@ spec: close({
 	logBase?: 2 | 10
 }
 
+// the right axis is displayed when query settings or series overrides bind series to it
+#yAxes: {
+	left?:  #yAxis
+	right?: #yAxis
+}
+
+#yAxisPosition: "left" | "right"
+
 #querySettings: [...{
 	queryIndex:   int & >=0
 	colorMode?:   "fixed" | "fixed-single"       // NB: "palette" could be added later
@@ -66,8 +77,52 @@ spec: close({
 	lineStyle?:   #lineStyle
 	areaOpacity?: #areaOpacity
 	format?:      common.#format
+	yAxis?:       #yAxisPosition
 }]
 
+#seriesOverride: {
+	matcher:      #seriesMatcher
+	color?:       =~"^#(?:[0-9a-fA-F]{3}){1,2}$" // hexadecimal color code
+	lineStyle?:   #lineStyle
+	lineWidth?:   number & >=0.25 & <=3
+	areaOpacity?: #areaOpacity
+	stack?:       string & !="" // name of the stack group, the series of the same group are stacked together
+	yAxis?:       #yAxisPosition
+	hidden?:      bool
+}
+
+// exactly one way to select the series: exact name, regex on the whole name or label matchers
+#seriesMatcher: close({name: string & !=""}) | close({nameRegex: string & !=""}) | close({labels: [#labelMatcher, ...#labelMatcher]})
+
+#labelMatcher: {
+	name:  string & !=""
+	value: string
+	type?: "=" | "!=" | "=~" | "!~" // same semantic as the PromQL label matchers, "=" by default
+}
+
+// events overlaid on the chart, given by the results of a time series or log query of any plugin
+#annotation: {
+	name:     string & !=""
+	enabled?: bool // disabled annotations are not queried, true by default
+	color?:   =~"^#(?:[0-9a-fA-F]{3}){1,2}$"
+	query: {
+		kind: "TimeSeriesQuery" | "LogQuery"
+		spec: plugin: {
+			kind: string
+			spec: {...}
+		}
+	}
+	mapping?: #annotationMapping
+}
+
+// names of the fields (labels of the series or of the log entries, columns of the rows) giving the events
+#annotationMapping: {
+	time?:    string // start time of the event, the timestamp of the entry or point by default
+	endTime?: string // end time of the event, an event with an end time is a time window
+	text?:    string // text of the event, the log line or the series name by default
+	tags?:    [...string]
+}
+
 #lineStyle: "solid" | "dashed" | "dotted"
 
 #areaOpacity: number & >=0 & <=1 // transparency level from 0 (transparent) to 1 (opaque)
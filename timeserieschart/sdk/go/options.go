@@ -13,7 +13,12 @@
 
 package timeseries
 
-import "github.com/perses/perses/go-sdk/common"
+import (
+	"fmt"
+
+	"github.com/perses/perses/go-sdk/common"
+	"github.com/perses/perses/go-sdk/query"
+)
 
 func WithLegend(legend Legend) Option {
 	return func(builder *Builder) error {
@@ -29,6 +34,9 @@ func WithTooltip(tooltip Tooltip) Option {
 	}
 }
 
+// WithYAxis defines the yAxis field, read by the panel as the left Y axis when yAxes.left is not set.
+//
+// Deprecated: use WithLeftYAxis.
 func WithYAxis(axis YAxis) Option {
 	return func(builder *Builder) error {
 		builder.YAxis = &axis
@@ -36,6 +44,28 @@ func WithYAxis(axis YAxis) Option {
 	}
 }
 
+func WithLeftYAxis(axis YAxis) Option {
+	return func(builder *Builder) error {
+		if builder.YAxes == nil {
+			builder.YAxes = &YAxes{}
+		}
+		builder.YAxes.Left = &axis
+		return nil
+	}
+}
+
+// WithRightYAxis defines the right Y axis, used by the series bound to it with the query settings or the series
+// overrides.
+func WithRightYAxis(axis YAxis) Option {
+	return func(builder *Builder) error {
+		if builder.YAxes == nil {
+			builder.YAxes = &YAxes{}
+		}
+		builder.YAxes.Right = &axis
+		return nil
+	}
+}
+
 func Thresholds(thresholds common.Thresholds) Option {
 	return func(builder *Builder) error {
 		builder.Thresholds = &thresholds
@@ -56,3 +86,91 @@ func WithQuerySettings(querySettingsList []QuerySettingsItem) Option {
 		return nil
 	}
 }
+
+func WithSeriesOverrides(overrides []SeriesOverride) Option {
+	return func(builder *Builder) error {
+		for i := range overrides {
+			if err := overrides[i].validate(); err != nil {
+				return fmt.Errorf("seriesOverrides[%d]: %w", i, err)
+			}
+		}
+		builder.SeriesOverrides = overrides
+		return nil
+	}
+}
+
+func AddSeriesOverride(override SeriesOverride) Option {
+	return func(builder *Builder) error {
+		if err := override.validate(); err != nil {
+			return err
+		}
+		builder.SeriesOverrides = append(builder.SeriesOverrides, override)
+		return nil
+	}
+}
+
+// MatchName returns a matcher selecting the series with exactly this name.
+func MatchName(name string) SeriesMatcher {
+	return SeriesMatcher{Name: name}
+}
+
+// MatchNameRegex returns a matcher selecting the series whose whole name matches the regex.
+func MatchNameRegex(regex string) SeriesMatcher {
+	return SeriesMatcher{NameRegex: regex}
+}
+
+// MatchLabels returns a matcher selecting the series whose labels match all the matchers.
+func MatchLabels(matchers ...LabelMatcher) SeriesMatcher {
+	return SeriesMatcher{Labels: matchers}
+}
+
+type AnnotationOption func(annotation *Annotation) error
+
+// AddAnnotation adds an annotation whose events are the results of the query, built by the query plugin SDK
+// (e.g. loki.LogQuery or prometheus.PromQL).
+func AddAnnotation(name string, queryOption query.Option, options ...AnnotationOption) Option {
+	return func(builder *Builder) error {
+		q, err := query.New(queryOption)
+		if err != nil {
+			return err
+		}
+		annotation := Annotation{Name: name, Query: *q}
+		for _, opt := range options {
+			if optErr := opt(&annotation); optErr != nil {
+				return optErr
+			}
+		}
+		if validateErr := annotation.validate(); validateErr != nil {
+			return validateErr
+		}
+		for _, a := range builder.Annotations {
+			if a.Name == name {
+				return fmt.Errorf("annotation %q is defined more than once", name)
+			}
+		}
+		builder.Annotations = append(builder.Annotations, annotation)
+		return nil
+	}
+}
+
+func AnnotationColor(color string) AnnotationOption {
+	return func(annotation *Annotation) error {
+		annotation.Color = color
+		return nil
+	}
+}
+
+func AnnotationMappingFields(mapping AnnotationMapping) AnnotationOption {
+	return func(annotation *Annotation) error {
+		annotation.Mapping = &mapping
+		return nil
+	}
+}
+
+// AnnotationEnabled toggles the annotation. A disabled annotation is kept in the panel but not queried.
+func AnnotationEnabled(enabled bool) AnnotationOption {
+	return func(annotation *Annotation) error {
+		annotation.Enabled = &enabled
+		return nil
+	}
+}
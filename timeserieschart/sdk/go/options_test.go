@@ -16,6 +16,12 @@ package timeseries
 import (
 	"encoding/json"
 	"testing"
+
+	"github.com/perses/perses/go-sdk/common"
+	"github.com/perses/perses/go-sdk/query"
+	persesCommon "github.com/perses/perses/pkg/model/api/v1/common"
+	"github.com/perses/perses/pkg/model/api/v1/plugin"
+	"github.com/perses/plugins/sdk/go/option"
 )
 
 func TestWithVisual_EmptyPalette(t *testing.T) {
@@ -98,3 +104,222 @@ func TestWithVisual_WithPaletteMode(t *testing.T) {
 		t.Errorf("Expected palette mode to be %s, got %v", AutoMode, mode)
 	}
 }
+
+func TestWithVisual_Stack(t *testing.T) {
+	builder, err := create(WithVisual(Visual{Stack: PercentageStack}))
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if builder.Visual.Stack != PercentageStack {
+		t.Errorf("Expected stack to be %s, got %s", PercentageStack, builder.Visual.Stack)
+	}
+	if _, err := create(WithVisual(Visual{Stack: "relative"})); err == nil {
+		t.Error("Expected an error for an unknown stack, got nil")
+	}
+}
+
+func TestAddSeriesOverride(t *testing.T) {
+	opacity := 0.0
+	builder, err := create(
+		AddSeriesOverride(SeriesOverride{Matcher: MatchName("p99"), Color: "#ff0000", LineStyle: "dashed"}),
+		AddSeriesOverride(SeriesOverride{Matcher: MatchNameRegex("errors.*"), YAxis: RightYAxis, AreaOpacity: &opacity}),
+		AddSeriesOverride(SeriesOverride{Matcher: MatchLabels(LabelMatcher{Name: "__name__", Value: "up"}), Hidden: true}),
+	)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+
+	jsonBytes, err := json.Marshal(builder)
+	if err != nil {
+		t.Fatalf("Failed to marshal builder: %v", err)
+	}
+	expected := `{"seriesOverrides":[` +
+		`{"matcher":{"name":"p99"},"color":"#ff0000","lineStyle":"dashed"},` +
+		`{"matcher":{"nameRegex":"errors.*"},"areaOpacity":0,"yAxis":"right"},` +
+		`{"matcher":{"labels":[{"name":"__name__","value":"up"}]},"hidden":true}]}`
+	if string(jsonBytes) != expected {
+		t.Errorf("Expected %s, got %s", expected, jsonBytes)
+	}
+}
+
+func TestAddSeriesOverride_Invalid(t *testing.T) {
+	testSuite := []struct {
+		title    string
+		override SeriesOverride
+	}{
+		{
+			title:    "no matcher",
+			override: SeriesOverride{Color: "#ff0000"},
+		},
+		{
+			title:    "several matchers",
+			override: SeriesOverride{Matcher: SeriesMatcher{Name: "p99", NameRegex: "p9.*"}},
+		},
+		{
+			title:    "invalid name regex",
+			override: SeriesOverride{Matcher: MatchNameRegex("p9(")},
+		},
+		{
+			title:    "invalid label regex",
+			override: SeriesOverride{Matcher: MatchLabels(LabelMatcher{Name: "job", Value: "api[", Type: RegexMatch})},
+		},
+		{
+			title:    "name regex using a syntax JavaScript doesn't support",
+			override: SeriesOverride{Matcher: MatchNameRegex(`(?P<quantile>p\d+)`)},
+		},
+		{
+			title:    "unknown label match type",
+			override: SeriesOverride{Matcher: MatchLabels(LabelMatcher{Name: "job", Value: "api", Type: "=="})},
+		},
+		{
+			title:    "invalid color",
+			override: SeriesOverride{Matcher: MatchName("p99"), Color: "red"},
+		},
+		{
+			title:    "line width out of range",
+			override: SeriesOverride{Matcher: MatchName("p99"), LineWidth: 5},
+		},
+		{
+			title:    "unknown y axis",
+			override: SeriesOverride{Matcher: MatchName("p99"), YAxis: "top"},
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			if _, err := create(AddSeriesOverride(test.override)); err == nil {
+				t.Error("Expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestAddSeriesOverride_JavaScriptRegex(t *testing.T) {
+	// a lookahead isn't supported by the regexp package, but the frontend evaluates the matchers as JavaScript regexes
+	for _, matcher := range []SeriesMatcher{
+		MatchNameRegex("(?!debug).*"),
+		MatchLabels(LabelMatcher{Name: "job", Value: "(?!debug).*", Type: NotRegexMatch}),
+	} {
+		if _, err := create(AddSeriesOverride(SeriesOverride{Matcher: matcher, Color: "#ff0000"})); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestWithYAxes(t *testing.T) {
+	unit := string(common.SecondsUnit)
+	builder, err := create(
+		WithLeftYAxis(YAxis{Label: "latency", Format: &common.Format{Unit: &unit}}),
+		WithRightYAxis(YAxis{Label: "throughput", Min: 1}),
+		WithQuerySettings([]QuerySettingsItem{{QueryIndex: 1, YAxis: RightYAxis}}),
+	)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	jsonBytes, err := json.Marshal(builder)
+	if err != nil {
+		t.Fatalf("Failed to marshal builder: %v", err)
+	}
+	expected := `{"yAxes":{"left":{"label":"latency","format":{"unit":"seconds"}},"right":{"label":"throughput","min":1}},` +
+		`"querySettings":[{"queryIndex":1,"yAxis":"right"}]}`
+	if string(jsonBytes) != expected {
+		t.Errorf("Expected %s, got %s", expected, jsonBytes)
+	}
+}
+
+func TestWithYAxis(t *testing.T) {
+	builder, err := create(WithYAxis(YAxis{Label: "latency"}), WithRightYAxis(YAxis{Label: "throughput"}))
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	jsonBytes, err := json.Marshal(builder)
+	if err != nil {
+		t.Fatalf("Failed to marshal builder: %v", err)
+	}
+	expected := `{"yAxis":{"label":"latency"},"yAxes":{"right":{"label":"throughput"}}}`
+	if string(jsonBytes) != expected {
+		t.Errorf("Expected %s, got %s", expected, jsonBytes)
+	}
+
+	if _, err := create(WithYAxis(YAxis{Label: "latency"}), WithLeftYAxis(YAxis{Label: "latency"})); err == nil {
+		t.Error("Expected an error when both yAxis and yAxes.left are set, got nil")
+	}
+}
+
+func TestWithQuerySettings_UnknownYAxis(t *testing.T) {
+	_, err := create(WithQuerySettings([]QuerySettingsItem{{QueryIndex: 0, YAxis: "top"}}))
+	if err == nil {
+		t.Fatal("Expected an error, got nil")
+	}
+	// the query settings are checked with the whole spec
+	if buildErrs := option.Errors(err); len(buildErrs) != 1 || len(buildErrs[0].Option) > 0 {
+		t.Errorf("Expected a validation error of the spec, got %v", err)
+	}
+}
+
+func lokiQuery(expr string) query.Option {
+	return query.Option{
+		Kind:   plugin.KindLogQuery,
+		Plugin: persesCommon.Plugin{Kind: "LokiLogQuery", Spec: map[string]any{"query": expr}},
+	}
+}
+
+func TestAddAnnotation(t *testing.T) {
+	builder, err := create(
+		AddAnnotation("deploys", lokiQuery(`{app="api"} |= "deployed"`),
+			AnnotationColor("#8e44ad"),
+			AnnotationMappingFields(AnnotationMapping{Text: "version", Tags: []string{"env"}}),
+		),
+		AddAnnotation("incidents", lokiQuery(`{app="pager"}`), AnnotationEnabled(false)),
+	)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+
+	jsonBytes, err := json.Marshal(builder.Annotations)
+	if err != nil {
+		t.Fatalf("Failed to marshal annotations: %v", err)
+	}
+	expected := `[{"name":"deploys","color":"#8e44ad","query":{"kind":"LogQuery","spec":{"plugin":{"kind":"LokiLogQuery","spec":{"query":"{app=\"api\"} |= \"deployed\""}}}},` +
+		`"mapping":{"text":"version","tags":["env"]}},` +
+		`{"name":"incidents","enabled":false,"query":{"kind":"LogQuery","spec":{"plugin":{"kind":"LokiLogQuery","spec":{"query":"{app=\"pager\"}"}}}}}]`
+	if string(jsonBytes) != expected {
+		t.Errorf("Expected %s, got %s", expected, jsonBytes)
+	}
+}
+
+func TestAddAnnotation_Invalid(t *testing.T) {
+	testSuites := []struct {
+		title   string
+		options []Option
+	}{
+		{
+			title:   "empty name",
+			options: []Option{AddAnnotation("", lokiQuery(`{app="api"}`))},
+		},
+		{
+			title:   "invalid color",
+			options: []Option{AddAnnotation("deploys", lokiQuery(`{app="api"}`), AnnotationColor("purple"))},
+		},
+		{
+			title: "trace query",
+			options: []Option{AddAnnotation("traces", query.Option{
+				Kind:   plugin.KindTraceQuery,
+				Plugin: persesCommon.Plugin{Kind: "TempoTraceQuery"},
+			})},
+		},
+		{
+			title: "duplicated name",
+			options: []Option{
+				AddAnnotation("deploys", lokiQuery(`{app="api"}`)),
+				AddAnnotation("deploys", lokiQuery(`{app="web"}`)),
+			},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			if _, err := create(test.options...); err == nil {
+				t.Error("Expected an error, got nil")
+			}
+		})
+	}
+}
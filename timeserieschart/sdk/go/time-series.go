@@ -14,8 +14,15 @@
 package timeseries
 
 import (
+	"fmt"
+	"regexp"
+
+	"github.com/dlclark/regexp2"
 	"github.com/perses/perses/go-sdk/common"
 	"github.com/perses/perses/go-sdk/panel"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/plugin"
+	"github.com/perses/plugins/sdk/go/option"
 )
 
 const PluginKind = "TimeSeriesChart"
@@ -81,7 +88,7 @@ type VisualStack string
 
 const (
 	AllStack        VisualStack = "all"
-	PercentageStack VisualStack = "percent"
+	PercentageStack VisualStack = "percent" // stacks the series as percentages of the total of each timestamp
 )
 
 type Visual struct {
@@ -95,6 +102,15 @@ type Visual struct {
 	ConnectNulls bool             `json:"connectNulls,omitempty" yaml:"connectNulls,omitempty"`
 }
 
+func (v *Visual) validate() error {
+	switch v.Stack {
+	case "", AllStack, PercentageStack:
+		return nil
+	default:
+		return fmt.Errorf("unknown stack %q, it must be %q or %q", v.Stack, AllStack, PercentageStack)
+	}
+}
+
 type YAxis struct {
 	Show    bool           `json:"show,omitempty" yaml:"show,omitempty"`
 	Label   string         `json:"label,omitempty" yaml:"label,omitempty"`
@@ -104,13 +120,61 @@ type YAxis struct {
 	LogBase uint           `json:"logBase,omitempty" yaml:"logBase,omitempty"`
 }
 
+// YAxes are the axes of the chart. The right axis is displayed when query settings or series overrides bind series
+// to it.
+type YAxes struct {
+	Left  *YAxis `json:"left,omitempty" yaml:"left,omitempty"`
+	Right *YAxis `json:"right,omitempty" yaml:"right,omitempty"`
+}
+
 type PluginSpec struct {
-	Legend        *Legend              `json:"legend,omitempty" yaml:"legend,omitempty"`
-	Tooltip       *Tooltip             `json:"tooltip,omitempty" yaml:"tooltip,omitempty"`
-	YAxis         *YAxis               `json:"yAxis,omitempty" yaml:"yAxis,omitempty"`
-	Thresholds    *common.Thresholds   `json:"thresholds,omitempty" yaml:"thresholds,omitempty"`
-	Visual        *Visual              `json:"visual,omitempty" yaml:"visual,omitempty"`
-	QuerySettings *[]QuerySettingsItem `json:"querySettings,omitempty" yaml:"querySettings,omitempty"`
+	Legend  *Legend  `json:"legend,omitempty" yaml:"legend,omitempty"`
+	Tooltip *Tooltip `json:"tooltip,omitempty" yaml:"tooltip,omitempty"`
+	// Deprecated: use YAxes.Left. The panel reads YAxis as the left axis when YAxes.Left is not set.
+	YAxis           *YAxis               `json:"yAxis,omitempty" yaml:"yAxis,omitempty"`
+	YAxes           *YAxes               `json:"yAxes,omitempty" yaml:"yAxes,omitempty"`
+	Thresholds      *common.Thresholds   `json:"thresholds,omitempty" yaml:"thresholds,omitempty"`
+	Visual          *Visual              `json:"visual,omitempty" yaml:"visual,omitempty"`
+	QuerySettings   *[]QuerySettingsItem `json:"querySettings,omitempty" yaml:"querySettings,omitempty"`
+	SeriesOverrides []SeriesOverride     `json:"seriesOverrides,omitempty" yaml:"seriesOverrides,omitempty"`
+	Annotations     []Annotation         `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+}
+
+func (s *PluginSpec) validate() error {
+	if s.YAxis != nil && s.YAxes != nil && s.YAxes.Left != nil {
+		return fmt.Errorf("yAxis and yAxes.left cannot be both set, yAxis is deprecated in favor of yAxes.left")
+	}
+	if s.Visual != nil {
+		if err := s.Visual.validate(); err != nil {
+			return err
+		}
+	}
+	if s.QuerySettings != nil {
+		for _, item := range *s.QuerySettings {
+			switch item.YAxis {
+			case "", LeftYAxis, RightYAxis:
+			default:
+				return fmt.Errorf("unknown yAxis %q for the query %d", item.YAxis, item.QueryIndex)
+			}
+		}
+	}
+	for i := range s.SeriesOverrides {
+		if err := s.SeriesOverrides[i].validate(); err != nil {
+			return fmt.Errorf("seriesOverrides[%d]: %w", i, err)
+		}
+	}
+	names := make(map[string]bool, len(s.Annotations))
+	for i := range s.Annotations {
+		annotation := &s.Annotations[i]
+		if err := annotation.validate(); err != nil {
+			return err
+		}
+		if names[annotation.Name] {
+			return fmt.Errorf("annotation %q is defined more than once", annotation.Name)
+		}
+		names[annotation.Name] = true
+	}
+	return nil
 }
 
 type ColorMode string
@@ -127,6 +191,165 @@ type QuerySettingsItem struct {
 	LineStyle   string         `json:"lineStyle,omitempty" yaml:"lineStyle,omitempty"`
 	AreaOpacity float64        `json:"areaOpacity,omitempty" yaml:"areaOpacity,omitempty"`
 	Format      *common.Format `json:"format,omitempty" yaml:"format,omitempty"`
+	YAxis       YAxisPosition  `json:"yAxis,omitempty" yaml:"yAxis,omitempty"`
+}
+
+var colorRegexp = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}){1,2}$`)
+
+type LabelMatchType string
+
+const (
+	EqualMatch    LabelMatchType = "="
+	NotEqualMatch LabelMatchType = "!="
+	RegexMatch    LabelMatchType = "=~"
+	NotRegexMatch LabelMatchType = "!~"
+)
+
+// validateRegex checks a regex of a matcher as the frontend evaluates it: as a fully anchored JavaScript regex, so with
+// the ECMAScript semantics rather than the RE2 ones of the regexp package.
+func validateRegex(pattern string) error {
+	_, err := regexp2.Compile("^(?:"+pattern+")$", regexp2.ECMAScript)
+	return err
+}
+
+// LabelMatcher matches a label of the series, like a PromQL label matcher. The regexes are fully anchored.
+type LabelMatcher struct {
+	Name  string         `json:"name" yaml:"name"`
+	Value string         `json:"value" yaml:"value"`
+	Type  LabelMatchType `json:"type,omitempty" yaml:"type,omitempty"`
+}
+
+func (m *LabelMatcher) validate() error {
+	if len(m.Name) == 0 {
+		return fmt.Errorf("label matcher name cannot be empty")
+	}
+	switch m.Type {
+	case "", EqualMatch, NotEqualMatch:
+	case RegexMatch, NotRegexMatch:
+		if err := validateRegex(m.Value); err != nil {
+			return fmt.Errorf("invalid regex for the label matcher %q: %w", m.Name, err)
+		}
+	default:
+		return fmt.Errorf("unknown type %q for the label matcher %q", m.Type, m.Name)
+	}
+	return nil
+}
+
+// SeriesMatcher selects the series an override applies to: by exact name, by a regex on the name, or by labels.
+// Exactly one of them must be set.
+type SeriesMatcher struct {
+	Name      string         `json:"name,omitempty" yaml:"name,omitempty"`
+	NameRegex string         `json:"nameRegex,omitempty" yaml:"nameRegex,omitempty"`
+	Labels    []LabelMatcher `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+func (m *SeriesMatcher) validate() error {
+	set := 0
+	for _, ok := range []bool{len(m.Name) > 0, len(m.NameRegex) > 0, len(m.Labels) > 0} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("exactly one of matcher.name, matcher.nameRegex or matcher.labels must be set")
+	}
+	if len(m.NameRegex) > 0 {
+		if err := validateRegex(m.NameRegex); err != nil {
+			return fmt.Errorf("invalid matcher.nameRegex: %w", err)
+		}
+	}
+	for i := range m.Labels {
+		if err := m.Labels[i].validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type YAxisPosition string
+
+const (
+	LeftYAxis  YAxisPosition = "left"
+	RightYAxis YAxisPosition = "right"
+)
+
+// SeriesOverride changes the style of the series selected by its matcher. When several overrides match a series, they
+// are applied in order, so the last one wins.
+type SeriesOverride struct {
+	Matcher   SeriesMatcher `json:"matcher" yaml:"matcher"`
+	Color     string        `json:"color,omitempty" yaml:"color,omitempty"`
+	LineStyle string        `json:"lineStyle,omitempty" yaml:"lineStyle,omitempty"`
+	LineWidth float64       `json:"lineWidth,omitempty" yaml:"lineWidth,omitempty"`
+	// AreaOpacity is a pointer since 0 is a valid opacity, removing the area of the series.
+	AreaOpacity *float64 `json:"areaOpacity,omitempty" yaml:"areaOpacity,omitempty"`
+	// Stack is the name of the stack group of the series. The series of the same group are stacked together.
+	Stack  string        `json:"stack,omitempty" yaml:"stack,omitempty"`
+	YAxis  YAxisPosition `json:"yAxis,omitempty" yaml:"yAxis,omitempty"`
+	Hidden bool          `json:"hidden,omitempty" yaml:"hidden,omitempty"`
+}
+
+func (o *SeriesOverride) validate() error {
+	if err := o.Matcher.validate(); err != nil {
+		return err
+	}
+	if len(o.Color) > 0 && !colorRegexp.MatchString(o.Color) {
+		return fmt.Errorf("color %q is not a hexadecimal color code", o.Color)
+	}
+	switch o.LineStyle {
+	case "", "solid", "dashed", "dotted":
+	default:
+		return fmt.Errorf("unknown lineStyle %q", o.LineStyle)
+	}
+	if o.LineWidth != 0 && (o.LineWidth < 0.25 || o.LineWidth > 3) {
+		return fmt.Errorf("lineWidth must be between 0.25 and 3")
+	}
+	if o.AreaOpacity != nil && (*o.AreaOpacity < 0 || *o.AreaOpacity > 1) {
+		return fmt.Errorf("areaOpacity must be between 0 and 1")
+	}
+	switch o.YAxis {
+	case "", LeftYAxis, RightYAxis:
+	default:
+		return fmt.Errorf("unknown yAxis %q", o.YAxis)
+	}
+	return nil
+}
+
+// AnnotationMapping names the fields of the query results giving the events of an annotation. For a log query, they
+// are labels of the entries (the columns of a ClickHouse row). For a time series query, they are labels of the series,
+// every non-zero point being an event.
+type AnnotationMapping struct {
+	// Time is the field holding the start time of the event, the timestamp of the entry or point by default.
+	Time string `json:"time,omitempty" yaml:"time,omitempty"`
+	// EndTime is the field holding the end time of the event. An event with an end time is a time window.
+	EndTime string `json:"endTime,omitempty" yaml:"endTime,omitempty"`
+	// Text is the field holding the text of the event, the log line or the series name by default.
+	Text string   `json:"text,omitempty" yaml:"text,omitempty"`
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// Annotation overlays on the chart the events (deploys, incidents...) given by a time series or log query.
+type Annotation struct {
+	Name string `json:"name" yaml:"name"`
+	// Enabled is a pointer since an annotation is enabled by default. Disabled annotations are not queried.
+	Enabled *bool              `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Color   string             `json:"color,omitempty" yaml:"color,omitempty"`
+	Query   v1.Query           `json:"query" yaml:"query"`
+	Mapping *AnnotationMapping `json:"mapping,omitempty" yaml:"mapping,omitempty"`
+}
+
+func (a *Annotation) validate() error {
+	if len(a.Name) == 0 {
+		return fmt.Errorf("annotation name cannot be empty")
+	}
+	if len(a.Color) > 0 && !colorRegexp.MatchString(a.Color) {
+		return fmt.Errorf("color %q of the annotation %q is not a hexadecimal color code", a.Color, a.Name)
+	}
+	switch plugin.Kind(a.Query.Kind) {
+	case plugin.KindTimeSeriesQuery, plugin.KindLogQuery:
+	default:
+		return fmt.Errorf("the query of the annotation %q must be a %s or a %s, not a %q", a.Name, plugin.KindTimeSeriesQuery, plugin.KindLogQuery, a.Query.Kind)
+	}
+	return nil
 }
 
 type Option func(plugin *Builder) error
@@ -136,10 +359,8 @@ func create(options ...Option) (Builder, error) {
 		PluginSpec: PluginSpec{},
 	}
 
-	for _, opt := range options {
-		if err := opt(builder); err != nil {
-			return *builder, err
-		}
+	if err := option.ApplyAndValidate(PluginKind, builder, options, builder.validate); err != nil {
+		return *builder, err
 	}
 
 	return *builder, nil
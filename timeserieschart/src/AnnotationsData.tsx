@@ -0,0 +1,69 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+import { ReactElement, useMemo } from 'react';
+import { LogData, TimeSeriesData } from '@perses-dev/core';
+import { DataQueriesProvider, useDataQueries } from '@perses-dev/plugin-system';
+import type { LineSeriesOption } from 'echarts/charts';
+import { AnnotationOptions } from './time-series-chart-model';
+import { getAnnotationSeries, getLogAnnotationEvents, getTimeSeriesAnnotationEvents } from './utils/annotations';
+
+export interface AnnotationsDataProps {
+  // Only the enabled annotations
+  annotations: AnnotationOptions[];
+  // Receives a series per annotation, drawing its events
+  children: (annotationSeries: LineSeriesOption[]) => ReactElement;
+}
+
+/**
+ * Runs the queries of the annotations and gives the series drawing their events to its children.
+ */
+export function AnnotationsData({ annotations, children }: AnnotationsDataProps): ReactElement {
+  const definitions = useMemo(
+    () =>
+      annotations.map((annotation) => ({
+        kind: annotation.query.spec.plugin.kind,
+        spec: annotation.query.spec.plugin.spec,
+      })),
+    [annotations]
+  );
+
+  return (
+    <DataQueriesProvider definitions={definitions} options={{ mode: 'range' }}>
+      <AnnotationsEvents annotations={annotations}>{children}</AnnotationsEvents>
+    </DataQueriesProvider>
+  );
+}
+
+function AnnotationsEvents({ annotations, children }: AnnotationsDataProps): ReactElement {
+  const { queryResults: timeSeriesResults } = useDataQueries('TimeSeriesQuery');
+  const { queryResults: logResults } = useDataQueries('LogQuery');
+
+  const annotationSeries = useMemo(() => {
+    // The results of a kind of query are in the order of the definitions of this kind
+    const timeSeriesAnnotations = annotations.filter((annotation) => annotation.query.kind === 'TimeSeriesQuery');
+    const logAnnotations = annotations.filter((annotation) => annotation.query.kind === 'LogQuery');
+    const events = [
+      ...timeSeriesAnnotations.flatMap((annotation, i) =>
+        getTimeSeriesAnnotationEvents(annotation, timeSeriesResults[i]?.data as TimeSeriesData | undefined)
+      ),
+      ...logAnnotations.flatMap((annotation, i) =>
+        getLogAnnotationEvents(annotation, logResults[i]?.data as { logs?: LogData } | undefined)
+      ),
+    ];
+    return getAnnotationSeries(events);
+  }, [annotations, timeSeriesResults, logResults]);
+
+  return children(annotationSeries);
+}
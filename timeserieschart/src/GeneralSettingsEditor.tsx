@@ -27,6 +27,8 @@ import {
   DEFAULT_VISUAL,
   DEFAULT_Y_AXIS,
   TimeSeriesChartOptionsEditorProps,
+  getYAxes,
+  migrateYAxis,
 } from './time-series-chart-model';
 import { VisualOptionsEditor, VisualOptionsEditorProps } from './VisualOptionsEditor';
 import { YAxisOptionsEditor, YAxisOptionsEditorProps } from './YAxisOptionsEditor';
@@ -51,10 +53,21 @@ export function TimeSeriesChartGeneralSettings(props: TimeSeriesChartOptionsEdit
     );
   };
 
-  const handleYAxisChange: YAxisOptionsEditorProps['onChange'] = (newYAxis) => {
+  const { left: leftYAxis, right: rightYAxis } = getYAxes(value);
+
+  // Editing an axis migrates the legacy yAxis to yAxes.left
+  const handleLeftYAxisChange: YAxisOptionsEditorProps['onChange'] = (newYAxis) => {
     onChange(
-      produce(value, (draft: TimeSeriesChartOptions) => {
-        draft.yAxis = newYAxis;
+      produce(migrateYAxis(value), (draft: TimeSeriesChartOptions) => {
+        draft.yAxes = { ...draft.yAxes, left: newYAxis };
+      })
+    );
+  };
+
+  const handleRightYAxisChange: YAxisOptionsEditorProps['onChange'] = (newYAxis) => {
+    onChange(
+      produce(migrateYAxis(value), (draft: TimeSeriesChartOptions) => {
+        draft.yAxes = { ...draft.yAxes, right: newYAxis };
       })
     );
   };
@@ -74,7 +87,12 @@ export function TimeSeriesChartGeneralSettings(props: TimeSeriesChartOptionsEdit
         <VisualOptionsEditor value={value.visual ?? DEFAULT_VISUAL} onChange={handleVisualChange} />
       </OptionsEditorColumn>
       <OptionsEditorColumn>
-        <YAxisOptionsEditor value={value.yAxis ?? DEFAULT_Y_AXIS} onChange={handleYAxisChange} />
+        <YAxisOptionsEditor title="Y Axis" value={leftYAxis ?? DEFAULT_Y_AXIS} onChange={handleLeftYAxisChange} />
+        <YAxisOptionsEditor
+          title="Right Y Axis"
+          value={rightYAxis ?? DEFAULT_Y_AXIS}
+          onChange={handleRightYAxisChange}
+        />
       </OptionsEditorColumn>
       <OptionsEditorColumn>
         <ThresholdsEditor hideDefault thresholds={value.thresholds} onChange={handleThresholdsChange} />
@@ -87,6 +105,7 @@ export function TimeSeriesChartGeneralSettings(props: TimeSeriesChartOptionsEdit
                 produce(value, (draft: TimeSeriesChartOptions) => {
                   // reset button removes all general panel options
                   draft.yAxis = undefined;
+                  draft.yAxes = undefined;
                   draft.legend = undefined;
                   draft.visual = undefined;
                   draft.thresholds = undefined;
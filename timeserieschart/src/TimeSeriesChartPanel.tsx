@@ -14,6 +14,7 @@
 import { ReactElement, useMemo, useRef, useState } from 'react';
 import { Box, useTheme } from '@mui/material';
 import type { GridComponentOption } from 'echarts';
+import type { LineSeriesOption } from 'echarts/charts';
 import merge from 'lodash/merge';
 import {
   getTimeSeriesValues,
@@ -53,8 +54,10 @@ import {
   TimeSeriesChartOptions,
   DEFAULT_FORMAT,
   DEFAULT_VISUAL,
+  PERCENT_STACK_FORMAT,
   THRESHOLD_PLOT_INTERVAL,
   QuerySettingsOptions,
+  getYAxes,
 } from './time-series-chart-model';
 import {
   getTimeSeries,
@@ -62,8 +65,11 @@ import {
   convertPanelYAxis,
   getThresholdSeries,
   convertPercentThreshold,
+  getPercentStackedSeries,
 } from './utils/data-transform';
 import { getSeriesColor } from './utils/palette-gen';
+import { getSeriesOverride } from './utils/series-overrides';
+import { AnnotationsData } from './AnnotationsData';
 import { TimeSeriesChartBase } from './TimeSeriesChartBase';
 
 export type TimeSeriesChartProps = PanelProps<TimeSeriesChartOptions, TimeSeriesData>;
@@ -79,10 +85,12 @@ export type TimeSeriesChartProps = PanelProps<TimeSeriesChartOptions, TimeSeries
 
 export function TimeSeriesChartPanel(props: TimeSeriesChartProps): ReactElement | null {
   const {
-    spec: { thresholds, yAxis, tooltip, querySettings: querySettingsList },
+    spec: { thresholds, tooltip, querySettings: querySettingsList, seriesOverrides },
     contentDimensions,
     queryResults,
   } = props;
+  // The legacy yAxis is read as the left axis
+  const { left: yAxis, right: rightYAxis } = getYAxes(props.spec);
   const chartsTheme = useChartsTheme();
   const muiTheme = useTheme();
   const chartId = useId('time-series-panel');
@@ -113,8 +121,12 @@ export function TimeSeriesChartPanel(props: TimeSeriesChartProps): ReactElement
       : undefined;
   }, [props.spec.legend]);
 
-  // TODO: add support for y_axis_alt.format
-  const format = props.spec.yAxis?.format ?? DEFAULT_FORMAT;
+  const format = yAxis?.format ?? DEFAULT_FORMAT;
+  // The right axis uses the format of the left axis unless it has its own
+  const rightFormat = rightYAxis?.format ?? format;
+  // The series stacked as percentages are plotted as percentages whatever the unit of their values, which the legend
+  // values keep
+  const chartFormat = props.spec.visual?.stack === 'percent' ? PERCENT_STACK_FORMAT : format;
 
   // ensures there are fallbacks for unset properties since most
   // users should not need to customize visual display
@@ -129,7 +141,7 @@ export function TimeSeriesChartPanel(props: TimeSeriesChartProps): ReactElement
 
   // Collect unique formats from query settings that differ from the base format
   // These will create additional Y axes on the right side
-  const { additionalFormats, formatToYAxisIndex, seriesFormatMap } = useMemo(() => {
+  const { additionalFormats, formatToYAxisIndex, seriesFormatMap, rightYAxisIndex } = useMemo(() => {
     const baseUnit = format?.unit ?? 'decimal';
     const additionalFormats: Array<typeof format> = [];
     const formatToYAxisIndex = new Map<string, number>();
@@ -150,8 +162,18 @@ export function TimeSeriesChartPanel(props: TimeSeriesChartProps): ReactElement
       }
     }
 
-    return { additionalFormats, formatToYAxisIndex, seriesFormatMap };
-  }, [format, querySettingsList]);
+    // The right Y axis is only added when query settings or series overrides bind series to it
+    let rightYAxisIndex: number | undefined;
+    if (
+      querySettingsList?.some((qs) => qs.yAxis === 'right') ||
+      seriesOverrides?.some((override) => override.yAxis === 'right')
+    ) {
+      rightYAxisIndex = 1 + additionalFormats.length;
+      additionalFormats.push(rightFormat);
+    }
+
+    return { additionalFormats, formatToYAxisIndex, seriesFormatMap, rightYAxisIndex };
+  }, [format, rightFormat, querySettingsList, seriesOverrides]);
 
   const [selectedLegendItems, setSelectedLegendItems] = useState<SelectedLegendItemState>('ALL');
   const [legendSorting, setLegendSorting] = useState<NonNullable<LegendProps['tableProps']>['sorting']>();
@@ -166,6 +188,7 @@ export function TimeSeriesChartPanel(props: TimeSeriesChartProps): ReactElement
     legendItems,
     seriesFormatMap: computedSeriesFormatMap,
     maxValuesByFormat,
+    rightYAxisMax,
   } = useMemo(() => {
     const timeScale = getCommonTimeScaleForQueries(queryResults);
     if (timeScale === undefined) {
@@ -174,6 +197,7 @@ export function TimeSeriesChartPanel(props: TimeSeriesChartProps): ReactElement
         timeSeriesMapping: [],
         seriesFormatMap: new Map(),
         maxValuesByFormat: new Map<string, number>(),
+        rightYAxisMax: 0,
       };
     }
 
@@ -186,6 +210,7 @@ export function TimeSeriesChartPanel(props: TimeSeriesChartProps): ReactElement
 
     // Track max values for each format unit (used for dynamic Y axis offset calculation)
     const maxValuesByFormat = new Map<string, number>();
+    let rightYAxisMax = 0;
 
     // Index is counted across multiple queries which ensures the categorical color palette does not reset for every query
     let seriesIndex = 0;
@@ -216,17 +241,27 @@ export function TimeSeriesChartPanel(props: TimeSeriesChartProps): ReactElement
           // Format is determined by seriesNameFormat in query spec
           const formattedSeriesName = timeSeries.formattedName ?? timeSeries.name;
 
+          const seriesOverride = getSeriesOverride(seriesOverrides, formattedSeriesName, timeSeries.labels);
+          if (seriesOverride?.hidden) {
+            // Hidden series are neither drawn nor listed in the legend, but keep their palette color so the other
+            // series don't change color.
+            seriesIndex++;
+            continue;
+          }
+
           // Color is used for line, tooltip, and legend
-          const seriesColor = getSeriesColor({
-            // ECharts type for color is not always an array but it is always an array in ChartsProvider
-            categoricalPalette: categoricalPalette as string[],
-            visual,
-            muiPrimaryColor: muiTheme.palette.primary.main,
-            seriesName: formattedSeriesName,
-            seriesIndex,
-            querySettings: querySettings,
-            queryHasMultipleResults: (queryResults[queryIndex]?.data?.series?.length ?? 0) > 1,
-          });
+          const seriesColor =
+            seriesOverride?.color ??
+            getSeriesColor({
+              // ECharts type for color is not always an array but it is always an array in ChartsProvider
+              categoricalPalette: categoricalPalette as string[],
+              visual,
+              muiPrimaryColor: muiTheme.palette.primary.main,
+              seriesName: formattedSeriesName,
+              seriesIndex,
+              querySettings: querySettings,
+              queryHasMultipleResults: (queryResults[queryIndex]?.data?.series?.length ?? 0) > 1,
+            });
 
           // We add a unique id for the chart to disambiguate items across charts
           // when there are multiple on the page.
@@ -249,8 +284,21 @@ export function TimeSeriesChartPanel(props: TimeSeriesChartProps): ReactElement
             const datasetIndex = timeChartData.length;
 
             // Determine yAxisIndex based on the query's format setting
-            const queryFormat = querySettings?.format;
-            const yAxisIndex = queryFormat?.unit ? (formatToYAxisIndex.get(queryFormat.unit) ?? 0) : 0;
+            let queryFormat = querySettings?.format;
+            let yAxisIndex = queryFormat?.unit ? (formatToYAxisIndex.get(queryFormat.unit) ?? 0) : 0;
+            // The Y axis chosen by an override, then by the query settings, takes precedence over the one of the
+            // query format
+            const axisPosition = seriesOverride?.yAxis ?? querySettings?.yAxis;
+            if (axisPosition === 'left') {
+              yAxisIndex = 0;
+              queryFormat = undefined;
+            } else if (axisPosition === 'right' && rightYAxisIndex !== undefined) {
+              yAxisIndex = rightYAxisIndex;
+              // the series is formatted like its axis in the tooltip
+              queryFormat = rightFormat;
+              const seriesMax = Math.max(...timeSeries.values.map((v) => Math.abs(v[1] ?? 0)));
+              rightYAxisMax = Math.max(rightYAxisMax, seriesMax);
+            }
 
             // Each series is stored as a separate dataset source.
             // https://apache.github.io/echarts-handbook/en/concepts/dataset/#how-to-reference-several-datasets
@@ -263,7 +311,8 @@ export function TimeSeriesChartPanel(props: TimeSeriesChartProps): ReactElement
                 timeScale,
                 seriesColor,
                 querySettings,
-                yAxisIndex
+                yAxisIndex,
+                seriesOverride
               )
             );
 
@@ -303,6 +352,21 @@ export function TimeSeriesChartPanel(props: TimeSeriesChartProps): ReactElement
       }
     }
 
+    // The series stacked as percentages are normalised together once all of them are known. Series moved to another
+    // stack group by an override are left as is.
+    if (visual.stack === 'percent') {
+      const percentIndexes = timeSeriesMapping.flatMap((series, index) => (series.stack === 'percent' ? [index] : []));
+      const percentSeries = getPercentStackedSeries(percentIndexes.flatMap((index) => timeChartData[index] ?? []));
+      percentIndexes.forEach((dataIndex, i) => {
+        const normalised = percentSeries[i];
+        if (normalised) {
+          timeChartData[dataIndex] = normalised;
+          // the tooltip formats them as percentages like the axis
+          seriesFormatMap.delete(String(timeSeriesMapping[dataIndex]?.id));
+        }
+      });
+    }
+
     // map thresholds only if there is at least one time series to avoid displaying thresholds without any data
     if (thresholds && thresholds.steps && timeChartData.length > 0) {
       // Convert how thresholds are defined in the panel spec to valid ECharts 'line' series.
@@ -348,6 +412,7 @@ export function TimeSeriesChartPanel(props: TimeSeriesChartProps): ReactElement
       legendItems,
       seriesFormatMap,
       maxValuesByFormat,
+      rightYAxisMax,
     };
   }, [
     queryResults,
@@ -356,6 +421,7 @@ export function TimeSeriesChartPanel(props: TimeSeriesChartProps): ReactElement
     legend,
     visual,
     querySettingsList,
+    seriesOverrides,
     yAxis?.max,
     yAxis?.min,
     categoricalPalette,
@@ -364,6 +430,8 @@ export function TimeSeriesChartPanel(props: TimeSeriesChartProps): ReactElement
     muiTheme.palette.primary.main,
     formatToYAxisIndex,
     seriesFormatMap,
+    rightYAxisIndex,
+    rightFormat,
   ]);
 
   // Create multiple Y axes if there are additional formats
@@ -373,12 +441,25 @@ export function TimeSeriesChartPanel(props: TimeSeriesChartProps): ReactElement
       return undefined; // Use single Y axis (default behavior)
     }
     // Build array of max values for each additional format (in order)
-    const maxValues = additionalFormats.map((fmt) => {
+    const maxValues = additionalFormats.map((fmt, index) => {
+      if (index + 1 === rightYAxisIndex) {
+        return rightYAxisMax || 1000;
+      }
       const unitKey = fmt.unit;
       return unitKey ? (maxValuesByFormat?.get(unitKey) ?? 1000) : 1000;
     });
-    return getFormattedMultipleYAxes(echartsYAxis, format, additionalFormats, maxValues);
-  }, [echartsYAxis, format, additionalFormats, maxValuesByFormat]);
+    const axes = getFormattedMultipleYAxes(echartsYAxis, chartFormat, additionalFormats, maxValues);
+    const rightAxis = rightYAxisIndex !== undefined ? axes[rightYAxisIndex] : undefined;
+    if (rightYAxisIndex !== undefined && rightAxis && rightYAxis) {
+      // Apply the scale and label of the right axis on top of the axis formatted for its unit
+      axes[rightYAxisIndex] = {
+        ...rightAxis,
+        ...convertPanelYAxis(rightYAxis),
+        name: rightYAxis.label || undefined,
+      };
+    }
+    return axes;
+  }, [echartsYAxis, chartFormat, additionalFormats, maxValuesByFormat, rightYAxisIndex, rightYAxisMax, rightYAxis]);
 
   // Translate the legend values into columns for the table legend.
   const legendColumns = useMemo(() => {
@@ -436,6 +517,12 @@ export function TimeSeriesChartPanel(props: TimeSeriesChartProps): ReactElement
         };
   }, [echartsYAxis.show, yAxis, additionalFormats.length]);
 
+  // Disabled annotations are not queried
+  const enabledAnnotations = useMemo(
+    () => (props.spec.annotations ?? []).filter((annotation) => annotation.enabled !== false),
+    [props.spec.annotations]
+  );
+
   if (adjustedContentDimensions === undefined) {
     return null;
   }
@@ -446,7 +533,7 @@ export function TimeSeriesChartPanel(props: TimeSeriesChartProps): ReactElement
   };
 
   // Used to opt in to ECharts trigger item which show subgroup data accurately
-  const isStackedBar = visual.display === 'bar' && visual.stack === 'all';
+  const isStackedBar = visual.display === 'bar' && (visual.stack === 'all' || visual.stack === 'percent');
 
   // Turn on tooltip pinning by default but opt out for stacked bar or if explicitly set in tooltip panel spec
   let enablePinning = true;
@@ -460,7 +547,7 @@ export function TimeSeriesChartPanel(props: TimeSeriesChartProps): ReactElement
     enablePinning,
   };
 
-  return (
+  const renderContent = (annotationSeries: LineSeriesOption[]): ReactElement => (
     <Box sx={{ padding: `${contentPadding}px` }}>
       <ContentWithLegend
         width={adjustedContentDimensions.width}
@@ -497,10 +584,12 @@ export function TimeSeriesChartPanel(props: TimeSeriesChartProps): ReactElement
                 ref={chartRef}
                 height={height}
                 data={timeChartData}
-                seriesMapping={timeSeriesMapping}
+                seriesMapping={
+                  annotationSeries.length > 0 ? [...timeSeriesMapping, ...annotationSeries] : timeSeriesMapping
+                }
                 timeScale={timeScale}
                 yAxis={multipleYAxes ?? echartsYAxis}
-                format={format}
+                format={chartFormat}
                 seriesFormatMap={computedSeriesFormatMap}
                 grid={gridOverrides}
                 isStackedBar={isStackedBar}
@@ -517,4 +606,9 @@ export function TimeSeriesChartPanel(props: TimeSeriesChartProps): ReactElement
       </ContentWithLegend>
     </Box>
   );
+
+  if (enabledAnnotations.length === 0) {
+    return renderContent([]);
+  }
+  return <AnnotationsData annotations={enabledAnnotations}>{renderContent}</AnnotationsData>;
 }
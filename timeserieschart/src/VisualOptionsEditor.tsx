@@ -77,7 +77,7 @@ export function VisualOptionsEditor({ value, onChange }: VisualOptionsEditorProp
                 stack: newValue.id === 'none' ? undefined : newValue.id, // stack is optional so remove property when 'None' is selected
               };
               // stacked area chart preset to automatically set area under a curve shading
-              if (newValue.id === 'all' && !value.areaOpacity) {
+              if ((newValue.id === 'all' || newValue.id === 'percent') && !value.areaOpacity) {
                 updatedValue.areaOpacity = 0.3;
               }
               onChange(updatedValue);
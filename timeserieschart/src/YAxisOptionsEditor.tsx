@@ -25,15 +25,16 @@ import {
 } from './time-series-chart-model';
 
 export interface YAxisOptionsEditorProps {
+  title?: string;
   value: TimeSeriesChartYAxisOptions;
   onChange: (yAxis: TimeSeriesChartYAxisOptions) => void;
 }
 
-export function YAxisOptionsEditor({ value, onChange }: YAxisOptionsEditorProps): ReactElement {
+export function YAxisOptionsEditor({ title = 'Y Axis', value, onChange }: YAxisOptionsEditorProps): ReactElement {
   const logBase = value.logBase ? LOG_BASE_CONFIG[value.logBase] : undefined;
 
   return (
-    <OptionsEditorGroup title="Y Axis">
+    <OptionsEditorGroup title={title}>
       <OptionsEditorControl
         label="Show"
         control={
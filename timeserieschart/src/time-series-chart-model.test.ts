@@ -0,0 +1,41 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+import { getYAxes, migrateYAxis, TimeSeriesChartOptions } from './time-series-chart-model';
+
+describe('getYAxes', () => {
+  it('should read the legacy yAxis as the left axis', () => {
+    expect(getYAxes({ yAxis: { label: 'legacy' } })).toEqual({ left: { label: 'legacy' }, right: undefined });
+  });
+
+  it('should prefer yAxes.left to the legacy yAxis', () => {
+    const options: TimeSeriesChartOptions = {
+      yAxis: { label: 'legacy' },
+      yAxes: { left: { label: 'left' }, right: { label: 'right' } },
+    };
+    expect(getYAxes(options)).toEqual({ left: { label: 'left' }, right: { label: 'right' } });
+  });
+});
+
+describe('migrateYAxis', () => {
+  it('should move the legacy yAxis to yAxes.left', () => {
+    const options: TimeSeriesChartOptions = { yAxis: { label: 'legacy' }, yAxes: { right: { label: 'right' } } };
+    expect(migrateYAxis(options)).toEqual({ yAxes: { left: { label: 'legacy' }, right: { label: 'right' } } });
+  });
+
+  it('should return the options without yAxis as is', () => {
+    const options: TimeSeriesChartOptions = { yAxes: { left: { label: 'left' } } };
+    expect(migrateYAxis(options)).toBe(options);
+  });
+});
@@ -11,7 +11,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-import { Definition, ThresholdOptions, FormatOptions } from '@perses-dev/core';
+import { Definition, ThresholdOptions, FormatOptions, QueryDefinition } from '@perses-dev/core';
 import { OptionsEditorProps, LegendSpecOptions } from '@perses-dev/plugin-system';
 
 /**
@@ -31,11 +31,17 @@ export interface TimeSeriesChartDefinition extends Definition<TimeSeriesChartOpt
  */
 export interface TimeSeriesChartOptions {
   legend?: LegendSpecOptions;
+  /**
+   * @deprecated use yAxes.left instead, yAxis is read as the left axis when yAxes.left is not set.
+   */
   yAxis?: TimeSeriesChartYAxisOptions;
+  yAxes?: TimeSeriesChartYAxesOptions;
   thresholds?: ThresholdOptions;
   visual?: TimeSeriesChartVisualOptions;
   tooltip?: TooltipSpecOptions;
   querySettings?: QuerySettingsOptions[];
+  seriesOverrides?: SeriesOverrideOptions[];
+  annotations?: AnnotationOptions[];
 }
 
 export interface QuerySettingsOptions {
@@ -45,8 +51,72 @@ export interface QuerySettingsOptions {
   lineStyle?: LineStyleType;
   areaOpacity?: number;
   format?: FormatOptions;
+  yAxis?: YAxisPosition;
 }
 
+export type LabelMatcherType = '=' | '!=' | '=~' | '!~';
+
+/**
+ * Matches a label of a series, like a PromQL label matcher. Regexes are fully anchored.
+ */
+export interface LabelMatcherOptions {
+  name: string;
+  value: string;
+  type?: LabelMatcherType;
+}
+
+/**
+ * Selects the series a series override applies to: by exact name, by a (fully anchored) regex on the name, or by labels.
+ */
+export type SeriesMatcherOptions = { name: string } | { nameRegex: string } | { labels: LabelMatcherOptions[] };
+
+export type YAxisPosition = 'left' | 'right';
+
+/**
+ * Styles the series selected by its matcher. When several overrides match a series, they are applied in order.
+ */
+export interface SeriesOverrideOptions {
+  matcher: SeriesMatcherOptions;
+  color?: string;
+  lineStyle?: LineStyleType;
+  lineWidth?: number;
+  areaOpacity?: number;
+  // Name of the stack group, the series of the same group are stacked together.
+  stack?: string;
+  yAxis?: YAxisPosition;
+  hidden?: boolean;
+}
+
+/**
+ * Names the fields of the query results that give the events of an annotation. For a log query, they are labels of the
+ * entries (the columns of a ClickHouse row). For a time series query, they are labels of the series, every non-zero
+ * point being an event.
+ */
+export interface AnnotationMappingOptions {
+  // Field holding the start time of the event, the timestamp of the entry or point by default
+  time?: string;
+  // Field holding the end time of the event, an event with an end time is a time window
+  endTime?: string;
+  // Field holding the text of the event, the log line or the series name by default
+  text?: string;
+  // Fields whose values are the tags of the event
+  tags?: string[];
+}
+
+/**
+ * Events (deploys, incidents...) overlaid on the chart, given by the results of a query of any query plugin.
+ */
+export interface AnnotationOptions {
+  name: string;
+  // Disabled annotations are not queried
+  enabled?: boolean;
+  color?: string;
+  query: QueryDefinition;
+  mapping?: AnnotationMappingOptions;
+}
+
+export const DEFAULT_ANNOTATION_COLOR = '#8e44ad';
+
 export type TimeSeriesChartOptionsEditorProps = OptionsEditorProps<TimeSeriesChartOptions>;
 
 export interface TimeSeriesChartYAxisOptions {
@@ -58,6 +128,33 @@ export interface TimeSeriesChartYAxisOptions {
   logBase?: LOG_BASE;
 }
 
+export interface TimeSeriesChartYAxesOptions {
+  left?: TimeSeriesChartYAxisOptions;
+  // The right axis is displayed when query settings or series overrides bind series to it.
+  right?: TimeSeriesChartYAxisOptions;
+}
+
+/**
+ * Returns the Y axes of the panel, the legacy yAxis being migrated to the left axis.
+ */
+export function getYAxes(options: Pick<TimeSeriesChartOptions, 'yAxis' | 'yAxes'>): TimeSeriesChartYAxesOptions {
+  return {
+    left: options.yAxes?.left ?? options.yAxis,
+    right: options.yAxes?.right,
+  };
+}
+
+/**
+ * Moves the legacy yAxis of the options to yAxes.left. Options without yAxis are returned as is.
+ */
+export function migrateYAxis(options: TimeSeriesChartOptions): TimeSeriesChartOptions {
+  if (options.yAxis === undefined) {
+    return options;
+  }
+  const { yAxis, ...rest } = options;
+  return { ...rest, yAxes: { ...rest.yAxes, left: rest.yAxes?.left ?? yAxis } };
+}
+
 export interface TooltipSpecOptions {
   enablePinning: boolean;
 }
@@ -157,13 +254,18 @@ export const VISUAL_CONFIG = {
 };
 
 // None is equivalent to undefined since stack is optional
-export type StackOptions = 'none' | 'all'; // TODO: add percent option support
+export type StackOptions = 'none' | 'all' | 'percent';
 
 export const STACK_CONFIG = {
   none: { label: 'None' },
   all: { label: 'All' },
-  // TODO: enable option after 'Percent' implemented
-  // percent: { label: 'Percent', disabled: true }, // hidden since not implemented yet
+  percent: { label: 'Percent' },
+};
+
+// The series stacked as percentages are plotted as percentages of the total of each timestamp
+export const PERCENT_STACK_FORMAT: FormatOptions = {
+  unit: 'percent',
+  decimalPlaces: 1,
 };
 
 export const STACK_OPTIONS = Object.entries(STACK_CONFIG).map(([id, config]) => {
@@ -0,0 +1,118 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+import { AnnotationOptions } from '../time-series-chart-model';
+import {
+  getAnnotationSeries,
+  getLogAnnotationEvents,
+  getTimeSeriesAnnotationEvents,
+  parseAnnotationTime,
+} from './annotations';
+
+const query: AnnotationOptions['query'] = {
+  kind: 'LogQuery',
+  spec: { plugin: { kind: 'ClickHouseLogQuery', spec: {} } },
+};
+
+describe('parseAnnotationTime', () => {
+  it('should convert the times to milliseconds', () => {
+    expect(parseAnnotationTime(1700000000)).toBe(1700000000000);
+    expect(parseAnnotationTime('1700000000.5')).toBe(1700000000500);
+    expect(parseAnnotationTime(1700000000000)).toBe(1700000000000);
+    expect(parseAnnotationTime('2023-11-14T22:13:20Z')).toBe(1700000000000);
+    expect(parseAnnotationTime('not a time')).toBeUndefined();
+    expect(parseAnnotationTime(undefined)).toBeUndefined();
+  });
+});
+
+describe('getLogAnnotationEvents', () => {
+  it('should map the fields of the entries', () => {
+    const annotation: AnnotationOptions = {
+      name: 'incidents',
+      color: '#f00',
+      query,
+      mapping: { time: 'start', endTime: 'end', text: 'title', tags: ['severity', 'team'] },
+    };
+    const events = getLogAnnotationEvents(annotation, {
+      logs: {
+        totalCount: 2,
+        entries: [
+          {
+            timestamp: 1,
+            line: 'raw',
+            labels: { start: '1700000000', end: '1700000600', title: 'API down', severity: 'critical' },
+          },
+          { timestamp: 1, line: 'raw', labels: { title: 'no start time' } },
+        ],
+      },
+    });
+    expect(events).toEqual([
+      {
+        annotation: 'incidents',
+        color: '#f00',
+        time: 1700000000000,
+        endTime: 1700000600000,
+        text: 'API down',
+        tags: ['critical'],
+      },
+    ]);
+  });
+
+  it('should default to the timestamp and the line of the entries', () => {
+    const events = getLogAnnotationEvents(
+      { name: 'deploys', query },
+      { logs: { totalCount: 1, entries: [{ timestamp: 1700000000, line: 'deployed v1.2.0', labels: {} }] } }
+    );
+    expect(events).toEqual([
+      { annotation: 'deploys', color: '#8e44ad', time: 1700000000000, text: 'deployed v1.2.0', tags: [] },
+    ]);
+  });
+});
+
+describe('getTimeSeriesAnnotationEvents', () => {
+  it('should create an event for each non-zero point', () => {
+    const events = getTimeSeriesAnnotationEvents(
+      { name: 'restarts', query, mapping: { tags: ['pod'] } },
+      {
+        series: [
+          {
+            name: 'changes(kube_pod_start_time[1m])',
+            formattedName: 'api-0',
+            labels: { pod: 'api-0' },
+            values: [
+              [1700000000000, 0],
+              [1700000060000, 1],
+              [1700000120000, null],
+            ],
+          },
+        ],
+      }
+    );
+    expect(events).toEqual([
+      { annotation: 'restarts', color: '#8e44ad', time: 1700000060000, text: 'api-0', tags: ['api-0'] },
+    ]);
+  });
+});
+
+describe('getAnnotationSeries', () => {
+  it('should draw the points as lines and the windows as areas', () => {
+    const series = getAnnotationSeries([
+      { annotation: 'deploys', color: '#00f', time: 1000, text: 'v1', tags: ['api'] },
+      { annotation: 'deploys', color: '#00f', time: 2000, endTime: 3000, text: 'rollout', tags: [] },
+    ]);
+    expect(series).toHaveLength(1);
+    expect(series[0]?.markLine?.data).toEqual([{ name: 'v1 [api]', xAxis: 1000 }]);
+    expect(series[0]?.markArea?.data).toEqual([[{ name: 'rollout', xAxis: 2000 }, { xAxis: 3000 }]]);
+  });
+});
@@ -0,0 +1,148 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+import { LogData, TimeSeriesData } from '@perses-dev/core';
+import { LineSeriesOption } from 'echarts/charts';
+import { AnnotationOptions, DEFAULT_ANNOTATION_COLOR } from '../time-series-chart-model';
+
+/**
+ * An event of an annotation: a point in time, or a time window when it has an end time.
+ */
+export interface AnnotationEvent {
+  annotation: string;
+  color: string;
+  // Times are in milliseconds
+  time: number;
+  endTime?: number;
+  text: string;
+  tags: string[];
+}
+
+// Timestamps below this value are in seconds (it's in 1973 in milliseconds, in 5138 in seconds).
+const SECONDS_THRESHOLD = 1e11;
+
+/**
+ * Converts a time of a query result to milliseconds. Numbers (and numeric strings) in seconds or milliseconds are
+ * supported, as well as dates parsable by Date.parse. Returns undefined when the value is not a time.
+ */
+export function parseAnnotationTime(value: unknown): number | undefined {
+  if (value === undefined || value === null || value === '') {
+    return undefined;
+  }
+  const num = typeof value === 'number' ? value : Number(value);
+  if (!Number.isNaN(num)) {
+    return num < SECONDS_THRESHOLD ? num * 1000 : num;
+  }
+  const date = Date.parse(String(value));
+  return Number.isNaN(date) ? undefined : date;
+}
+
+function getTags(annotation: AnnotationOptions, labels: Record<string, string> = {}): string[] {
+  return (annotation.mapping?.tags ?? []).flatMap((field) => (labels[field] ? [labels[field]] : []));
+}
+
+/**
+ * Returns the events of a log query: an event for each entry.
+ */
+export function getLogAnnotationEvents(annotation: AnnotationOptions, data?: { logs?: LogData }): AnnotationEvent[] {
+  const events: AnnotationEvent[] = [];
+  const mapping = annotation.mapping ?? {};
+  for (const entry of data?.logs?.entries ?? []) {
+    const labels = entry.labels ?? {};
+    const time = parseAnnotationTime(mapping.time ? labels[mapping.time] : entry.timestamp);
+    if (time === undefined) {
+      continue;
+    }
+    const endTime = mapping.endTime ? parseAnnotationTime(labels[mapping.endTime]) : undefined;
+    events.push({
+      annotation: annotation.name,
+      color: annotation.color ?? DEFAULT_ANNOTATION_COLOR,
+      time,
+      endTime: endTime !== undefined && endTime > time ? endTime : undefined,
+      text: (mapping.text ? labels[mapping.text] : undefined) ?? entry.line,
+      tags: getTags(annotation, labels),
+    });
+  }
+  return events;
+}
+
+/**
+ * Returns the events of a time series query: an event for each non-zero point, like the result of changes().
+ */
+export function getTimeSeriesAnnotationEvents(annotation: AnnotationOptions, data?: TimeSeriesData): AnnotationEvent[] {
+  const events: AnnotationEvent[] = [];
+  const mapping = annotation.mapping ?? {};
+  for (const series of data?.series ?? []) {
+    const labels = series.labels ?? {};
+    const text = (mapping.text ? labels[mapping.text] : undefined) ?? series.formattedName ?? series.name;
+    for (const [timestamp, value] of series.values) {
+      if (value === null || value === 0) {
+        continue;
+      }
+      events.push({
+        annotation: annotation.name,
+        color: annotation.color ?? DEFAULT_ANNOTATION_COLOR,
+        time: timestamp,
+        text,
+        tags: getTags(annotation, labels),
+      });
+    }
+  }
+  return events;
+}
+
+function getEventLabel(event: AnnotationEvent): string {
+  return event.tags.length > 0 ? `${event.text} [${event.tags.join(', ')}]` : event.text;
+}
+
+/**
+ * Returns a series per annotation drawing its events: a vertical line for each point in time and a shaded area for
+ * each time window. The series have no data, like the pinned crosshair.
+ */
+export function getAnnotationSeries(events: AnnotationEvent[]): LineSeriesOption[] {
+  const byAnnotation = new Map<string, AnnotationEvent[]>();
+  for (const event of events) {
+    byAnnotation.set(event.annotation, [...(byAnnotation.get(event.annotation) ?? []), event]);
+  }
+  return Array.from(byAnnotation.entries()).map(([name, annotationEvents]) => {
+    const color = annotationEvents[0]?.color ?? DEFAULT_ANNOTATION_COLOR;
+    return {
+      type: 'line',
+      id: `annotation-${name}`,
+      name,
+      data: [],
+      silent: false,
+      markLine: {
+        symbol: 'none',
+        animation: false,
+        lineStyle: { color, type: 'dashed', width: 1 },
+        label: { show: false },
+        emphasis: { label: { show: true, position: 'insideEndTop', formatter: '{b}' } },
+        data: annotationEvents
+          .filter((event) => event.endTime === undefined)
+          .map((event) => ({ name: getEventLabel(event), xAxis: event.time })),
+      },
+      markArea: {
+        silent: false,
+        animation: false,
+        itemStyle: { color, opacity: 0.15 },
+        label: { show: false },
+        emphasis: { label: { show: true, position: 'insideTop' } },
+        data: annotationEvents
+          .filter((event) => event.endTime !== undefined)
+          .map((event) => [{ name: getEventLabel(event), xAxis: event.time }, { xAxis: event.endTime }]),
+      },
+    };
+  });
+}
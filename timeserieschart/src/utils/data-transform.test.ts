@@ -13,7 +13,7 @@
 
 import { LegacyTimeSeries } from '@perses-dev/components';
 import { TimeSeriesChartYAxisOptions } from '../time-series-chart-model';
-import { convertPercentThreshold, convertPanelYAxis, roundDown } from './data-transform';
+import { convertPercentThreshold, convertPanelYAxis, getPercentStackedSeries, roundDown } from './data-transform';
 
 const MAX_VALUE = 120;
 const MOCK_ECHART_TIME_SERIES_DATA: LegacyTimeSeries[] = [
@@ -144,3 +144,35 @@ describe('roundDown', () => {
     expect(roundDown(value)).toEqual(expected);
   });
 });
+
+describe('getPercentStackedSeries', () => {
+  it('should normalise each timestamp to 100 percent', () => {
+    const stacked = getPercentStackedSeries([
+      { name: 'a', values: [[1000, 1], [2000, 3], [3000, 0]] },
+      { name: 'b', values: [[1000, 3], [2000, 1], [3000, 0]] },
+    ]);
+    expect(stacked).toEqual([
+      { name: 'a', values: [[1000, 25], [2000, 75], [3000, 0]] },
+      { name: 'b', values: [[1000, 75], [2000, 25], [3000, 0]] },
+    ]);
+  });
+
+  it('should leave nulls out of the total', () => {
+    const stacked = getPercentStackedSeries([
+      { name: 'a', values: [[1000, null], [2000, 2]] },
+      { name: 'b', values: [[1000, 5], [2000, 2]] },
+    ]);
+    expect(stacked.map((s) => s.values)).toEqual([
+      [[1000, null], [2000, 50]],
+      [[1000, 100], [2000, 50]],
+    ]);
+  });
+
+  it('should keep the sign of negative values', () => {
+    const stacked = getPercentStackedSeries([
+      { name: 'in', values: [[1000, 3]] },
+      { name: 'out', values: [[1000, -1]] },
+    ]);
+    expect(stacked.map((s) => s.values)).toEqual([[[1000, 75]], [[1000, -25]]]);
+  });
+});
@@ -41,6 +41,7 @@ import {
   TimeSeriesChartYAxisOptions,
   LineStyleType,
 } from '../time-series-chart-model';
+import { ResolvedSeriesOverride } from './series-overrides';
 
 export type RunningQueriesState = ReturnType<typeof useTimeSeriesQueries>;
 
@@ -75,9 +76,16 @@ export function getTimeSeries(
   timeScale: TimeScale,
   paletteColor: string,
   querySettings?: { lineStyle?: LineStyleType; areaOpacity?: number },
-  yAxisIndex?: number
+  yAxisIndex?: number,
+  seriesOverride?: ResolvedSeriesOverride
 ): TimeSeriesOption {
-  const lineWidth = visual.lineWidth ?? DEFAULT_LINE_WIDTH;
+  const lineWidth = seriesOverride?.lineWidth ?? visual.lineWidth ?? DEFAULT_LINE_WIDTH;
+  const lineStyle = (seriesOverride?.lineStyle ?? querySettings?.lineStyle ?? visual.lineStyle) as LineStyleType;
+  const areaOpacity =
+    seriesOverride?.areaOpacity ?? querySettings?.areaOpacity ?? visual.areaOpacity ?? DEFAULT_AREA_OPACITY;
+  // A stack group set by an override takes precedence over stacking all the series.
+  const stack =
+    seriesOverride?.stack ?? (visual.stack === 'all' || visual.stack === 'percent' ? visual.stack : undefined);
   const pointRadius = visual.pointRadius ?? DEFAULT_POINT_RADIUS;
 
   // Shows datapoint symbols when selected time range is roughly 15 minutes or less
@@ -95,7 +103,7 @@ export function getTimeSeries(
       datasetIndex,
       name: formattedName,
       color: paletteColor,
-      stack: visual.stack === 'all' ? visual.stack : undefined,
+      stack,
       yAxisIndex: yAxisIndex,
       label: {
         show: false,
@@ -111,7 +119,7 @@ export function getTimeSeries(
     name: formattedName,
     connectNulls: visual.connectNulls ?? DEFAULT_CONNECT_NULLS,
     color: paletteColor,
-    stack: visual.stack === 'all' ? visual.stack : undefined,
+    stack,
     yAxisIndex: yAxisIndex,
     sampling: 'lttb',
     progressiveThreshold: OPTIMIZED_MODE_SERIES_LIMIT, // https://echarts.apache.org/en/option.html#series-lines.progressiveThreshold
@@ -120,19 +128,19 @@ export function getTimeSeries(
     symbolSize: pointRadius,
     lineStyle: {
       width: lineWidth,
-      type: (querySettings?.lineStyle ?? visual.lineStyle) as LineStyleType,
+      type: lineStyle,
     },
     areaStyle: {
-      opacity: querySettings?.areaOpacity ?? visual.areaOpacity ?? DEFAULT_AREA_OPACITY,
+      opacity: areaOpacity,
     },
     // https://echarts.apache.org/en/option.html#series-line.emphasis
     emphasis: {
       focus: 'series',
-      disabled: visual.areaOpacity !== undefined && visual.areaOpacity > 0, // prevents flicker when moving cursor between shaded regions
+      disabled: areaOpacity > 0, // prevents flicker when moving cursor between shaded regions
       lineStyle: {
         width: lineWidth + 1,
         opacity: 1,
-        type: visual.lineStyle,
+        type: lineStyle,
       },
     },
     selectedMode: 'single',
@@ -146,7 +154,7 @@ export function getTimeSeries(
       lineStyle: {
         width: lineWidth,
         opacity: BLUR_FADEOUT_OPACITY,
-        type: visual.lineStyle,
+        type: lineStyle,
       },
     },
   };
@@ -297,3 +305,29 @@ export function roundDown(num: number): number {
   const firstDigit = Math.floor(num / Math.pow(10, magnitude));
   return firstDigit * Math.pow(10, magnitude);
 }
+
+/**
+ * Normalises the series stacked as percentages so that, at each timestamp, their values are percentages of the total
+ * of the absolute values. Null values are left out of the total and stay null, negative values keep their sign so they
+ * are stacked below zero, and values are 0 at a timestamp where the total is 0.
+ */
+export function getPercentStackedSeries(series: TimeSeries[]): TimeSeries[] {
+  const totals = new Map<number, number>();
+  for (const { values } of series) {
+    for (const [timestamp, value] of values) {
+      if (value !== null && Number.isFinite(value)) {
+        totals.set(timestamp, (totals.get(timestamp) ?? 0) + Math.abs(value));
+      }
+    }
+  }
+  return series.map((s) => ({
+    ...s,
+    values: s.values.map(([timestamp, value]): TimeSeriesValueTuple => {
+      if (value === null || !Number.isFinite(value)) {
+        return [timestamp, null];
+      }
+      const total = totals.get(timestamp) ?? 0;
+      return [timestamp, total === 0 ? 0 : (value / total) * 100];
+    }),
+  }));
+}
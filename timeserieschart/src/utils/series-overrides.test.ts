@@ -0,0 +1,80 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+import { SeriesOverrideOptions } from '../time-series-chart-model';
+import { getSeriesOverride, matchesSeries } from './series-overrides';
+
+describe('matchesSeries', () => {
+  const labels = { __name__: 'up', job: 'node', instance: 'localhost:9100' };
+
+  it('should match the exact name', () => {
+    expect(matchesSeries({ name: 'p99' }, 'p99')).toBe(true);
+    expect(matchesSeries({ name: 'p99' }, 'p999')).toBe(false);
+  });
+
+  it('should match the whole name with a regex', () => {
+    expect(matchesSeries({ nameRegex: 'p9.*' }, 'p99')).toBe(true);
+    expect(matchesSeries({ nameRegex: 'p9.*' }, 'up95')).toBe(false);
+    expect(matchesSeries({ nameRegex: 'p9(' }, 'p9(')).toBe(false);
+  });
+
+  it('should match all the label matchers', () => {
+    expect(matchesSeries({ labels: [{ name: '__name__', value: 'up' }] }, 'up', labels)).toBe(true);
+    expect(
+      matchesSeries(
+        {
+          labels: [
+            { name: '__name__', value: 'up' },
+            { name: 'job', value: 'node|api', type: '=~' },
+          ],
+        },
+        'up',
+        labels
+      )
+    ).toBe(true);
+    expect(
+      matchesSeries(
+        {
+          labels: [
+            { name: '__name__', value: 'up' },
+            { name: 'job', value: 'node', type: '!=' },
+          ],
+        },
+        'up',
+        labels
+      )
+    ).toBe(false);
+    expect(matchesSeries({ labels: [{ name: 'env', value: 'prod.*', type: '!~' }] }, 'up', labels)).toBe(true);
+    expect(matchesSeries({ labels: [{ name: 'env', value: '' }] }, 'up', labels)).toBe(true);
+  });
+});
+
+describe('getSeriesOverride', () => {
+  const overrides: SeriesOverrideOptions[] = [
+    { matcher: { nameRegex: 'p.*' }, color: '#00f', lineStyle: 'dotted' },
+    { matcher: { name: 'p99' }, color: '#f00', lineStyle: 'dashed' },
+    { matcher: { name: 'errors' }, yAxis: 'right', stack: 'errors' },
+  ];
+
+  it('should merge the matching overrides in order', () => {
+    expect(getSeriesOverride(overrides, 'p99')).toEqual({ color: '#f00', lineStyle: 'dashed' });
+    expect(getSeriesOverride(overrides, 'p50')).toEqual({ color: '#00f', lineStyle: 'dotted' });
+    expect(getSeriesOverride(overrides, 'errors')).toEqual({ yAxis: 'right', stack: 'errors' });
+  });
+
+  it('should return undefined when no override matches', () => {
+    expect(getSeriesOverride(overrides, 'requests')).toBeUndefined();
+    expect(getSeriesOverride(undefined, 'p99')).toBeUndefined();
+  });
+});
@@ -0,0 +1,90 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+import { Labels } from '@perses-dev/core';
+import { LabelMatcherOptions, SeriesMatcherOptions, SeriesOverrideOptions } from '../time-series-chart-model';
+
+/**
+ * The overrides resolved for a series, every matching override merged in order.
+ */
+export type ResolvedSeriesOverride = Omit<SeriesOverrideOptions, 'matcher'>;
+
+const regexCache = new Map<string, RegExp | undefined>();
+
+// Regexes are fully anchored like in PromQL, so 'p9.*' doesn't match 'up95'.
+// An invalid regex matches nothing rather than breaking the panel.
+function getAnchoredRegex(pattern: string): RegExp | undefined {
+  if (!regexCache.has(pattern)) {
+    let regex: RegExp | undefined;
+    try {
+      regex = new RegExp(`^(?:${pattern})$`);
+    } catch {
+      regex = undefined;
+    }
+    regexCache.set(pattern, regex);
+  }
+  return regexCache.get(pattern);
+}
+
+function matchesLabel(matcher: LabelMatcherOptions, labels: Labels): boolean {
+  // A missing label is an empty label, as in PromQL.
+  const value = labels[matcher.name] ?? '';
+  switch (matcher.type ?? '=') {
+    case '=':
+      return value === matcher.value;
+    case '!=':
+      return value !== matcher.value;
+    case '=~':
+      return getAnchoredRegex(matcher.value)?.test(value) ?? false;
+    case '!~': {
+      const regex = getAnchoredRegex(matcher.value);
+      return regex !== undefined && !regex.test(value);
+    }
+    default:
+      return false;
+  }
+}
+
+/**
+ * Returns whether the series, identified by its (formatted) name and its labels, is selected by the matcher.
+ */
+export function matchesSeries(matcher: SeriesMatcherOptions, name: string, labels: Labels = {}): boolean {
+  if ('name' in matcher) {
+    return matcher.name === name;
+  }
+  if ('nameRegex' in matcher) {
+    return getAnchoredRegex(matcher.nameRegex)?.test(name) ?? false;
+  }
+  if ('labels' in matcher) {
+    return matcher.labels.length > 0 && matcher.labels.every((labelMatcher) => matchesLabel(labelMatcher, labels));
+  }
+  return false;
+}
+
+/**
+ * Merges, in order, the overrides matching the series. Returns undefined when none matches.
+ */
+export function getSeriesOverride(
+  overrides: SeriesOverrideOptions[] | undefined,
+  name: string,
+  labels?: Labels
+): ResolvedSeriesOverride | undefined {
+  let resolved: ResolvedSeriesOverride | undefined;
+  for (const { matcher, ...override } of overrides ?? []) {
+    if (matchesSeries(matcher, name, labels)) {
+      resolved = { ...resolved, ...override };
+    }
+  }
+  return resolved;
+}
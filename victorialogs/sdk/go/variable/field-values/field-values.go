@@ -16,10 +16,21 @@ package labelvalues
 import (
 	"github.com/perses/perses/go-sdk/datasource"
 	list_variable "github.com/perses/perses/go-sdk/variable/list-variable"
+	"github.com/perses/plugins/sdk/go/analysis"
+	"github.com/perses/plugins/sdk/go/option"
+	"github.com/perses/plugins/sdk/go/selector"
+	victorialogsDatasource "github.com/perses/plugins/victorialogs/sdk/go/datasource"
 )
 
 const PluginKind = "VictoriaLogsFieldValuesVariable"
 
+func init() {
+	selector.Register(PluginKind, victorialogsDatasource.PluginKind)
+	analysis.Register(PluginKind, analysis.Decode(func(spec PluginSpec) []analysis.Field {
+		return []analysis.Field{analysis.Datasource(spec.Datasource), {Path: "field", Value: spec.Field}, {Path: "query", Value: spec.Query}}
+	}))
+}
+
 type PluginSpec struct {
 	Datasource *datasource.Selector `json:"datasource,omitempty" yaml:"datasource,omitempty"`
 	Field      string               `json:"field" yaml:"field"`
@@ -37,10 +48,10 @@ func create(field string, options ...Option) (Builder, error) {
 		Field(field),
 	}
 
-	for _, opt := range append(defaults, options...) {
-		if err := opt(builder); err != nil {
-			return *builder, err
-		}
+	if err := option.ApplyAndValidate(PluginKind, builder, append(defaults, options...), func() error {
+		return selector.Check(PluginKind, builder.Datasource)
+	}); err != nil {
+		return *builder, err
 	}
 
 	return *builder, nil